@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/runner"
+)
+
+const (
+	FormatHuman = "human"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// output writes the results of a run, Start, Result and Finish are called while running
+// and Close once all the targets ran.
+type output interface {
+	Start(name string)
+	Result(res runner.Result)
+	Finish(report *runner.Report)
+	Close(reports []*runner.Report) error
+}
+
+func newOutput(format string, w io.Writer) (output, error) {
+	switch format {
+	case FormatHuman:
+		return &humanOutput{w: w}, nil
+	case FormatJSON:
+		return &jsonOutput{w: w}, nil
+	case FormatJUnit:
+		return &junitOutput{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %s, expected human, json or junit", format)
+	}
+}
+
+// humanOutput prints the results as they come, so long runs show their progress in ci logs.
+type humanOutput struct {
+	w         io.Writer
+	iteration int
+}
+
+func (h *humanOutput) Start(name string) {
+	h.iteration = 0
+	fmt.Fprintln(h.w, name)
+}
+
+func (h *humanOutput) Result(res runner.Result) {
+	if res.Iteration != h.iteration {
+		h.iteration = res.Iteration
+		fmt.Fprintf(h.w, "  %s\n", iterationName(res))
+	}
+
+	line := fmt.Sprintf("    %-4s %s", statusLabel(res.Status), res.Name)
+	if res.Method != "" {
+		line += fmt.Sprintf("  %s %s", res.Method, res.URL)
+	}
+	if res.StatusCode != 0 {
+		line += fmt.Sprintf("  %d  %s", res.StatusCode, res.Duration.Round(time.Millisecond))
+	}
+	if res.Message != "" {
+		line += "  " + res.Message
+	}
+	fmt.Fprintln(h.w, line)
+
+	for _, t := range res.TestResults {
+		if !t.Passed {
+			fmt.Fprintf(h.w, "         FAIL %s: %s\n", t.Assertion, t.Message)
+		}
+	}
+}
+
+func (h *humanOutput) Finish(report *runner.Report) {
+	summary := fmt.Sprintf("  %d passed, %d failed, %d skipped in %s",
+		report.Count(runner.StatusPassed), report.Count(runner.StatusFailed), report.Count(runner.StatusSkipped), report.Duration.Round(time.Millisecond))
+	if report.Stopped {
+		summary += ", stopped"
+	}
+	fmt.Fprintln(h.w, summary)
+	fmt.Fprintln(h.w)
+}
+
+func (h *humanOutput) Close([]*runner.Report) error {
+	return nil
+}
+
+func statusLabel(status string) string {
+	switch status {
+	case runner.StatusPassed:
+		return "PASS"
+	case runner.StatusFailed:
+		return "FAIL"
+	default:
+		return "SKIP"
+	}
+}
+
+// iterationName returns the iteration with its variables, e.g. Iteration 2, user=bob.
+func iterationName(res runner.Result) string {
+	name := fmt.Sprintf("Iteration %d", res.Iteration)
+	if len(res.Variables) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(res.Variables))
+	for k := range res.Variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+res.Variables[k])
+	}
+	return name + ", " + strings.Join(pairs, ", ")
+}
+
+type jsonOutput struct {
+	w io.Writer
+}
+
+type jsonRun struct {
+	Passed  bool         `json:"passed"`
+	Reports []jsonReport `json:"reports"`
+}
+
+type jsonReport struct {
+	Name         string       `json:"name"`
+	CollectionID string       `json:"collectionId,omitempty"`
+	Iterations   int          `json:"iterations"`
+	Started      time.Time    `json:"started"`
+	DurationMs   int64        `json:"durationMs"`
+	Passed       int          `json:"passed"`
+	Failed       int          `json:"failed"`
+	Skipped      int          `json:"skipped"`
+	Stopped      bool         `json:"stopped"`
+	Results      []jsonResult `json:"results"`
+}
+
+type jsonResult struct {
+	Iteration  int               `json:"iteration"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	RequestID  string            `json:"requestId"`
+	Name       string            `json:"name"`
+	Method     string            `json:"method,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Status     string            `json:"status"`
+	StatusCode int               `json:"statusCode,omitempty"`
+	DurationMs int64             `json:"durationMs"`
+	Message    string            `json:"message,omitempty"`
+	Tests      []jsonTest        `json:"tests,omitempty"`
+}
+
+type jsonTest struct {
+	Assertion string `json:"assertion"`
+	Passed    bool   `json:"passed"`
+	Message   string `json:"message,omitempty"`
+}
+
+func (j *jsonOutput) Start(string)          {}
+func (j *jsonOutput) Result(runner.Result)  {}
+func (j *jsonOutput) Finish(*runner.Report) {}
+
+func (j *jsonOutput) Close(reports []*runner.Report) error {
+	run := jsonRun{Passed: true, Reports: make([]jsonReport, 0, len(reports))}
+	for _, report := range reports {
+		out := jsonReport{
+			Name:         report.CollectionName,
+			CollectionID: report.CollectionID,
+			Iterations:   report.Iterations,
+			Started:      report.Started,
+			DurationMs:   report.Duration.Milliseconds(),
+			Passed:       report.Count(runner.StatusPassed),
+			Failed:       report.Count(runner.StatusFailed),
+			Skipped:      report.Count(runner.StatusSkipped),
+			Stopped:      report.Stopped,
+			Results:      make([]jsonResult, 0, len(report.Results)),
+		}
+
+		if out.Failed > 0 || out.Stopped {
+			run.Passed = false
+		}
+
+		for _, res := range report.Results {
+			r := jsonResult{
+				Iteration:  res.Iteration,
+				Variables:  res.Variables,
+				RequestID:  res.RequestID,
+				Name:       res.Name,
+				Method:     res.Method,
+				URL:        res.URL,
+				Status:     res.Status,
+				StatusCode: res.StatusCode,
+				DurationMs: res.Duration.Milliseconds(),
+				Message:    res.Message,
+			}
+			for _, t := range res.TestResults {
+				r.Tests = append(r.Tests, jsonTest{Assertion: t.Assertion.String(), Passed: t.Passed, Message: t.Message})
+			}
+			out.Results = append(out.Results, r)
+		}
+		run.Reports = append(run.Reports, out)
+	}
+
+	enc := json.NewEncoder(j.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(run)
+}
+
+// junitOutput writes a test suite per collection or request and a test case per request of every iteration.
+type junitOutput struct {
+	w io.Writer
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (j *junitOutput) Start(string)          {}
+func (j *junitOutput) Result(runner.Result)  {}
+func (j *junitOutput) Finish(*runner.Report) {}
+
+func (j *junitOutput) Close(reports []*runner.Report) error {
+	suites := junitTestSuites{Name: "chapar"}
+	var total time.Duration
+	for _, report := range reports {
+		suite := junitTestSuite{
+			Name:      report.CollectionName,
+			Tests:     len(report.Results),
+			Failures:  report.Count(runner.StatusFailed),
+			Skipped:   report.Count(runner.StatusSkipped),
+			Time:      seconds(report.Duration),
+			Timestamp: report.Started.Format(time.RFC3339),
+		}
+
+		for _, res := range report.Results {
+			name := res.Name
+			if report.Iterations > 1 {
+				name = fmt.Sprintf("%s (%s)", res.Name, iterationName(res))
+			}
+
+			tc := junitTestCase{Name: name, ClassName: report.CollectionName, Time: seconds(res.Duration)}
+			switch res.Status {
+			case runner.StatusFailed:
+				tc.Failure = &junitMessage{Message: res.Message, Text: failureText(res)}
+			case runner.StatusSkipped:
+				tc.Skipped = &junitMessage{Message: res.Message}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		total += report.Duration
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = seconds(total)
+
+	if _, err := io.WriteString(j.w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(j.w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n")
+	return err
+}
+
+// failureText returns the request and the failed tests, so ci tools show why the request failed.
+func failureText(res runner.Result) string {
+	lines := []string{fmt.Sprintf("%s %s", res.Method, res.URL)}
+	if res.StatusCode != 0 {
+		lines = append(lines, fmt.Sprintf("status %d", res.StatusCode))
+	}
+
+	for _, t := range res.TestResults {
+		if !t.Passed {
+			lines = append(lines, fmt.Sprintf("FAIL %s: %s", t.Assertion, t.Message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
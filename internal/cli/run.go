@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/runner"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+const (
+	ExitPassed = 0
+	// ExitFailed is returned when a request failed or the run was stopped
+	ExitFailed = 1
+	// ExitError is returned when the run could not start, like for invalid flags or unknown names
+	ExitError = 2
+)
+
+// variables is a flag which can be repeated, every value is a key=value pair.
+type variables map[string]string
+
+func (v variables) String() string {
+	pairs := make([]string, 0, len(v))
+	for k, val := range v {
+		pairs = append(pairs, k+"="+val)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v variables) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	v[strings.TrimSpace(key)] = val
+	return nil
+}
+
+// target is a collection or a single request to run.
+type target struct {
+	collection *domain.Collection
+	request    *domain.Request
+}
+
+// Run runs the collections and requests named in args without the ui and returns the exit code.
+// reports are written to stdout and errors to stderr.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: chapar run [flags] <collection | request | collection/request>...")
+		fmt.Fprintln(stderr, "\nRuns collections and requests of a workspace and exits with 1 when one of them fails.")
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	vars := variables{}
+	workspace := fs.String("workspace", "", "name or directory of the workspace, the active workspace is used when empty")
+	envName := fs.String("env", "", "name of the environment")
+	fs.Var(vars, "var", "variable as key=value which overrides the environment and data file, can be repeated")
+	iterations := fs.Int("iterations", 1, "number of iterations, ignored when a data file is given")
+	dataFile := fs.String("data", "", "csv or json file whose rows are the variables of the iterations")
+	delay := fs.Duration("delay", 0, "delay between requests")
+	bail := fs.Bool("bail", false, "stop at the first failure")
+	output := fs.String("output", FormatHuman, "output format, human, json or junit")
+	persist := fs.Bool("persist", false, "write the variables and cookies requests set to the workspace, they are only kept for the run by default")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitPassed
+		}
+		return ExitError
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "no collection or request to run")
+		fs.Usage()
+		return ExitError
+	}
+
+	out, err := newOutput(*output, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+
+	repo, err := openWorkspace(*workspace)
+	if err != nil {
+		fmt.Fprintln(stderr, "failed to open workspace:", err)
+		return ExitError
+	}
+
+	requests := state.NewRequests(repo)
+	environments := state.NewEnvironments(repo)
+
+	// runs start without the cookies of the workspace and do not change it, so ci runs do not depend on each other
+	cookies := state.NewCookies(nil)
+	if *persist {
+		cookies = state.NewCookies(repo)
+	}
+
+	restService := rest.New(requests, environments, cookies)
+	restService.SetStateOnly(!*persist)
+	// tunnels are shared between the requests of the run and closed once it is done
+	defer restService.Tunnels().CloseAll()
+
+	if err := load(repo, requests, environments, restService); err != nil {
+		fmt.Fprintln(stderr, "failed to load workspace:", err)
+		return ExitError
+	}
+
+	opts := runner.Options{
+		Iterations:    *iterations,
+		DataFile:      *dataFile,
+		Variables:     vars,
+		Delay:         *delay,
+		StopOnFailure: *bail,
+	}
+
+	if *envName != "" {
+		env := findEnvironment(environments, *envName)
+		if env == nil {
+			fmt.Fprintf(stderr, "environment %s not found\n", *envName)
+			return ExitError
+		}
+		opts.EnvironmentID = env.MetaData.ID
+	}
+
+	targets := make([]target, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		t, err := findTarget(requests, name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitError
+		}
+		targets = append(targets, t)
+	}
+
+	r := runner.New(requests, restService)
+	reports := make([]*runner.Report, 0, len(targets))
+	for _, t := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var report *runner.Report
+		if t.collection != nil {
+			out.Start(t.collection.MetaData.Name)
+			report, err = r.Run(ctx, t.collection.MetaData.ID, opts, out.Result)
+		} else {
+			out.Start(t.request.MetaData.Name)
+			report, err = r.RunRequest(ctx, t.request.MetaData.ID, opts, out.Result)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitError
+		}
+
+		if report.CollectionName == "" {
+			report.CollectionName = t.request.MetaData.Name
+		}
+		reports = append(reports, report)
+		out.Finish(report)
+
+		if report.Stopped && *bail {
+			break
+		}
+	}
+
+	if err := out.Close(reports); err != nil {
+		fmt.Fprintln(stderr, "failed to write report:", err)
+		return ExitError
+	}
+
+	if ctx.Err() != nil {
+		return ExitFailed
+	}
+	for _, report := range reports {
+		if report.Stopped || report.Count(runner.StatusFailed) > 0 {
+			return ExitFailed
+		}
+	}
+	return ExitPassed
+}
+
+// openWorkspace opens the workspace with the given directory or name, or the active workspace when it is empty.
+func openWorkspace(workspace string) (*repository.Filesystem, error) {
+	if workspace == "" {
+		return repository.NewFilesystem()
+	}
+
+	if info, err := os.Stat(workspace); err == nil && info.IsDir() {
+		return repository.NewFilesystemFromDir(workspace)
+	}
+
+	// the workspace is selected for this run only, so the active workspace of the ui does not change
+	repo := &repository.Filesystem{}
+	workspaces, err := repo.LoadWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ws := range workspaces {
+		if ws.MetaData.Name == workspace {
+			repo.ActiveWorkspace = ws
+			return repo, nil
+		}
+	}
+	return nil, fmt.Errorf("workspace %s not found", workspace)
+}
+
+func load(repo *repository.Filesystem, requests *state.Requests, environments *state.Environments, restService *rest.Service) error {
+	preferences, err := repo.ReadPreferencesData()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if preferences != nil {
+		restService.SetHTTPClientSettings(preferences.Spec.HTTPClient)
+	}
+
+	if _, err := environments.LoadEnvironmentsFromDisk(); err != nil {
+		return err
+	}
+
+	if _, err := requests.LoadCollectionsFromDisk(); err != nil {
+		return err
+	}
+
+	_, err = requests.LoadRequestsFromDisk()
+	return err
+}
+
+func findEnvironment(environments *state.Environments, name string) *domain.Environment {
+	for _, env := range environments.GetEnvironments() {
+		if env.MetaData.Name == name || env.MetaData.ID == name {
+			return env
+		}
+	}
+	return nil
+}
+
+// findTarget finds the collection or request with the given name or id,
+// requests of a collection are named as collection/request.
+func findTarget(requests *state.Requests, name string) (target, error) {
+	var found []target
+	for _, col := range requests.GetCollections() {
+		if col.MetaData.Name == name || col.MetaData.ID == name {
+			found = append(found, target{collection: col})
+		}
+	}
+
+	for _, req := range requests.GetRequests() {
+		fullName := req.MetaData.Name
+		if req.CollectionID != "" {
+			fullName = req.CollectionName + "/" + req.MetaData.Name
+		}
+
+		if fullName == name || req.MetaData.ID == name {
+			found = append(found, target{request: req})
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return target{}, fmt.Errorf("collection or request %s not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return target{}, fmt.Errorf("%s matches %d collections or requests, use its id instead", name, len(found))
+	}
+}
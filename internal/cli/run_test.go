@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+)
+
+func newWorkspace(t *testing.T, url string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := repository.NewFilesystemFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.SetKey("baseUrl", url)
+	env.SetKey("user", "alice")
+	envPath, err := repo.GetNewEnvironmentFilePath(env.MetaData.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.FilePath = envPath.Path
+	if err := repo.UpdateEnvironment(env); err != nil {
+		t.Fatal(err)
+	}
+
+	collection := domain.NewCollection("users")
+	colPath, err := repo.GetNewCollectionDir(collection.MetaData.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.FilePath = colPath.Path
+	if err := repo.UpdateCollection(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	get := domain.NewRequest("get")
+	get.Spec.HTTP.URL = "{{baseUrl}}/users/{{user}}"
+	get.Spec.HTTP.Request.Tests = []domain.Assertion{
+		{Enable: true, Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorEquals, Value: "200"},
+	}
+	get.FilePath = filepath.Join(filepath.Dir(collection.FilePath), "get.yaml")
+	if err := repo.UpdateRequest(get); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := domain.NewRequest("missing")
+	missing.Spec.HTTP.URL = "{{baseUrl}}/missing"
+	if err := repo.UpdateRequest(missing); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/alice" {
+			_, _ = w.Write([]byte(`{"name": "alice"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	dir := newWorkspace(t, srv.URL)
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := Run(context.Background(), append([]string{"-workspace", dir, "-env", "staging"}, args...), &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, stdout, stderr := run("users")
+	if code != ExitPassed {
+		t.Fatalf("expected the collection to pass, got %d, %s%s", code, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "PASS get") || !strings.Contains(stdout, "1 passed, 0 failed") {
+		t.Errorf("unexpected output %s", stdout)
+	}
+
+	// a variable overrides the environment
+	code, stdout, _ = run("-var", "user=bob", "-output", "json", "users/get")
+	if code != ExitFailed {
+		t.Errorf("expected the request to fail for another user, got %d", code)
+	}
+
+	var report jsonRun
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid json output: %v, %s", err, stdout)
+	}
+	if report.Passed || len(report.Reports) != 1 || report.Reports[0].Results[0].URL != "{{baseUrl}}/users/{{user}}" || report.Reports[0].Results[0].StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	code, stdout, _ = run("-output", "junit", "users", "missing")
+	if code != ExitFailed {
+		t.Errorf("expected the missing request to fail, got %d", code)
+	}
+	if !strings.Contains(stdout, `<testsuites name="chapar" tests="2" failures="1" skipped="0"`) || !strings.Contains(stdout, `<failure message="status is 404 Not Found">`) {
+		t.Errorf("unexpected junit output %s", stdout)
+	}
+
+	for _, args := range [][]string{
+		{"unknown"},
+		{"-env", "production", "users"},
+		{"-output", "yaml", "users"},
+		{},
+	} {
+		if code, _, _ := run(args...); code != ExitError {
+			t.Errorf("expected an error for %v, got %d", args, code)
+		}
+	}
+}
+
+func TestRun_Persist(t *testing.T) {
+	var sentCookie bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := r.Cookie("session")
+		sentCookie = err == nil
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token": "t-1"}`))
+	}))
+	defer srv.Close()
+
+	dir := newWorkspace(t, srv.URL)
+	repo, err := repository.NewFilesystemFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login := domain.NewRequest("login")
+	login.Spec.HTTP.URL = "{{baseUrl}}/login"
+	login.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypeSetEnv,
+		PostRequestSet: domain.PostRequestSet{
+			Target:     "token",
+			StatusCode: "200",
+			From:       domain.PostRequestSetFromResponseBody,
+			FromKey:    "$.token",
+		},
+	}
+	if err := repo.UpdateRequest(login); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := Run(context.Background(), append([]string{"-workspace", dir, "-env", "staging"}, args...), &stdout, &stderr); code != ExitPassed {
+			t.Fatalf("expected the run to pass, got %d, %s%s", code, stdout.String(), stderr.String())
+		}
+	}
+
+	storedToken := func() string {
+		envs, err := repo.LoadEnvironments()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, kv := range envs[0].Spec.Values {
+			if kv.Key == "token" {
+				return kv.Value
+			}
+		}
+		return ""
+	}
+
+	// runs do not change the workspace and do not share cookies
+	run("login")
+	run("login")
+	if sentCookie {
+		t.Errorf("expected the cookie of the previous run not to be sent")
+	}
+
+	if token := storedToken(); token != "" {
+		t.Errorf("expected the environment on disk not to change, got token %q", token)
+	}
+
+	run("-persist", "login")
+	run("-persist", "login")
+	if !sentCookie {
+		t.Errorf("expected the persisted cookie to be sent")
+	}
+
+	if token := storedToken(); token != "t-1" {
+		t.Errorf("expected the token to be written to the environment, got %q", token)
+	}
+}
@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"strings"
+)
+
+const (
+	OpEqual  = "equal"
+	OpDelete = "delete"
+	OpInsert = "insert"
+	// OpChange is used by side by side rows when a deleted line is shown next to the line which replaced it
+	OpChange = "change"
+)
+
+// maxCells bounds the size of the table of the longest common subsequence, the lines of larger texts
+// which are not in their common prefix and suffix are reported as deleted and inserted as a whole.
+const maxCells = 4_000_000
+
+// Line is a line of a unified diff.
+type Line struct {
+	Op   string
+	Text string
+}
+
+// Row is a row of a side by side diff, Left is empty for inserted lines and Right for deleted ones.
+type Row struct {
+	Op    string
+	Left  string
+	Right string
+}
+
+// Lines returns the lines of a and b as deleted, inserted or equal lines, turning a into b.
+func Lines(a, b string) []Line {
+	al, bl := splitLines(a), splitLines(b)
+
+	// the common prefix and suffix are trimmed, so most diffs only compare a few lines
+	prefix := 0
+	for prefix < len(al) && prefix < len(bl) && al[prefix] == bl[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(al)-prefix && suffix < len(bl)-prefix && al[len(al)-1-suffix] == bl[len(bl)-1-suffix] {
+		suffix++
+	}
+
+	out := make([]Line, 0, len(al)+len(bl))
+	for _, l := range al[:prefix] {
+		out = append(out, Line{Op: OpEqual, Text: l})
+	}
+
+	out = append(out, middle(al[prefix:len(al)-suffix], bl[prefix:len(bl)-suffix])...)
+
+	for _, l := range al[len(al)-suffix:] {
+		out = append(out, Line{Op: OpEqual, Text: l})
+	}
+
+	return out
+}
+
+// middle diffs the lines using their longest common subsequence.
+func middle(a, b []string) []Line {
+	out := make([]Line, 0, len(a)+len(b))
+
+	if len(a)*len(b) > maxCells {
+		for _, l := range a {
+			out = append(out, Line{Op: OpDelete, Text: l})
+		}
+		for _, l := range b {
+			out = append(out, Line{Op: OpInsert, Text: l})
+		}
+		return out
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			out = append(out, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		out = append(out, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, Line{Op: OpInsert, Text: b[j]})
+	}
+
+	return out
+}
+
+// SideBySide returns the diff of a and b as rows, deleted lines are paired with the inserted lines which follow them.
+func SideBySide(a, b string) []Row {
+	lines := Lines(a, b)
+	rows := make([]Row, 0, len(lines))
+
+	for i := 0; i < len(lines); {
+		if lines[i].Op == OpEqual {
+			rows = append(rows, Row{Op: OpEqual, Left: lines[i].Text, Right: lines[i].Text})
+			i++
+			continue
+		}
+
+		var deleted, inserted []string
+		for ; i < len(lines) && lines[i].Op == OpDelete; i++ {
+			deleted = append(deleted, lines[i].Text)
+		}
+		for ; i < len(lines) && lines[i].Op == OpInsert; i++ {
+			inserted = append(inserted, lines[i].Text)
+		}
+
+		for k := 0; k < max(len(deleted), len(inserted)); k++ {
+			switch {
+			case k < len(deleted) && k < len(inserted):
+				rows = append(rows, Row{Op: OpChange, Left: deleted[k], Right: inserted[k]})
+			case k < len(deleted):
+				rows = append(rows, Row{Op: OpDelete, Left: deleted[k]})
+			default:
+				rows = append(rows, Row{Op: OpInsert, Right: inserted[k]})
+			}
+		}
+	}
+
+	return rows
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n"), "\n")
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected []Line
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb",
+			expected: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+			},
+		},
+		{
+			name: "changed line",
+			a:    "{\n  \"id\": 1,\n  \"name\": \"a\"\n}",
+			b:    "{\n  \"id\": 2,\n  \"name\": \"a\"\n}",
+			expected: []Line{
+				{Op: OpEqual, Text: "{"},
+				{Op: OpDelete, Text: `  "id": 1,`},
+				{Op: OpInsert, Text: `  "id": 2,`},
+				{Op: OpEqual, Text: `  "name": "a"`},
+				{Op: OpEqual, Text: "}"},
+			},
+		},
+		{
+			name: "inserted and deleted lines",
+			a:    "a\nb\nc\nd",
+			b:    "a\nc\nd\ne",
+			expected: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpEqual, Text: "d"},
+				{Op: OpInsert, Text: "e"},
+			},
+		},
+		{
+			name:     "empty",
+			a:        "",
+			b:        "a",
+			expected: []Line{{Op: OpInsert, Text: "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v but got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	got := SideBySide("a\nb\nc\nd", "a\nx\nd\ny")
+	expected := []Row{
+		{Op: OpEqual, Left: "a", Right: "a"},
+		{Op: OpChange, Left: "b", Right: "x"},
+		{Op: OpDelete, Left: "c"},
+		{Op: OpEqual, Left: "d", Right: "d"},
+		{Op: OpInsert, Right: "y"},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v but got %+v", expected, got)
+	}
+}
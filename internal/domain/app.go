@@ -1,5 +1,7 @@
 package domain
 
+import "github.com/google/uuid"
+
 const (
 	ApiVersion = "v1"
 
@@ -10,6 +12,9 @@ const (
 	KindPreferences   = "Preferences"
 	KindCollection    = "Collection"
 	KindProtoFileList = "ProtoFileList"
+	KindCookieJar     = "CookieJar"
+	KindHistory       = "ResponseHistory"
+	KindGlobals       = "Globals"
 )
 
 type MetaData struct {
@@ -39,6 +44,23 @@ func CompareKeyValues(a, b []KeyValue) bool {
 	return true
 }
 
+// SetKeyValue sets the value of the key, a new enabled key is appended when it does not exist.
+func SetKeyValue(values []KeyValue, key, value string) []KeyValue {
+	for i, v := range values {
+		if v.Key == key {
+			values[i].Value = value
+			return values
+		}
+	}
+
+	return append(values, KeyValue{
+		ID:     uuid.NewString(),
+		Key:    key,
+		Value:  value,
+		Enable: true,
+	})
+}
+
 func KeyValuesToText(values []KeyValue) string {
 	var text string
 	for _, v := range values {
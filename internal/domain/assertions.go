@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// AssertionTypeStatus checks the status code, Value is a code or a range like 200-299
+	AssertionTypeStatus = "status"
+	// AssertionTypeJSONPath checks the value at the JSONPath expression in Target
+	AssertionTypeJSONPath = "jsonPath"
+	// AssertionTypeHeader checks the header named in Target
+	AssertionTypeHeader       = "header"
+	AssertionTypeBodyContains = "bodyContains"
+	// AssertionTypeLatency checks the response arrived in less than Value milliseconds
+	AssertionTypeLatency = "latency"
+
+	AssertionOperatorEquals   = "equals"
+	AssertionOperatorInRange  = "inRange"
+	AssertionOperatorMatches  = "matches"
+	AssertionOperatorExists   = "exists"
+	AssertionOperatorContains = "contains"
+	AssertionOperatorLessThan = "lessThan"
+)
+
+// Assertion is a check run against the response after every send.
+type Assertion struct {
+	ID     string `yaml:"id"`
+	Enable bool   `yaml:"enable"`
+	Type   string `yaml:"type"`
+	// Target is the JSONPath expression or the header name, depending on the type
+	Target   string `yaml:"target,omitempty"`
+	Operator string `yaml:"operator"`
+	Value    string `yaml:"value,omitempty"`
+}
+
+// AssertionResult is the outcome of an assertion, Message explains it, like the actual value when it failed.
+type AssertionResult struct {
+	Assertion Assertion
+	Passed    bool
+	Message   string
+}
+
+func NewAssertion() Assertion {
+	return Assertion{
+		ID:       uuid.NewString(),
+		Enable:   true,
+		Type:     AssertionTypeStatus,
+		Operator: AssertionOperatorEquals,
+		Value:    "200",
+	}
+}
+
+// AssertionOperators returns the operators supported by the assertion type, the first one is the default.
+func AssertionOperators(assertionType string) []string {
+	switch assertionType {
+	case AssertionTypeStatus:
+		return []string{AssertionOperatorEquals, AssertionOperatorInRange}
+	case AssertionTypeJSONPath:
+		return []string{AssertionOperatorEquals, AssertionOperatorMatches, AssertionOperatorExists}
+	case AssertionTypeHeader:
+		return []string{AssertionOperatorExists, AssertionOperatorEquals, AssertionOperatorMatches}
+	case AssertionTypeBodyContains:
+		return []string{AssertionOperatorContains}
+	case AssertionTypeLatency:
+		return []string{AssertionOperatorLessThan}
+	}
+	return nil
+}
+
+// HasTarget reports whether the assertion type checks a JSONPath expression or a header.
+func (a Assertion) HasTarget() bool {
+	return a.Type == AssertionTypeJSONPath || a.Type == AssertionTypeHeader
+}
+
+// String describes the assertion, e.g. status inRange 200-299.
+func (a Assertion) String() string {
+	parts := []string{a.Type}
+	switch a.Type {
+	case AssertionTypeBodyContains:
+		parts = []string{"body contains"}
+	case AssertionTypeLatency:
+		parts = []string{"latency lessThan"}
+	default:
+		if a.HasTarget() {
+			parts = append(parts, a.Target)
+		}
+		parts = append(parts, a.Operator)
+	}
+
+	if a.Operator != AssertionOperatorExists {
+		value := a.Value
+		if a.Type == AssertionTypeLatency {
+			value += " ms"
+		}
+		parts = append(parts, fmt.Sprintf("%q", value))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func CompareAssertions(a, b []Assertion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -12,7 +12,9 @@ type Collection struct {
 }
 
 type ColSpec struct {
-	Requests []*Request `yaml:"requests"`
+	// Variables are shared by the requests of the collection, the active environment overrides them
+	Variables []KeyValue `yaml:"variables,omitempty"`
+	Requests  []*Request `yaml:"requests"`
 }
 
 func (c *Collection) Clone() *Collection {
@@ -24,11 +26,14 @@ func (c *Collection) Clone() *Collection {
 			Name: c.MetaData.Name,
 		},
 		Spec: ColSpec{
-			Requests: make([]*Request, len(c.Spec.Requests)),
+			Variables: make([]KeyValue, len(c.Spec.Variables)),
+			Requests:  make([]*Request, len(c.Spec.Requests)),
 		},
 		FilePath: c.FilePath,
 	}
 
+	copy(clone.Spec.Variables, c.Spec.Variables)
+
 	for i, v := range c.Spec.Requests {
 		clone.Spec.Requests[i] = v
 	}
@@ -51,6 +56,10 @@ func NewCollection(name string) *Collection {
 	}
 }
 
+func (c *Collection) SetKey(key, value string) {
+	c.Spec.Variables = SetKeyValue(c.Spec.Variables, key, value)
+}
+
 func (c *Collection) AddRequest(req *Request) {
 	c.Spec.Requests = append(c.Spec.Requests, req)
 }
@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// DefaultCookieJarName is the name of the jar used when no environment is selected.
+const DefaultCookieJarName = "default"
+
+// CookieJar holds the cookies of a single environment, they are stored in the workspace
+// so sessions survive restarts.
+type CookieJar struct {
+	ApiVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	MetaData   MetaData      `yaml:"metadata"`
+	Spec       CookieJarSpec `yaml:"spec"`
+}
+
+type CookieJarSpec struct {
+	Cookies []Cookie `yaml:"cookies"`
+}
+
+type Cookie struct {
+	Name   string `yaml:"name"`
+	Value  string `yaml:"value"`
+	Domain string `yaml:"domain"`
+	Path   string `yaml:"path"`
+	// Expires is zero for session cookies
+	Expires time.Time `yaml:"expires,omitempty"`
+	// HostOnly cookies are only sent to the exact host which set them and not to its subdomains
+	HostOnly bool   `yaml:"hostOnly,omitempty"`
+	Secure   bool   `yaml:"secure,omitempty"`
+	HttpOnly bool   `yaml:"httpOnly,omitempty"`
+	SameSite string `yaml:"sameSite,omitempty"`
+}
+
+func NewCookieJar(name string) *CookieJar {
+	return &CookieJar{
+		ApiVersion: ApiVersion,
+		Kind:       KindCookieJar,
+		MetaData: MetaData{
+			ID:   name,
+			Name: name,
+		},
+		Spec: CookieJarSpec{
+			Cookies: make([]Cookie, 0),
+		},
+	}
+}
+
+func (j *CookieJar) Clone() *CookieJar {
+	clone := *j
+	clone.Spec.Cookies = make([]Cookie, len(j.Spec.Cookies))
+	copy(clone.Spec.Cookies, j.Spec.Cookies)
+	return &clone
+}
+
+// NewCookieFromHTTP converts a cookie received from u into a stored cookie, filling the defaults of
+// the domain, path and expiry the same way browsers do. cookies for a domain the host of u does not belong to,
+// or for a public suffix like co.uk, are rejected as described in RFC 6265 section 5.3.
+func NewCookieFromHTTP(u *url.URL, c *http.Cookie, now time.Time) (Cookie, error) {
+	cookie := Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+		SameSite: sameSiteToString(c.SameSite),
+	}
+
+	host := strings.ToLower(u.Hostname())
+	domain, hostOnly, err := cookieDomain(host, c.Domain)
+	if err != nil {
+		return Cookie{}, fmt.Errorf("cookie %s from %s: %w", c.Name, host, err)
+	}
+	cookie.Domain, cookie.HostOnly = domain, hostOnly
+
+	if cookie.Path == "" || !strings.HasPrefix(cookie.Path, "/") {
+		cookie.Path = defaultCookiePath(u.Path)
+	}
+
+	switch {
+	case c.MaxAge < 0:
+		// MaxAge<0 means delete the cookie now
+		cookie.Expires = time.Unix(1, 0)
+	case c.MaxAge > 0:
+		cookie.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+	case !c.Expires.IsZero():
+		cookie.Expires = c.Expires
+	}
+
+	return cookie, nil
+}
+
+// cookieDomain returns the domain the cookie is stored for and whether it is only sent to host itself.
+func cookieDomain(host, attribute string) (string, bool, error) {
+	domain := strings.TrimPrefix(strings.ToLower(attribute), ".")
+	if domain == "" || domain == host {
+		return host, domain == "", nil
+	}
+
+	if net.ParseIP(host) != nil {
+		return "", false, fmt.Errorf("domain %s does not match the ip address", domain)
+	}
+
+	if !strings.HasSuffix(host, "."+domain) {
+		return "", false, fmt.Errorf("domain %s does not match the host", domain)
+	}
+
+	// a cookie for a public suffix would be sent to every site under it
+	if suffix, _ := publicsuffix.PublicSuffix(domain); suffix == domain {
+		return "", false, fmt.Errorf("domain %s is a public suffix", domain)
+	}
+	return domain, false, nil
+}
+
+// defaultCookiePath returns the directory of the request path as described in RFC 6265 section 5.1.4.
+func defaultCookiePath(path string) string {
+	if path == "" || path[0] != '/' {
+		return "/"
+	}
+
+	i := strings.LastIndex(path, "/")
+	if i == 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+func sameSiteToString(s http.SameSite) string {
+	switch s {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+	return ""
+}
+
+func (c Cookie) Expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// Matches reports whether the cookie should be sent with a request to u.
+func (c Cookie) Matches(u *url.URL, now time.Time) bool {
+	if c.Expired(now) {
+		return false
+	}
+
+	if c.Secure && u.Scheme != "https" && u.Scheme != "wss" {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if c.HostOnly || net.ParseIP(host) != nil {
+		if host != c.Domain {
+			return false
+		}
+	} else if host != c.Domain && !strings.HasSuffix(host, "."+c.Domain) {
+		return false
+	}
+
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	if path == c.Path {
+		return true
+	}
+
+	if !strings.HasPrefix(path, c.Path) {
+		return false
+	}
+
+	return strings.HasSuffix(c.Path, "/") || path[len(c.Path)] == '/'
+}
+
+// ToHTTP converts the cookie into the form sent with requests.
+func (c Cookie) ToHTTP() *http.Cookie {
+	return &http.Cookie{Name: c.Name, Value: c.Value}
+}
+
+// SameCookie reports whether both cookies have the same identity, a newer one replaces the older one.
+func SameCookie(a, b Cookie) bool {
+	return a.Name == b.Name && a.Domain == b.Domain && a.Path == b.Path
+}
+
+// Set adds the cookie to the jar replacing the one with the same identity, expired cookies are removed instead.
+func (s *CookieJarSpec) Set(cookie Cookie, now time.Time) {
+	for i, c := range s.Cookies {
+		if !SameCookie(c, cookie) {
+			continue
+		}
+
+		if cookie.Expired(now) {
+			s.Cookies = append(s.Cookies[:i], s.Cookies[i+1:]...)
+		} else {
+			s.Cookies[i] = cookie
+		}
+		return
+	}
+
+	if !cookie.Expired(now) {
+		s.Cookies = append(s.Cookies, cookie)
+	}
+}
+
+// RemoveExpired drops the cookies which are expired and reports whether any cookie was removed.
+func (s *CookieJarSpec) RemoveExpired(now time.Time) bool {
+	cookies := make([]Cookie, 0, len(s.Cookies))
+	for _, c := range s.Cookies {
+		if !c.Expired(now) {
+			cookies = append(cookies, c)
+		}
+	}
+
+	removed := len(cookies) != len(s.Cookies)
+	s.Cookies = cookies
+	return removed
+}
+
+// CookieJarName returns the name of the jar of the given environment.
+func CookieJarName(environmentID string) string {
+	if environmentID == "" {
+		return DefaultCookieJarName
+	}
+	return environmentID
+}
@@ -14,6 +14,9 @@ type Environment struct {
 
 type EnvSpec struct {
 	Values []KeyValue `yaml:"values"`
+
+	// Proxy overrides the workspace proxy settings when the environment is active
+	Proxy *ProxySettings `yaml:"proxy,omitempty"`
 }
 
 func (e *EnvSpec) Clone() EnvSpec {
@@ -21,6 +24,10 @@ func (e *EnvSpec) Clone() EnvSpec {
 		Values: make([]KeyValue, len(e.Values)),
 	}
 
+	if e.Proxy != nil {
+		clone.Proxy = e.Proxy.Clone()
+	}
+
 	for i, v := range e.Values {
 		clone.Values[i] = KeyValue{
 			ID:     uuid.NewString(),
@@ -48,6 +55,10 @@ func NewEnvironment(name string) *Environment {
 	}
 }
 
+func CompareEnvSpecs(a, b EnvSpec) bool {
+	return CompareKeyValues(a.Values, b.Values) && CompareProxySettings(a.Proxy, b.Proxy)
+}
+
 func CompareEnvValue(a, b KeyValue) bool {
 	// compare length of the values
 	if len(a.Key) != len(b.Key) || len(a.Value) != len(b.Value) || len(a.ID) != len(b.ID) {
@@ -77,17 +88,5 @@ func (e *Environment) Clone() *Environment {
 }
 
 func (e *Environment) SetKey(key string, value string) {
-	for i, v := range e.Spec.Values {
-		if v.Key == key {
-			e.Spec.Values[i].Value = value
-			return
-		}
-	}
-
-	e.Spec.Values = append(e.Spec.Values, KeyValue{
-		ID:     uuid.NewString(),
-		Key:    key,
-		Value:  value,
-		Enable: true,
-	})
+	e.Spec.Values = SetKeyValue(e.Spec.Values, key, value)
 }
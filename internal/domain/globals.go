@@ -0,0 +1,41 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Globals are the variables of a workspace which are available whichever environment is active,
+// the collection and environment variables override them.
+type Globals struct {
+	ApiVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	MetaData   MetaData    `yaml:"metadata"`
+	Spec       GlobalsSpec `yaml:"spec"`
+}
+
+type GlobalsSpec struct {
+	Values []KeyValue `yaml:"values"`
+}
+
+func NewGlobals() *Globals {
+	return &Globals{
+		ApiVersion: ApiVersion,
+		Kind:       KindGlobals,
+		MetaData: MetaData{
+			ID:   uuid.NewString(),
+			Name: "Globals",
+		},
+		Spec: GlobalsSpec{
+			Values: make([]KeyValue, 0),
+		},
+	}
+}
+
+func (g *Globals) Clone() *Globals {
+	clone := *g
+	clone.Spec.Values = make([]KeyValue, len(g.Spec.Values))
+	copy(clone.Spec.Values, g.Spec.Values)
+	return &clone
+}
+
+func (g *Globals) SetKey(key, value string) {
+	g.Spec.Values = SetKeyValue(g.Spec.Values, key, value)
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// GraphQLRequestSpec is a GraphQL operation sent over HTTP as a POST request with a JSON body.
+type GraphQLRequestSpec struct {
+	URL string `yaml:"url"`
+
+	Query string `yaml:"query"`
+	// Variables is a JSON object with the values of the operation variables
+	Variables     string `yaml:"variables,omitempty"`
+	OperationName string `yaml:"operationName,omitempty"`
+
+	Headers []KeyValue  `yaml:"headers"`
+	Auth    Auth        `yaml:"auth"`
+	Tests   []Assertion `yaml:"tests,omitempty"`
+
+	LastUsedEnvironment LastUsedEnvironment `yaml:"lastUsedEnvironment"`
+
+	// Settings overrides the workspace http client settings for this request
+	Settings *HTTPClientSettings `yaml:"settings,omitempty"`
+}
+
+func NewGraphQLRequest(name string) *Request {
+	return &Request{
+		ApiVersion: ApiVersion,
+		Kind:       KindRequest,
+		MetaData: RequestMeta{
+			ID:   uuid.NewString(),
+			Name: name,
+			Type: RequestTypeGraphQL,
+		},
+		Spec: RequestSpec{
+			GraphQL: &GraphQLRequestSpec{
+				URL:   "https://example.com/graphql",
+				Query: "query {\n  \n}",
+				Headers: []KeyValue{
+					{ID: uuid.NewString(), Key: "Content-Type", Value: "application/json", Enable: true},
+				},
+				Auth: Auth{Type: AuthTypeNone},
+			},
+		},
+	}
+}
+
+func (g *GraphQLRequestSpec) Clone() *GraphQLRequestSpec {
+	clone := *g
+
+	if g.Headers != nil {
+		clone.Headers = make([]KeyValue, len(g.Headers))
+		copy(clone.Headers, g.Headers)
+	}
+
+	if g.Auth != (Auth{}) {
+		clone.Auth = g.Auth.Clone()
+	}
+
+	if g.Tests != nil {
+		clone.Tests = make([]Assertion, len(g.Tests))
+		copy(clone.Tests, g.Tests)
+	}
+
+	if g.Settings != nil {
+		clone.Settings = g.Settings.Clone()
+	}
+
+	return &clone
+}
+
+// ToHTTPRequestSpec returns the http request carrying the operation, so it can be sent like any rest request.
+// the variables of the environment must be replaced in the query and the variables before, as they are encoded in the body.
+func (g *GraphQLRequestSpec) ToHTTPRequestSpec() (*HTTPRequestSpec, error) {
+	payload := struct {
+		Query         string          `json:"query"`
+		Variables     json.RawMessage `json:"variables,omitempty"`
+		OperationName string          `json:"operationName,omitempty"`
+	}{
+		Query:         g.Query,
+		OperationName: g.OperationName,
+	}
+
+	// the variables must be resolved already, so the values of the environment are checked as part of the json
+	if variables := strings.TrimSpace(g.Variables); variables != "" {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(variables), &object); err != nil || object == nil {
+			return nil, fmt.Errorf("variables are not a valid json object")
+		}
+		payload.Variables = json.RawMessage(variables)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]KeyValue, 0, len(g.Headers)+1)
+	hasContentType := false
+	for _, h := range g.Headers {
+		if h.Enable && strings.EqualFold(h.Key, "Content-Type") {
+			hasContentType = true
+		}
+		headers = append(headers, h)
+	}
+
+	if !hasContentType {
+		headers = append(headers, KeyValue{Key: "Content-Type", Value: "application/json", Enable: true})
+	}
+
+	return &HTTPRequestSpec{
+		Method:   RequestMethodPOST,
+		URL:      g.URL,
+		Settings: g.Settings,
+		Request: &HTTPRequest{
+			Headers: headers,
+			Body: Body{
+				Type: BodyTypeJSON,
+				Data: string(body),
+			},
+			Auth:  g.Auth,
+			Tests: g.Tests,
+		},
+	}, nil
+}
+
+func CompareGraphQLRequestSpecs(a, b *GraphQLRequestSpec) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	if a.URL != b.URL || a.Query != b.Query || a.Variables != b.Variables || a.OperationName != b.OperationName {
+		return false
+	}
+
+	if !CompareKeyValues(a.Headers, b.Headers) || !CompareAuth(a.Auth, b.Auth) || !CompareAssertions(a.Tests, b.Tests) {
+		return false
+	}
+
+	return CompareHTTPClientSettings(a.Settings, b.Settings)
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	GRPCTransportPlaintext = "plaintext"
+	GRPCTransportTLS       = "tls"
+	// GRPCTransportMTLS is TLS with a client certificate
+	GRPCTransportMTLS = "mtls"
+)
+
+type GRPCRequestSpec struct {
+	Host string `yaml:"host"`
+	// Method is the full name of the method, like package.Service/Method
+	Method string `yaml:"method"`
+	// Body is the request message in JSON
+	Body string `yaml:"body"`
+
+	LastUsedEnvironment LastUsedEnvironment `yaml:"lastUsedEnvironment"`
+
+	// ServerReflection discovers the services using server reflection, the proto files of the workspace are used otherwise
+	ServerReflection bool `yaml:"serverReflection"`
+
+	// Metadata is sent with every call, environment variables are applied to the keys and values
+	Metadata []KeyValue `yaml:"metadata,omitempty"`
+	// Auth is sent as the authorization metadata, or as the metadata named by the api key
+	Auth     Auth         `yaml:"auth"`
+	Settings GRPCSettings `yaml:"settings"`
+
+	// Services are the services discovered on the host, kept so a method can be picked without discovering them again
+	Services []GRPCService `yaml:"services,omitempty"`
+}
+
+// GRPCSettings are the connection settings of a gRPC request.
+type GRPCSettings struct {
+	// Transport is one of the GRPCTransport values, empty means plaintext
+	Transport          string `yaml:"transport,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
+	// ServerName overrides the host name used to verify the server certificate
+	ServerName string `yaml:"serverName,omitempty"`
+	// CACertPath is a PEM bundle of certificate authorities to trust in addition to the system ones
+	CACertPath string `yaml:"caCertPath,omitempty"`
+	// ClientCertPath and ClientKeyPath are the PEM client certificate and key used with mtls
+	ClientCertPath string `yaml:"clientCertPath,omitempty"`
+	ClientKeyPath  string `yaml:"clientKeyPath,omitempty"`
+
+	// Deadline of each call, zero means no deadline
+	Deadline time.Duration `yaml:"deadline,omitempty"`
+}
+
+type GRPCService struct {
+	Name    string       `yaml:"name"`
+	Methods []GRPCMethod `yaml:"methods"`
+}
+
+type GRPCMethod struct {
+	// FullName is the name used to invoke the method, like package.Service/Method
+	FullName          string `yaml:"fullName"`
+	Name              string `yaml:"name"`
+	IsClientStreaming bool   `yaml:"isClientStreaming"`
+	IsServerStreaming bool   `yaml:"isServerStreaming"`
+}
+
+func NewGRPCRequest(name string) *Request {
+	return &Request{
+		ApiVersion: ApiVersion,
+		Kind:       KindRequest,
+		MetaData: RequestMeta{
+			ID:   uuid.NewString(),
+			Name: name,
+			Type: RequestTypeGRPC,
+		},
+		Spec: RequestSpec{
+			GRPC: &GRPCRequestSpec{
+				Host:             "localhost:50051",
+				Body:             "{}",
+				ServerReflection: true,
+			},
+		},
+	}
+}
+
+func (g *GRPCRequestSpec) Clone() *GRPCRequestSpec {
+	clone := *g
+	clone.Metadata = cloneKeyValues(g.Metadata)
+	clone.Auth = g.Auth.Clone()
+
+	if g.Services != nil {
+		clone.Services = make([]GRPCService, len(g.Services))
+		for i, s := range g.Services {
+			clone.Services[i] = GRPCService{Name: s.Name, Methods: slices.Clone(s.Methods)}
+		}
+	}
+
+	return &clone
+}
+
+// FindMethod returns the discovered method with the given full name.
+func (g *GRPCRequestSpec) FindMethod(fullName string) (GRPCMethod, bool) {
+	for _, s := range g.Services {
+		for _, m := range s.Methods {
+			if m.FullName == fullName {
+				return m, true
+			}
+		}
+	}
+	return GRPCMethod{}, false
+}
+
+func CompareGRPCRequestSpecs(a, b *GRPCRequestSpec) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	if a.Host != b.Host || a.Method != b.Method || a.Body != b.Body || a.ServerReflection != b.ServerReflection || a.Settings != b.Settings {
+		return false
+	}
+
+	if !CompareKeyValues(a.Metadata, b.Metadata) || !CompareAuth(a.Auth, b.Auth) {
+		return false
+	}
+
+	return slices.EqualFunc(a.Services, b.Services, func(x, y GRPCService) bool {
+		return x.Name == y.Name && slices.Equal(x.Methods, y.Methods)
+	})
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultHistorySize is the number of responses kept per request, older ones are dropped
+	DefaultHistorySize = 50
+	// MaxHistoryBodySize is the size of the body kept for each response, larger bodies are cut
+	MaxHistoryBodySize = 256 << 10
+)
+
+// ResponseHistory holds the past responses of a request, it is stored next to the requests in a directory
+// named after the request id with a file per response, so renaming or moving the request keeps its history.
+type ResponseHistory struct {
+	ApiVersion string              `yaml:"apiVersion"`
+	Kind       string              `yaml:"kind"`
+	MetaData   MetaData            `yaml:"metadata"`
+	Spec       ResponseHistorySpec `yaml:"spec"`
+}
+
+type ResponseHistorySpec struct {
+	// Responses are ordered from the newest to the oldest
+	Responses []HTTPResponse `yaml:"responses"`
+}
+
+// HTTPResponse is a response of a past execution of a request.
+type HTTPResponse struct {
+	ID   string    `yaml:"id"`
+	Time time.Time `yaml:"time"`
+	// Environment is the name of the environment the request was sent with, it is empty when none was selected
+	Environment string        `yaml:"environment,omitempty"`
+	StatusCode  int           `yaml:"statusCode,omitempty"`
+	Duration    time.Duration `yaml:"duration"`
+	Size        int           `yaml:"size"`
+	ContentType string        `yaml:"contentType,omitempty"`
+	Headers     []KeyValue    `yaml:"headers"`
+	Cookies     []KeyValue    `yaml:"cookies"`
+	// Body is empty for binary responses, it is cut to MaxHistoryBodySize and BodyTruncated is set when it is larger
+	Body          string `yaml:"body"`
+	BodyTruncated bool   `yaml:"bodyTruncated,omitempty"`
+	// Error is set when the request failed before a response was received
+	Error string `yaml:"error,omitempty"`
+}
+
+func NewResponseHistory(requestID string) *ResponseHistory {
+	return &ResponseHistory{
+		ApiVersion: ApiVersion,
+		Kind:       KindHistory,
+		MetaData: MetaData{
+			ID:   requestID,
+			Name: requestID,
+		},
+		Spec: ResponseHistorySpec{
+			Responses: make([]HTTPResponse, 0),
+		},
+	}
+}
+
+// NewHTTPResponse returns the history entry of the response detail sent with the given environment.
+func NewHTTPResponse(detail HTTPResponseDetail, environment string, now time.Time) HTTPResponse {
+	r := HTTPResponse{
+		ID:          uuid.NewString(),
+		Time:        now,
+		Environment: environment,
+		StatusCode:  detail.StatusCode,
+		Duration:    detail.Duration,
+		Size:        detail.Size,
+		ContentType: detail.ContentType,
+		Headers:     detail.Headers,
+		Cookies:     detail.Cookies,
+		Body:        detail.Response,
+	}
+
+	if detail.Error != nil {
+		r.Error = detail.Error.Error()
+	}
+
+	if len(r.Body) > MaxHistoryBodySize {
+		// the body is cut at the start of a rune, so the kept text stays valid utf-8
+		cut := MaxHistoryBodySize
+		for cut > 0 && !utf8.RuneStart(r.Body[cut]) {
+			cut--
+		}
+		r.Body = r.Body[:cut]
+		r.BodyTruncated = true
+	}
+
+	return r
+}
+
+func (h *ResponseHistory) Clone() *ResponseHistory {
+	clone := *h
+	clone.Spec.Responses = make([]HTTPResponse, len(h.Spec.Responses))
+	copy(clone.Spec.Responses, h.Spec.Responses)
+	return &clone
+}
+
+// Add adds the response as the newest one and drops the oldest ones above size, the dropped responses are returned.
+func (h *ResponseHistory) Add(response HTTPResponse, size int) []HTTPResponse {
+	h.Spec.Responses = append([]HTTPResponse{response}, h.Spec.Responses...)
+	if size <= 0 || len(h.Spec.Responses) <= size {
+		return nil
+	}
+
+	dropped := h.Spec.Responses[size:]
+	h.Spec.Responses = h.Spec.Responses[:size]
+	return dropped
+}
+
+// ToDetail returns the response as a detail, so it can be shown as the response of the request.
+func (r HTTPResponse) ToDetail() HTTPResponseDetail {
+	if r.Error != "" {
+		return HTTPResponseDetail{Error: errors.New(r.Error)}
+	}
+
+	return HTTPResponseDetail{
+		Response:    r.Body,
+		Headers:     r.Headers,
+		Cookies:     r.Cookies,
+		StatusCode:  r.StatusCode,
+		Duration:    r.Duration,
+		Size:        r.Size,
+		ContentType: r.ContentType,
+		Body:        []byte(r.Body),
+	}
+}
@@ -0,0 +1,220 @@
+package domain
+
+import "time"
+
+const (
+	DefaultMaxRedirects = 10
+	// DefaultMaxResponseSize is the size above which response bodies are written to a file, 10 MB
+	DefaultMaxResponseSize = 10 << 20
+
+	ProxyTypeHTTP   = "http"
+	ProxyTypeSOCKS5 = "socks5"
+)
+
+// HTTPClientSettings controls how requests are sent.
+// Workspace defaults are stored in the preferences and each request can override any of them,
+// fields which are not set on the request fall back to the workspace defaults.
+type HTTPClientSettings struct {
+	// Timeout of the whole request, zero means no timeout
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// FollowRedirects is nil when it is not set, redirects are followed by default
+	FollowRedirects *bool `yaml:"followRedirects,omitempty"`
+	// MaxRedirects is the number of redirects to follow before giving up, zero means DefaultMaxRedirects
+	MaxRedirects int `yaml:"maxRedirects,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the server certificate, useful for self-signed certificates
+	InsecureSkipVerify *bool `yaml:"insecureSkipVerify,omitempty"`
+	// CACertPath is a PEM bundle of certificate authorities to trust in addition to the system ones
+	CACertPath string `yaml:"caCertPath,omitempty"`
+
+	// MaxResponseSize is the size in bytes above which the body is written to a temp file and only its beginning is shown,
+	// zero means DefaultMaxResponseSize
+	MaxResponseSize int64 `yaml:"maxResponseSize,omitempty"`
+
+	// StreamChunked shows chunked text responses without a length line by line as they arrive, like log tails.
+	// it is nil when it is not set, chunked responses are read whole by default as servers chunk any large body
+	StreamChunked *bool `yaml:"streamChunked,omitempty"`
+
+	// Proxy is nil when it is not set, in that case the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used
+	Proxy *ProxySettings `yaml:"proxy,omitempty"`
+}
+
+type ProxySettings struct {
+	// Enabled is false to send the requests directly, ignoring the proxy environment variables
+	Enabled bool `yaml:"enabled"`
+	// Type is either http or socks5
+	Type string `yaml:"type"`
+	// Host is the address of the proxy in the host:port form
+	Host     string `yaml:"host"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Bypass is a list of hosts which are reached without the proxy,
+	// entries can be a host name which also matches its subdomains, an IP address or a CIDR range
+	Bypass []string `yaml:"bypass,omitempty"`
+}
+
+func (p *ProxySettings) Clone() *ProxySettings {
+	clone := *p
+	if p.Bypass != nil {
+		clone.Bypass = make([]string, len(p.Bypass))
+		copy(clone.Bypass, p.Bypass)
+	}
+	return &clone
+}
+
+func CompareProxySettings(a, b *ProxySettings) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	if a.Enabled != b.Enabled || a.Type != b.Type || a.Host != b.Host || a.Username != b.Username || a.Password != b.Password {
+		return false
+	}
+
+	if len(a.Bypass) != len(b.Bypass) {
+		return false
+	}
+
+	for i, v := range a.Bypass {
+		if v != b.Bypass[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (h *HTTPClientSettings) Clone() *HTTPClientSettings {
+	clone := *h
+
+	if h.FollowRedirects != nil {
+		v := *h.FollowRedirects
+		clone.FollowRedirects = &v
+	}
+
+	if h.InsecureSkipVerify != nil {
+		v := *h.InsecureSkipVerify
+		clone.InsecureSkipVerify = &v
+	}
+
+	if h.StreamChunked != nil {
+		v := *h.StreamChunked
+		clone.StreamChunked = &v
+	}
+
+	if h.Proxy != nil {
+		clone.Proxy = h.Proxy.Clone()
+	}
+
+	return &clone
+}
+
+// Merge returns a copy of the settings with the fields set in override taking precedence.
+func (h HTTPClientSettings) Merge(override *HTTPClientSettings) HTTPClientSettings {
+	if override == nil {
+		return h
+	}
+
+	if override.Timeout > 0 {
+		h.Timeout = override.Timeout
+	}
+
+	if override.FollowRedirects != nil {
+		h.FollowRedirects = override.FollowRedirects
+	}
+
+	if override.MaxRedirects > 0 {
+		h.MaxRedirects = override.MaxRedirects
+	}
+
+	if override.InsecureSkipVerify != nil {
+		h.InsecureSkipVerify = override.InsecureSkipVerify
+	}
+
+	if override.CACertPath != "" {
+		h.CACertPath = override.CACertPath
+	}
+
+	if override.MaxResponseSize > 0 {
+		h.MaxResponseSize = override.MaxResponseSize
+	}
+
+	if override.StreamChunked != nil {
+		h.StreamChunked = override.StreamChunked
+	}
+
+	if override.Proxy != nil {
+		h.Proxy = override.Proxy
+	}
+
+	return h
+}
+
+// ShouldFollowRedirects reports whether redirects should be followed, it defaults to true.
+func (h HTTPClientSettings) ShouldFollowRedirects() bool {
+	return h.FollowRedirects == nil || *h.FollowRedirects
+}
+
+// GetMaxRedirects returns the max number of redirects to follow, it defaults to DefaultMaxRedirects.
+func (h HTTPClientSettings) GetMaxRedirects() int {
+	if h.MaxRedirects <= 0 {
+		return DefaultMaxRedirects
+	}
+	return h.MaxRedirects
+}
+
+// GetMaxResponseSize returns the size above which response bodies are written to a file, it defaults to DefaultMaxResponseSize.
+func (h HTTPClientSettings) GetMaxResponseSize() int64 {
+	if h.MaxResponseSize <= 0 {
+		return DefaultMaxResponseSize
+	}
+	return h.MaxResponseSize
+}
+
+// ShouldSkipTLSVerify reports whether the server certificate verification should be skipped, it defaults to false.
+func (h HTTPClientSettings) ShouldSkipTLSVerify() bool {
+	return h.InsecureSkipVerify != nil && *h.InsecureSkipVerify
+}
+
+// ShouldStreamChunked reports whether chunked text responses should be shown as they arrive, it defaults to false.
+func (h HTTPClientSettings) ShouldStreamChunked() bool {
+	return h.StreamChunked != nil && *h.StreamChunked
+}
+
+func CompareHTTPClientSettings(a, b *HTTPClientSettings) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	if a.Timeout != b.Timeout || a.MaxRedirects != b.MaxRedirects || a.CACertPath != b.CACertPath || a.MaxResponseSize != b.MaxResponseSize {
+		return false
+	}
+
+	if !compareBoolPtr(a.FollowRedirects, b.FollowRedirects) || !compareBoolPtr(a.InsecureSkipVerify, b.InsecureSkipVerify) ||
+		!compareBoolPtr(a.StreamChunked, b.StreamChunked) {
+		return false
+	}
+
+	return CompareProxySettings(a.Proxy, b.Proxy)
+}
+
+func compareBoolPtr(a, b *bool) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	return *a == *b
+}
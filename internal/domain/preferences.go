@@ -12,6 +12,9 @@ type Preferences struct {
 type PrefSpec struct {
 	DarkMode            bool                `yaml:"darkMode"`
 	SelectedEnvironment SelectedEnvironment `yaml:"selectedEnvironment"`
+
+	// HTTPClient holds the default http client settings of the workspace
+	HTTPClient HTTPClientSettings `yaml:"httpClient"`
 }
 
 type SelectedEnvironment struct {
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// ProtoFileList holds the proto files of a workspace, gRPC requests read their services from them
+// when the server does not support reflection.
+type ProtoFileList struct {
+	ApiVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	MetaData   MetaData          `yaml:"metadata"`
+	Spec       ProtoFileListSpec `yaml:"spec"`
+}
+
+type ProtoFileListSpec struct {
+	// ImportPaths are the directories the imports of the files are resolved from
+	ImportPaths []string    `yaml:"importPaths"`
+	Files       []ProtoFile `yaml:"files"`
+}
+
+type ProtoFile struct {
+	ID string `yaml:"id"`
+	// Path is the path of the file on disk
+	Path     string   `yaml:"path"`
+	Package  string   `yaml:"package"`
+	Services []string `yaml:"services"`
+}
+
+func NewProtoFileList(name string) *ProtoFileList {
+	return &ProtoFileList{
+		ApiVersion: ApiVersion,
+		Kind:       KindProtoFileList,
+		MetaData: MetaData{
+			ID:   uuid.NewString(),
+			Name: name,
+		},
+		Spec: ProtoFileListSpec{
+			ImportPaths: make([]string, 0),
+			Files:       make([]ProtoFile, 0),
+		},
+	}
+}
+
+func (p *ProtoFileList) Clone() *ProtoFileList {
+	clone := *p
+	clone.Spec.ImportPaths = append([]string{}, p.Spec.ImportPaths...)
+	clone.Spec.Files = make([]ProtoFile, len(p.Spec.Files))
+	for i, f := range p.Spec.Files {
+		clone.Spec.Files[i] = f
+		clone.Spec.Files[i].Services = append([]string{}, f.Services...)
+	}
+	return &clone
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,8 +10,10 @@ import (
 )
 
 const (
-	RequestTypeHTTP = "http"
-	RequestTypeGRPC = "grpc"
+	RequestTypeHTTP      = "http"
+	RequestTypeGRPC      = "grpc"
+	RequestTypeGraphQL   = "graphql"
+	RequestTypeWebSocket = "websocket"
 
 	RequestMethodGET     = "GET"
 	RequestMethodPOST    = "POST"
@@ -32,7 +35,7 @@ const (
 
 	PrePostTypeNone      = "none"
 	PrePostTypePython    = "python"
-	PrePostTypeShell     = "ssh"
+	PrePostTypeShell     = "shell"
 	PrePostTypeSSHTunnel = "sshTunnel"
 	PrePostTypeK8sTunnel = "k8sTunnel"
 )
@@ -55,18 +58,10 @@ type RequestMeta struct {
 }
 
 type RequestSpec struct {
-	GRPC *GRPCRequestSpec `yaml:"grpc,omitempty"`
-	HTTP *HTTPRequestSpec `yaml:"http,omitempty"`
-}
-
-type GRPCRequestSpec struct {
-	Host   string `yaml:"host"`
-	Method string `yaml:"method"`
-}
-
-func (g *GRPCRequestSpec) Clone() *GRPCRequestSpec {
-	clone := *g
-	return &clone
+	GRPC      *GRPCRequestSpec      `yaml:"grpc,omitempty"`
+	HTTP      *HTTPRequestSpec      `yaml:"http,omitempty"`
+	GraphQL   *GraphQLRequestSpec   `yaml:"graphql,omitempty"`
+	WebSocket *WebSocketRequestSpec `yaml:"websocket,omitempty"`
 }
 
 type HTTPRequestSpec struct {
@@ -75,8 +70,10 @@ type HTTPRequestSpec struct {
 
 	LastUsedEnvironment LastUsedEnvironment `yaml:"lastUsedEnvironment"`
 
-	Request   *HTTPRequest   `yaml:"request"`
-	Responses []HTTPResponse `yaml:"responses"`
+	// Settings overrides the workspace http client settings for this request
+	Settings *HTTPClientSettings `yaml:"settings,omitempty"`
+
+	Request *HTTPRequest `yaml:"request"`
 }
 
 type LastUsedEnvironment struct {
@@ -91,6 +88,10 @@ func (h *HTTPRequestSpec) Clone() *HTTPRequestSpec {
 		clone.Request = h.Request.Clone()
 	}
 
+	if h.Settings != nil {
+		clone.Settings = h.Settings.Clone()
+	}
+
 	return &clone
 }
 
@@ -106,6 +107,9 @@ type HTTPRequest struct {
 
 	PreRequest  PreRequest  `yaml:"preRequest"`
 	PostRequest PostRequest `yaml:"postRequest"`
+
+	// Tests are the assertions checked against the response after every send
+	Tests []Assertion `yaml:"tests,omitempty"`
 }
 
 const (
@@ -130,6 +134,11 @@ type Body struct {
 
 func (b *Body) Clone() *Body {
 	clone := *b
+	clone.URLEncoded = cloneKeyValues(b.URLEncoded)
+	if b.FormData.Fields != nil {
+		clone.FormData.Fields = make([]FormField, len(b.FormData.Fields))
+		copy(clone.FormData.Fields, b.FormData.Fields)
+	}
 	return &clone
 }
 
@@ -216,15 +225,11 @@ type TokenAuth struct {
 	Token string `yaml:"token"`
 }
 
-type HTTPResponse struct {
-	Headers []KeyValue `yaml:"headers"`
-	Body    string     `yaml:"body"`
-	Cookies []KeyValue `yaml:"cookies"`
-}
-
 type PreRequest struct {
 	Type   string `yaml:"type"`
 	Script string `yaml:"script"`
+	// Timeout of the script, zero uses the default timeout of scripts
+	Timeout time.Duration `yaml:"timeout,omitempty"`
 
 	SShTunnel        *SShTunnel        `yaml:"sshTunnel,omitempty"`
 	KubernetesTunnel *KubernetesTunnel `yaml:"kubernetesTunnel,omitempty"`
@@ -240,9 +245,39 @@ const (
 )
 
 type PostRequest struct {
-	Type           string         `yaml:"type"`
-	Script         string         `yaml:"script"`
-	PostRequestSet PostRequestSet `yaml:"set"`
+	Type   string `yaml:"type"`
+	Script string `yaml:"script"`
+	// Timeout of the script, zero uses the default timeout of scripts
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// PostRequestSet is the single rule of requests saved before a post request could have many, use GetRules to read them
+	PostRequestSet PostRequestSet   `yaml:"set,omitempty"`
+	Rules          []PostRequestSet `yaml:"rules,omitempty"`
+}
+
+// IsEmpty returns true when the post request is not set at all.
+func (p PostRequest) IsEmpty() bool {
+	return p.Type == "" && p.Script == "" && p.Timeout == 0 && p.PostRequestSet == (PostRequestSet{}) && len(p.Rules) == 0
+}
+
+// GetRules returns the rules of the post request, falling back to the legacy single rule.
+func (p PostRequest) GetRules() []PostRequestSet {
+	if len(p.Rules) > 0 {
+		return p.Rules
+	}
+
+	if p.PostRequestSet != (PostRequestSet{}) {
+		return []PostRequestSet{p.PostRequestSet}
+	}
+	return nil
+}
+
+func (p PostRequest) Clone() PostRequest {
+	clone := p
+	if p.Rules != nil {
+		clone.Rules = make([]PostRequestSet, len(p.Rules))
+		copy(clone.Rules, p.Rules)
+	}
+	return clone
 }
 
 const (
@@ -251,21 +286,64 @@ const (
 	PostRequestSetFromResponseCookie = "responseCookie"
 )
 
+const (
+	PostRequestScopeEnvironment = "environment"
+	PostRequestScopeCollection  = "collection"
+	PostRequestScopeGlobal      = "global"
+)
+
+// PostRequestSet is a rule which stores a value of the response in a variable.
 type PostRequestSet struct {
-	Target     string `yaml:"target"`
-	StatusCode int    `yaml:"statusCode"`
+	Target string `yaml:"target"`
+	// StatusCode is the status code the rule applies to, like 200, or a class of them like 2xx. empty matches every response
+	StatusCode string `yaml:"statusCode"`
 	// From can be response header, response body or cookies
 	From    string `yaml:"from"`
 	FromKey string `yaml:"fromKey"`
+	// Scope is where the variable is stored, the active environment when empty.
+	// values which target the environment or the collection are stored in the global scope when there is none
+	Scope string `yaml:"scope,omitempty"`
 }
 
+// MatchStatusCode reports whether the rule applies to a response with the given status code.
+func (p PostRequestSet) MatchStatusCode(code int) bool {
+	pattern := strings.ToLower(strings.TrimSpace(p.StatusCode))
+	if pattern == "" {
+		return true
+	}
+
+	status := strconv.Itoa(code)
+	if len(pattern) != len(status) {
+		return false
+	}
+
+	for i := range pattern {
+		if pattern[i] != 'x' && pattern[i] != status[i] {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	KubernetesTargetPod     = "pod"
+	KubernetesTargetService = "service"
+)
+
 type KubernetesTunnel struct {
 	Target     string `yaml:"target"`
 	TargetType string `yaml:"targetType"`
+	// Context and Namespace are the kubectl context and namespace, empty uses the current ones
+	Context   string `yaml:"context,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
 
-	// The port to be used in the local machine
-	LocalPort  int `yaml:"localPort"`
+	// The port to be used in the local machine, zero picks a free one
+	LocalPort int `yaml:"localPort"`
+	// TargetPort is the port of the target, zero uses the port of the request url
 	TargetPort int `yaml:"targetPort"`
+
+	// IdleTimeout is how long the forward is kept open after the last request, zero uses the default
+	IdleTimeout time.Duration `yaml:"idleTimeout,omitempty"`
 }
 
 type SShTunnel struct {
@@ -274,12 +352,18 @@ type SShTunnel struct {
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
 	KeyPath  string `yaml:"keyPath"`
-
-	// The port to be used in the local machine
-	LocalPort  int `yaml:"localPort"`
+	// KnownHostsPath is the known_hosts file the host key of the server is checked against, empty uses ~/.ssh/known_hosts
+	KnownHostsPath string `yaml:"knownHostsPath,omitempty"`
+
+	// The port to be used in the local machine, zero picks a free one
+	LocalPort int `yaml:"localPort"`
+	// TargetHost is the host the server connects to, empty uses the host of the request url
+	TargetHost string `yaml:"targetHost,omitempty"`
+	// TargetPort is the port the server connects to, zero uses the port of the request url
 	TargetPort int `yaml:"targetPort"`
 
-	Flags []string `yaml:"flags"`
+	// Flags are not supported as the tunnel is not opened with the ssh command, requests setting them fail when they are sent
+	Flags []string `yaml:"flags,omitempty"`
 }
 
 func (r *Request) Clone() *Request {
@@ -291,11 +375,22 @@ func (r *Request) Clone() *Request {
 
 func (r *HTTPRequest) Clone() *HTTPRequest {
 	clone := *r
+	clone.Headers = cloneKeyValues(r.Headers)
+	clone.PathParams = cloneKeyValues(r.PathParams)
+	clone.QueryParams = cloneKeyValues(r.QueryParams)
+	clone.Body = *r.Body.Clone()
 
 	if r.Auth != (Auth{}) {
 		clone.Auth = r.Auth.Clone()
 	}
 
+	if r.Tests != nil {
+		clone.Tests = make([]Assertion, len(r.Tests))
+		copy(clone.Tests, r.Tests)
+	}
+
+	clone.PostRequest = r.PostRequest.Clone()
+
 	return &clone
 }
 
@@ -307,6 +402,12 @@ func (r *RequestSpec) Clone() *RequestSpec {
 	if r.HTTP != nil {
 		clone.HTTP = r.HTTP.Clone()
 	}
+	if r.GraphQL != nil {
+		clone.GraphQL = r.GraphQL.Clone()
+	}
+	if r.WebSocket != nil {
+		clone.WebSocket = r.WebSocket.Clone()
+	}
 	return &clone
 }
 
@@ -350,10 +451,18 @@ func CompareRequests(a, b *Request) bool {
 		return false
 	}
 
+	if !CompareGraphQLRequestSpecs(a.Spec.GraphQL, b.Spec.GraphQL) {
+		return false
+	}
+
+	if !CompareWebSocketRequestSpecs(a.Spec.WebSocket, b.Spec.WebSocket) {
+		return false
+	}
+
 	return true
 }
 
-func CompareGRPCRequestSpecs(a, b *GRPCRequestSpec) bool {
+func CompareHTTPRequestSpecs(a, b *HTTPRequestSpec) bool {
 	if a == nil && b == nil {
 		return true
 	}
@@ -362,31 +471,18 @@ func CompareGRPCRequestSpecs(a, b *GRPCRequestSpec) bool {
 		return false
 	}
 
-	if a.Host != b.Host || a.Method != b.Method {
-		return false
-	}
-	return true
-}
-
-func CompareHTTPRequestSpecs(a, b *HTTPRequestSpec) bool {
 	if a.Method != b.Method || a.URL != b.URL {
 		return false
 	}
 
-	if !CompareHTTPRequests(a.Request, b.Request) {
+	if !CompareHTTPClientSettings(a.Settings, b.Settings) {
 		return false
 	}
 
-	if len(a.Responses) != len(b.Responses) {
+	if !CompareHTTPRequests(a.Request, b.Request) {
 		return false
 	}
 
-	for i, v := range a.Responses {
-		if !CompareHTTPResponses(v, b.Responses[i]) {
-			return false
-		}
-	}
-
 	return true
 }
 
@@ -435,6 +531,10 @@ func CompareHTTPRequests(a, b *HTTPRequest) bool {
 		return false
 	}
 
+	if !CompareAssertions(a.Tests, b.Tests) {
+		return false
+	}
+
 	return true
 }
 
@@ -547,28 +647,8 @@ func CompareAPIKey(a, b *APIKeyAuth) bool {
 	return true
 }
 
-func CompareHTTPResponses(a, b HTTPResponse) bool {
-	if IsHTTPResponseEmpty(a) && IsHTTPResponseEmpty(b) {
-		return true
-	}
-
-	if a.Body != b.Body {
-		return false
-	}
-
-	if !CompareKeyValues(a.Headers, b.Headers) {
-		return false
-	}
-
-	if !CompareKeyValues(a.Cookies, b.Cookies) {
-		return false
-	}
-
-	return true
-}
-
 func ComparePreRequest(a, b PreRequest) bool {
-	if a.Type != b.Type || a.Script != b.Script {
+	if a.Type != b.Type || a.Script != b.Script || a.Timeout != b.Timeout {
 		return false
 	}
 
@@ -596,16 +676,10 @@ func CompareSShTunnel(a, b *SShTunnel) bool {
 		return false
 	}
 
-	if len(a.Flags) != len(b.Flags) {
+	if a.KnownHostsPath != b.KnownHostsPath || a.TargetHost != b.TargetHost {
 		return false
 	}
 
-	for i, v := range a.Flags {
-		if v != b.Flags[i] {
-			return false
-		}
-	}
-
 	return true
 }
 
@@ -622,11 +696,15 @@ func CompareKubernetesTunnel(a, b *KubernetesTunnel) bool {
 		return false
 	}
 
+	if a.Context != b.Context || a.Namespace != b.Namespace || a.IdleTimeout != b.IdleTimeout {
+		return false
+	}
+
 	return true
 }
 
 func ComparePostRequest(a, b PostRequest) bool {
-	if a.Type != b.Type || a.Script != b.Script {
+	if a.Type != b.Type || a.Script != b.Script || a.Timeout != b.Timeout {
 		return false
 	}
 
@@ -634,11 +712,21 @@ func ComparePostRequest(a, b PostRequest) bool {
 		return false
 	}
 
+	if len(a.Rules) != len(b.Rules) {
+		return false
+	}
+
+	for i := range a.Rules {
+		if !ComparePostRequestSet(a.Rules[i], b.Rules[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
 func ComparePostRequestSet(a, b PostRequestSet) bool {
-	if a.Target != b.Target || a.From != b.From || a.FromKey != b.FromKey || a.StatusCode != b.StatusCode {
+	if a.Target != b.Target || a.From != b.From || a.FromKey != b.FromKey || a.StatusCode != b.StatusCode || a.Scope != b.Scope {
 		return false
 	}
 	return true
@@ -658,19 +746,16 @@ func Clone[T any](org *T) (*T, error) {
 	return &clone, nil
 }
 
-func IsHTTPResponseEmpty(r HTTPResponse) bool {
-	if r.Body != "" || len(r.Headers) > 0 || len(r.Cookies) > 0 {
-		return false
-	}
-
-	return true
-}
-
 func (r *Request) SetDefaultValues() {
 	if r.MetaData.Type == "" {
 		r.MetaData.Type = KindRequest
 	}
 
+	// other request types have their own spec
+	if r.Spec.HTTP == nil {
+		return
+	}
+
 	if r.Spec.HTTP.Method == "" {
 		r.Spec.HTTP.Method = "GET"
 	}
@@ -685,7 +770,7 @@ func (r *Request) SetDefaultValues() {
 		}
 	}
 
-	if r.Spec.HTTP.Request.PostRequest == (PostRequest{}) {
+	if r.Spec.HTTP.Request.PostRequest.IsEmpty() {
 		r.Spec.HTTP.Request.PostRequest = PostRequest{
 			Type: "None",
 		}
@@ -777,6 +862,32 @@ type HTTPResponseDetail struct {
 	StatusCode int
 	Duration   time.Duration
 	Size       int
+	Timings    HTTPTimings
+
+	ContentType string
+	// Body is the raw body, or its beginning when the response is Truncated
+	Body []byte
+	// Binary is true when the body is not text, like images or archives
+	Binary bool
+	// Truncated is true when the body was larger than the max response size, the whole body is in BodyFile
+	Truncated bool
+	BodyFile  string
+
+	TestResults []AssertionResult
 
 	Error error
 }
+
+// HTTPTimings is the breakdown of the time spent on a request,
+// phases which did not happen, like the dns lookup of a reused connection, are zero.
+type HTTPTimings struct {
+	DNSLookup    time.Duration
+	TCPConnect   time.Duration
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the time from writing the request until the first byte of the response, the time the server took to respond
+	TimeToFirstByte time.Duration
+	Download        time.Duration
+	Total           time.Duration
+
+	ConnectionReused bool
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	WebSocketMessageTypeText   = "text"
+	WebSocketMessageTypeJSON   = "json"
+	WebSocketMessageTypeBinary = "binary"
+)
+
+// WebSocketRequestSpec is a websocket connection, the handshake is sent with the headers and auth of Request.
+type WebSocketRequestSpec struct {
+	URL string `yaml:"url"`
+
+	LastUsedEnvironment LastUsedEnvironment `yaml:"lastUsedEnvironment"`
+
+	// Settings overrides the workspace http client settings for this request
+	Settings *HTTPClientSettings `yaml:"settings,omitempty"`
+
+	Request *HTTPRequest `yaml:"request"`
+
+	// Messages are the saved message templates
+	Messages []WebSocketMessage `yaml:"messages,omitempty"`
+}
+
+type WebSocketMessage struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	// Type is one of text, json or binary, binary data is base64 encoded
+	Type string `yaml:"type"`
+	Data string `yaml:"data"`
+}
+
+func NewWebSocketRequest(name string) *Request {
+	return &Request{
+		ApiVersion: ApiVersion,
+		Kind:       KindRequest,
+		MetaData: RequestMeta{
+			ID:   uuid.NewString(),
+			Name: name,
+			Type: RequestTypeWebSocket,
+		},
+		Spec: RequestSpec{
+			WebSocket: &WebSocketRequestSpec{
+				URL: "wss://example.com/ws",
+				Request: &HTTPRequest{
+					Auth: Auth{Type: AuthTypeNone},
+				},
+			},
+		},
+	}
+}
+
+func (w *WebSocketRequestSpec) Clone() *WebSocketRequestSpec {
+	clone := *w
+
+	if w.Request != nil {
+		clone.Request = w.Request.Clone()
+		clone.Request.Headers = cloneKeyValues(w.Request.Headers)
+	}
+
+	if w.Settings != nil {
+		clone.Settings = w.Settings.Clone()
+	}
+
+	if w.Messages != nil {
+		clone.Messages = make([]WebSocketMessage, len(w.Messages))
+		copy(clone.Messages, w.Messages)
+	}
+
+	return &clone
+}
+
+// ToHTTPRequestSpec returns the handshake request of the connection.
+func (w *WebSocketRequestSpec) ToHTTPRequestSpec() *HTTPRequestSpec {
+	spec := &HTTPRequestSpec{
+		Method:   RequestMethodGET,
+		URL:      w.URL,
+		Settings: w.Settings,
+		Request:  &HTTPRequest{},
+	}
+
+	if w.Request != nil {
+		spec.Request = w.Request.Clone()
+		spec.Request.Headers = cloneKeyValues(w.Request.Headers)
+		spec.Request.Body = Body{}
+	}
+
+	return spec
+}
+
+func CompareWebSocketRequestSpecs(a, b *WebSocketRequestSpec) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	if a.URL != b.URL || len(a.Messages) != len(b.Messages) {
+		return false
+	}
+
+	for i, m := range a.Messages {
+		if m != b.Messages[i] {
+			return false
+		}
+	}
+
+	if !CompareHTTPClientSettings(a.Settings, b.Settings) {
+		return false
+	}
+
+	return CompareHTTPRequests(a.Request, b.Request)
+}
+
+func cloneKeyValues(kvs []KeyValue) []KeyValue {
+	if kvs == nil {
+		return nil
+	}
+
+	clone := make([]KeyValue, len(kvs))
+	copy(clone, kvs)
+	return clone
+}
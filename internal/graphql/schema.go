@@ -0,0 +1,258 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+	KindList        = "LIST"
+	KindNonNull     = "NON_NULL"
+)
+
+// IntrospectionOperationName is the operation name of IntrospectionQuery.
+const IntrospectionOperationName = "IntrospectionQuery"
+
+// IntrospectionQuery fetches the parts of the schema shown in the schema explorer.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      description
+      fields(includeDeprecated: true) {
+        name
+        description
+        args { ...InputValue }
+        type { ...TypeRef }
+        isDeprecated
+        deprecationReason
+      }
+      inputFields { ...InputValue }
+      interfaces { ...TypeRef }
+      enumValues(includeDeprecated: true) {
+        name
+        description
+        isDeprecated
+        deprecationReason
+      }
+      possibleTypes { ...TypeRef }
+    }
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type Schema struct {
+	QueryType        *NamedRef `json:"queryType"`
+	MutationType     *NamedRef `json:"mutationType"`
+	SubscriptionType *NamedRef `json:"subscriptionType"`
+	Types            []Type    `json:"types"`
+}
+
+type NamedRef struct {
+	Name string `json:"name"`
+}
+
+type Type struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+type Field struct {
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason string       `json:"deprecationReason"`
+}
+
+type InputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type EnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// TypeRef is a reference to a type wrapped in any number of list and non null modifiers.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// String returns the type in the GraphQL notation, e.g. [User!]!
+func (t TypeRef) String() string {
+	switch t.Kind {
+	case KindNonNull:
+		if t.OfType == nil {
+			return "!"
+		}
+		return t.OfType.String() + "!"
+	case KindList:
+		if t.OfType == nil {
+			return "[]"
+		}
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// NamedType returns the name of the type without its list and non null modifiers.
+func (t TypeRef) NamedType() string {
+	if t.OfType != nil && (t.Kind == KindNonNull || t.Kind == KindList) {
+		return t.OfType.NamedType()
+	}
+	return t.Name
+}
+
+// Signature returns the field with its arguments, e.g. user(id: ID!): User
+func (f Field) Signature() string {
+	if len(f.Args) == 0 {
+		return f.Name + ": " + f.Type.String()
+	}
+
+	args := make([]string, 0, len(f.Args))
+	for _, a := range f.Args {
+		arg := a.Name + ": " + a.Type.String()
+		if a.DefaultValue != nil {
+			arg += " = " + *a.DefaultValue
+		}
+		args = append(args, arg)
+	}
+
+	return f.Name + "(" + strings.Join(args, ", ") + "): " + f.Type.String()
+}
+
+// Type returns the type with the given name or nil if it does not exist.
+func (s *Schema) Type(name string) *Type {
+	for i := range s.Types {
+		if s.Types[i].Name == name {
+			return &s.Types[i]
+		}
+	}
+	return nil
+}
+
+// RootTypes returns the names of the query, mutation and subscription types which exist in the schema.
+func (s *Schema) RootTypes() []string {
+	out := make([]string, 0, 3)
+	for _, ref := range []*NamedRef{s.QueryType, s.MutationType, s.SubscriptionType} {
+		if ref != nil && ref.Name != "" {
+			out = append(out, ref.Name)
+		}
+	}
+	return out
+}
+
+// TypeNames returns the names of the types defined by the server sorted with the root types first,
+// the built-in introspection types are left out.
+func (s *Schema) TypeNames() []string {
+	roots := s.RootTypes()
+	isRoot := make(map[string]bool, len(roots))
+	for _, r := range roots {
+		isRoot[r] = true
+	}
+
+	names := make([]string, 0, len(s.Types))
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || isRoot[t.Name] {
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+
+	return append(roots, names...)
+}
+
+type introspectionResponse struct {
+	Data *struct {
+		Schema *Schema `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ParseIntrospection parses the response of IntrospectionQuery.
+func ParseIntrospection(data []byte) (*Schema, error) {
+	var resp introspectionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, fmt.Errorf("introspection failed: %s", strings.Join(messages, ", "))
+	}
+
+	if resp.Data == nil || resp.Data.Schema == nil {
+		return nil, errors.New("introspection response has no schema")
+	}
+
+	return resp.Data.Schema, nil
+}
@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"testing"
+)
+
+const sampleIntrospection = `{
+  "data": {
+    "__schema": {
+      "queryType": {"name": "Query"},
+      "mutationType": null,
+      "subscriptionType": null,
+      "types": [
+        {
+          "kind": "OBJECT",
+          "name": "Query",
+          "fields": [
+            {
+              "name": "user",
+              "args": [
+                {"name": "id", "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "ID"}}, "defaultValue": null}
+              ],
+              "type": {"kind": "OBJECT", "name": "User"}
+            },
+            {
+              "name": "users",
+              "args": [
+                {"name": "first", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "10"}
+              ],
+              "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "User"}}}}
+            }
+          ]
+        },
+        {"kind": "OBJECT", "name": "User", "fields": [{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}}]},
+        {"kind": "SCALAR", "name": "ID"},
+        {"kind": "OBJECT", "name": "__Schema"}
+      ]
+    }
+  }
+}`
+
+func TestParseIntrospection(t *testing.T) {
+	schema, err := ParseIntrospection([]byte(sampleIntrospection))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := schema.TypeNames()
+	want := []string{"Query", "ID", "User"}
+	if len(names) != len(want) {
+		t.Fatalf("expected types %v but got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected types %v but got %v", want, names)
+		}
+	}
+
+	query := schema.Type("Query")
+	if query == nil {
+		t.Fatalf("expected Query type to exist")
+	}
+
+	if got := query.Fields[0].Signature(); got != "user(id: ID!): User" {
+		t.Errorf("unexpected signature %q", got)
+	}
+
+	if got := query.Fields[1].Signature(); got != "users(first: Int = 10): [User!]!" {
+		t.Errorf("unexpected signature %q", got)
+	}
+
+	if got := query.Fields[1].Type.NamedType(); got != "User" {
+		t.Errorf("expected named type User but got %q", got)
+	}
+}
+
+func TestParseIntrospectionErrors(t *testing.T) {
+	_, err := ParseIntrospection([]byte(`{"errors": [{"message": "introspection is disabled"}]}`))
+	if err == nil || err.Error() != "introspection failed: introspection is disabled" {
+		t.Errorf("expected introspection error but got %v", err)
+	}
+
+	if _, err := ParseIntrospection([]byte(`not json`)); err == nil {
+		t.Errorf("expected error for invalid json")
+	}
+}
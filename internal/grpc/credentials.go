@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// transportCredentials returns the credentials of the connection described by the settings.
+func transportCredentials(settings domain.GRPCSettings) (credentials.TransportCredentials, error) {
+	switch settings.Transport {
+	case "", domain.GRPCTransportPlaintext:
+		return insecure.NewCredentials(), nil
+	case domain.GRPCTransportTLS, domain.GRPCTransportMTLS:
+	default:
+		return nil, fmt.Errorf("unknown transport %s", settings.Transport)
+	}
+
+	tlsConfig := &tls.Config{
+		// it is up to the user to disable the verification, e.g. for self-signed staging certificates
+		InsecureSkipVerify: settings.InsecureSkipVerify,
+		ServerName:         settings.ServerName,
+	}
+
+	if settings.CACertPath != "" {
+		pool, err := rest.LoadCertPool(settings.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if settings.Transport == domain.GRPCTransportMTLS {
+		if settings.ClientCertPath == "" || settings.ClientKeyPath == "" {
+			return nil, fmt.Errorf("mtls requires a client certificate and key")
+		}
+
+		cert, err := tls.LoadX509KeyPair(settings.ClientCertPath, settings.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// outgoingMetadata returns the enabled metadata of the request along with its auth.
+func outgoingMetadata(spec *domain.GRPCRequestSpec) metadata.MD {
+	md := metadata.MD{}
+	for _, kv := range spec.Metadata {
+		if kv.Enable && kv.Key != "" {
+			md.Append(kv.Key, kv.Value)
+		}
+	}
+
+	auth := spec.Auth
+	switch auth.Type {
+	case domain.AuthTypeToken:
+		if auth.TokenAuth != nil && auth.TokenAuth.Token != "" {
+			md.Set("authorization", "Bearer "+auth.TokenAuth.Token)
+		}
+	case domain.AuthTypeBasic:
+		if auth.BasicAuth != nil && auth.BasicAuth.Username != "" && auth.BasicAuth.Password != "" {
+			credentials := base64.StdEncoding.EncodeToString([]byte(auth.BasicAuth.Username + ":" + auth.BasicAuth.Password))
+			md.Set("authorization", "Basic "+credentials)
+		}
+	case domain.AuthTypeAPIKey:
+		if auth.APIKeyAuth != nil && auth.APIKeyAuth.Key != "" && auth.APIKeyAuth.Value != "" {
+			md.Set(auth.APIKeyAuth.Key, auth.APIKeyAuth.Value)
+		}
+	}
+
+	return md
+}
+
+// replaceAuthVariables applies the variables to the auth, which should be a clone of the one of the request.
+func replaceAuthVariables(auth *domain.Auth, variables map[string]string) {
+	if auth.TokenAuth != nil {
+		auth.TokenAuth.Token = rest.ReplaceText(auth.TokenAuth.Token, variables)
+	}
+
+	if auth.BasicAuth != nil {
+		auth.BasicAuth.Username = rest.ReplaceText(auth.BasicAuth.Username, variables)
+		auth.BasicAuth.Password = rest.ReplaceText(auth.BasicAuth.Password, variables)
+	}
+
+	if auth.APIKeyAuth != nil {
+		auth.APIKeyAuth.Key = rest.ReplaceText(auth.APIKeyAuth.Key, variables)
+		auth.APIKeyAuth.Value = rest.ReplaceText(auth.APIKeyAuth.Value, variables)
+	}
+}
@@ -0,0 +1,306 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/state"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type Response struct {
+	// Body is the response message in JSON, or the status along with its details when the call failed
+	Body     string
+	Headers  []domain.KeyValue
+	Trailers []domain.KeyValue
+
+	// Status is the name of the status code, like OK
+	Status        string
+	StatusMessage string
+	TimePassed    time.Duration
+	Size          int
+}
+
+type Service struct {
+	requests     *state.Requests
+	environments *state.Environments
+	protoFiles   *state.ProtoFiles
+}
+
+func New(requests *state.Requests, environments *state.Environments, protoFiles *state.ProtoFiles) *Service {
+	return &Service{
+		requests:     requests,
+		environments: environments,
+		protoFiles:   protoFiles,
+	}
+}
+
+// GetServices lists the services of the request along with their methods, using server reflection or the proto files of the workspace.
+func (s *Service) GetServices(ctx context.Context, requestID, activeEnvironmentID string) ([]domain.GRPCService, error) {
+	spec, _, err := s.prepare(requestID, activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	src, err := s.newDescriptorSource(ctx, spec, conn)
+	if err != nil {
+		return nil, err
+	}
+	defer src.close()
+
+	services, err := src.listServices()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.GRPCService, 0, len(services))
+	for _, sd := range services {
+		out = append(out, serviceFromDescriptor(sd))
+	}
+	return out, nil
+}
+
+// GetRequestTemplate returns a JSON message with all the fields of the input of the given method.
+func (s *Service) GetRequestTemplate(ctx context.Context, requestID, activeEnvironmentID, method string) (string, error) {
+	spec, _, err := s.prepare(requestID, activeEnvironmentID)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := dial(spec)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	md, err := s.resolveMethod(ctx, conn, spec, method)
+	if err != nil {
+		return "", err
+	}
+
+	return messageTemplate(md.Input())
+}
+
+// Invoke calls the unary method of the request with the given id using the given environment.
+// statuses other than OK are returned as a response with the status in the body, the error is only for failures which have no status.
+func (s *Service) Invoke(ctx context.Context, requestID, activeEnvironmentID string) (*Response, error) {
+	spec, variables, err := s.prepare(requestID, activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Method == "" {
+		return nil, errors.New("no method is selected")
+	}
+
+	conn, err := dial(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	md, err := s.resolveMethod(ctx, conn, spec, spec.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, fmt.Errorf("method %s is a streaming method, open a stream instead", spec.Method)
+	}
+
+	req := dynamicpb.NewMessage(md.Input())
+	if err := unmarshalMessage(rest.ReplaceText(spec.Body, variables), req); err != nil {
+		return nil, err
+	}
+
+	res := dynamicpb.NewMessage(md.Output())
+	var header, trailer metadata.MD
+
+	ctx, cancel := callContext(ctx, spec)
+	defer cancel()
+
+	start := time.Now()
+	err = conn.Invoke(ctx, invokePath(spec.Method), req, res, grpc.Header(&header), grpc.Trailer(&trailer))
+	elapsed := time.Since(start)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if !ok {
+			return nil, err
+		}
+
+		return &Response{
+			Body:          statusBody(st),
+			Headers:       metadataToKeyValue(header),
+			Trailers:      metadataToKeyValue(trailer),
+			Status:        st.Code().String(),
+			StatusMessage: st.Message(),
+			TimePassed:    elapsed,
+		}, nil
+	}
+
+	body, err := marshalMessage(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Body:       body,
+		Headers:    metadataToKeyValue(header),
+		Trailers:   metadataToKeyValue(trailer),
+		Status:     codes.OK.String(),
+		TimePassed: elapsed,
+		Size:       proto.Size(res),
+	}, nil
+}
+
+// resolveMethod returns the descriptor of the method from the descriptor source of the request.
+func (s *Service) resolveMethod(ctx context.Context, conn *grpc.ClientConn, spec *domain.GRPCRequestSpec, method string) (protoreflect.MethodDescriptor, error) {
+	src, err := s.newDescriptorSource(ctx, spec, conn)
+	if err != nil {
+		return nil, err
+	}
+	defer src.close()
+
+	return findMethod(src, method)
+}
+
+// prepare returns the spec of the request with the environment variables applied to the host, the metadata, the auth and the settings,
+// along with the variables to apply to the messages.
+func (s *Service) prepare(requestID, activeEnvironmentID string) (*domain.GRPCRequestSpec, map[string]string, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	if req.Spec.GRPC == nil {
+		return nil, nil, fmt.Errorf("request %s is not a gRPC request", req.MetaData.Name)
+	}
+
+	var env *domain.Environment
+	if activeEnvironmentID != "" {
+		env = s.environments.GetEnvironment(activeEnvironmentID)
+		if env == nil {
+			return nil, nil, fmt.Errorf("environment with id %s not found", activeEnvironmentID)
+		}
+	}
+
+	variables, err := rest.ResolveVariables(s.requests, s.environments, req.CollectionID, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spec := req.Spec.GRPC.Clone()
+	spec.Host = rest.ReplaceText(spec.Host, variables)
+	for i, kv := range spec.Metadata {
+		spec.Metadata[i].Key = rest.ReplaceText(kv.Key, variables)
+		spec.Metadata[i].Value = rest.ReplaceText(kv.Value, variables)
+	}
+	replaceAuthVariables(&spec.Auth, variables)
+	spec.Settings.ServerName = rest.ReplaceText(spec.Settings.ServerName, variables)
+	spec.Settings.CACertPath = rest.ReplaceText(spec.Settings.CACertPath, variables)
+	spec.Settings.ClientCertPath = rest.ReplaceText(spec.Settings.ClientCertPath, variables)
+	spec.Settings.ClientKeyPath = rest.ReplaceText(spec.Settings.ClientKeyPath, variables)
+
+	if spec.Host == "" {
+		return nil, nil, errors.New("host is empty")
+	}
+
+	return spec, variables, nil
+}
+
+func dial(spec *domain.GRPCRequestSpec) (*grpc.ClientConn, error) {
+	creds, err := transportCredentials(spec.Settings)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewClient(spec.Host, grpc.WithTransportCredentials(creds))
+}
+
+// callContext returns the context of a call with the metadata and the deadline of the request.
+func callContext(ctx context.Context, spec *domain.GRPCRequestSpec) (context.Context, context.CancelFunc) {
+	ctx = metadata.NewOutgoingContext(ctx, outgoingMetadata(spec))
+	if spec.Settings.Deadline > 0 {
+		return context.WithTimeout(ctx, spec.Settings.Deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// invokePath returns the path the method is called with, package.Service/Method becomes /package.Service/Method.
+func invokePath(method string) string {
+	return "/" + strings.TrimPrefix(method, "/")
+}
+
+// methodFullName returns the name of the method as used in the requests.
+func methodFullName(md protoreflect.MethodDescriptor) string {
+	return string(md.Parent().FullName()) + "/" + string(md.Name())
+}
+
+func serviceFromDescriptor(sd protoreflect.ServiceDescriptor) domain.GRPCService {
+	service := domain.GRPCService{Name: string(sd.FullName())}
+
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		service.Methods = append(service.Methods, domain.GRPCMethod{
+			FullName:          methodFullName(md),
+			Name:              string(md.Name()),
+			IsClientStreaming: md.IsStreamingClient(),
+			IsServerStreaming: md.IsStreamingServer(),
+		})
+	}
+	return service
+}
+
+func unmarshalMessage(body string, msg *dynamicpb.Message) error {
+	if strings.TrimSpace(body) == "" {
+		return nil
+	}
+
+	if err := protojson.Unmarshal([]byte(body), msg); err != nil {
+		return fmt.Errorf("invalid request message: %w", err)
+	}
+	return nil
+}
+
+func marshalMessage(msg proto.Message) (string, error) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return rest.PrettyJSON(data)
+}
+
+func metadataToKeyValue(md metadata.MD) []domain.KeyValue {
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var kvs []domain.KeyValue
+	for _, k := range keys {
+		for _, v := range md[k] {
+			kvs = append(kvs, domain.KeyValue{Key: k, Value: v, Enable: true})
+		}
+	}
+	return kvs
+}
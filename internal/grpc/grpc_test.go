@@ -0,0 +1,463 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer starts a server with the health service and server reflection, it returns the address of the server.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	srv := grpc.NewServer(grpc.UnaryInterceptor(testInterceptor))
+	hs := health.NewServer()
+	hs.SetServingStatus("chapar", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(srv, hs)
+	reflection.Register(srv)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+// testInterceptor echoes the received metadata in the trailers, and fails the health check of the "details" service with error details.
+func testInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range []string{"authorization", "x-tenant"} {
+			if values := md.Get(key); len(values) > 0 {
+				_ = grpc.SetTrailer(ctx, metadata.Pairs("echo-"+key, values[0]))
+			}
+		}
+	}
+
+	if r, ok := req.(*grpc_health_v1.HealthCheckRequest); ok && r.GetService() == "details" {
+		st, err := status.New(codes.InvalidArgument, "invalid service").WithDetails(&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "service", Description: "reserved name"}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return nil, st.Err()
+	}
+
+	return handler(ctx, req)
+}
+
+func TestService_Invoke(t *testing.T) {
+	addr := newTestServer(t)
+	_, port, _ := net.SplitHostPort(addr)
+
+	req := domain.NewGRPCRequest("health")
+	req.Spec.GRPC.Host = "127.0.0.1:{{port}}"
+	req.Spec.GRPC.Method = "grpc.health.v1.Health/Check"
+	req.Spec.GRPC.Body = `{"service": "{{service}}"}`
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	env := domain.NewEnvironment("local")
+	env.Spec.Values = []domain.KeyValue{
+		{ID: "1", Key: "port", Value: port, Enable: true},
+		{ID: "2", Key: "service", Value: "chapar", Enable: true},
+	}
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	s := New(requests, environments, nil)
+	ctx := context.Background()
+
+	services, err := s.GetServices(ctx, req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(services) != 1 || services[0].Name != "grpc.health.v1.Health" {
+		t.Fatalf("expected only the health service but got %+v", services)
+	}
+
+	req.Spec.GRPC.Services = services
+	if m, ok := req.Spec.GRPC.FindMethod("grpc.health.v1.Health/Watch"); !ok || !m.IsServerStreaming || m.IsClientStreaming {
+		t.Errorf("expected Watch to be a server streaming method but got %+v", m)
+	}
+
+	template, err := s.GetRequestTemplate(ctx, req.MetaData.ID, env.MetaData.ID, req.Spec.GRPC.Method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(template, `"service": ""`) {
+		t.Errorf("unexpected template %s", template)
+	}
+
+	res, err := s.Invoke(ctx, req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != "OK" || !strings.Contains(res.Body, `"status": "SERVING"`) {
+		t.Errorf("unexpected response %s %s", res.Status, res.Body)
+	}
+
+	req.Spec.GRPC.Body = `{"service": "unknown"}`
+	res, err = s.Invoke(ctx, req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != "NotFound" || !strings.Contains(res.Body, `"code": "NotFound"`) {
+		t.Errorf("expected a NotFound status but got %s %s", res.Status, res.Body)
+	}
+
+	req.Spec.GRPC.Body = `{"unknown": 1}`
+	if _, err := s.Invoke(ctx, req.MetaData.ID, env.MetaData.ID); err == nil {
+		t.Errorf("expected an invalid message to fail")
+	}
+
+	req.Spec.GRPC.Method = "grpc.health.v1.Health/Watch"
+	if _, err := s.Invoke(ctx, req.MetaData.ID, env.MetaData.ID); err == nil {
+		t.Errorf("expected invoking a streaming method to fail")
+	}
+}
+
+func Test_messageTemplate(t *testing.T) {
+	md := (&grpc_health_v1.HealthCheckResponse{}).ProtoReflect().Descriptor()
+
+	template, err := messageTemplate(md)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(template, `"status": "UNKNOWN"`) {
+		t.Errorf("unexpected template %s", template)
+	}
+}
+
+const healthProto = `syntax = "proto3";
+
+package grpc.health.v1;
+
+import "common/service.proto";
+
+message HealthCheckResponse {
+  enum ServingStatus {
+    UNKNOWN = 0;
+    SERVING = 1;
+    NOT_SERVING = 2;
+    SERVICE_UNKNOWN = 3;
+  }
+  ServingStatus status = 1;
+}
+
+service Health {
+  rpc Check(HealthCheckRequest) returns (HealthCheckResponse);
+}
+`
+
+// the request message lives in another file to resolve the import from the import path
+const commonProto = `syntax = "proto3";
+
+package grpc.health.v1;
+
+message HealthCheckRequest {
+  string service = 1;
+}
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_InvokeWithProtoFiles(t *testing.T) {
+	addr := newTestServer(t)
+
+	dir := t.TempDir()
+	protoPath := filepath.Join(dir, "health", "health.proto")
+	writeFile(t, protoPath, healthProto)
+	writeFile(t, filepath.Join(dir, "include", "common", "service.proto"), commonProto)
+
+	list := domain.NewProtoFileList("test")
+	list.Spec.ImportPaths = []string{filepath.Join(dir, "include")}
+	list.Spec.Files = []domain.ProtoFile{{ID: "1", Path: protoPath}}
+
+	protoFiles := state.NewProtoFiles(nil)
+	if err := protoFiles.UpdateProtoFiles(list, state.SourceController); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := domain.NewGRPCRequest("health")
+	req.Spec.GRPC.Host = addr
+	req.Spec.GRPC.ServerReflection = false
+	req.Spec.GRPC.Method = "grpc.health.v1.Health/Check"
+	req.Spec.GRPC.Body = `{"service": "chapar"}`
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), protoFiles)
+	ctx := context.Background()
+
+	services, err := s.GetServices(ctx, req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(services) != 1 || len(services[0].Methods) != 1 || services[0].Methods[0].FullName != req.Spec.GRPC.Method {
+		t.Fatalf("unexpected services %+v", services)
+	}
+
+	res, err := s.Invoke(ctx, req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(res.Body, `"status": "SERVING"`) {
+		t.Errorf("unexpected response %s", res.Body)
+	}
+}
+
+func TestParseProtoFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "broken.proto")
+	writeFile(t, path, "syntax = \"proto3\";\n\nmessage Broken {\n  string name = ;\n}\n")
+
+	_, err := ParseProtoFiles(context.Background(), domain.ProtoFileListSpec{
+		Files: []domain.ProtoFile{{ID: "1", Path: path}},
+	})
+
+	var fileErr *ProtoFileError
+	if !errors.As(err, &fileErr) {
+		t.Fatalf("expected a ProtoFileError but got %v", err)
+	}
+
+	if fileErr.File != "broken.proto" || fileErr.Line != 4 {
+		t.Errorf("expected the error at broken.proto:4 but got %s", fileErr)
+	}
+}
+
+func TestStream_ServerStreaming(t *testing.T) {
+	addr := newTestServer(t)
+
+	req := domain.NewGRPCRequest("watch")
+	req.Spec.GRPC.Host = addr
+	req.Spec.GRPC.Method = "grpc.health.v1.Health/Watch"
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := s.OpenStream(ctx, req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stream.ClientStreaming || !stream.ServerStreaming {
+		t.Fatalf("expected a server streaming call but got %+v", stream)
+	}
+
+	if _, err := stream.Send(`{"service": "chapar"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := stream.Send(`{}`); err == nil {
+		t.Error("expected an error when sending on a half-closed stream")
+	}
+
+	var received []StreamMessage
+	// watch never ends on its own, the call is canceled once the current status is received
+	res, err := stream.Receive(func(msg StreamMessage) {
+		received = append(received, msg)
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received) != 1 || received[0].Direction != StreamDirectionIn || !strings.Contains(received[0].Body, `"status": "SERVING"`) {
+		t.Errorf("unexpected messages %+v", received)
+	}
+
+	if res.Status != "Canceled" {
+		t.Errorf("expected Canceled status but got %s", res.Status)
+	}
+}
+
+func TestStream_Bidirectional(t *testing.T) {
+	addr := newTestServer(t)
+
+	req := domain.NewGRPCRequest("reflection")
+	req.Spec.GRPC.Host = addr
+	req.Spec.GRPC.Method = "grpc.reflection.v1.ServerReflection/ServerReflectionInfo"
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), nil)
+
+	stream, err := s.OpenStream(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !stream.ClientStreaming || !stream.ServerStreaming {
+		t.Fatalf("expected a bidirectional call but got %+v", stream)
+	}
+
+	for i := 0; i < 2; i++ {
+		msg, err := stream.Send(`{"listServices": ""}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if msg.Direction != StreamDirectionOut {
+			t.Errorf("expected an outgoing message but got %+v", msg)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var received []StreamMessage
+	res, err := stream.Receive(func(msg StreamMessage) {
+		received = append(received, msg)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received) != 2 || !strings.Contains(received[0].Body, "grpc.health.v1.Health") {
+		t.Errorf("expected two responses listing the services but got %+v", received)
+	}
+
+	if res.Status != "OK" {
+		t.Errorf("expected OK status but got %s", res.Status)
+	}
+}
+
+func TestService_InvokeWithMetadata(t *testing.T) {
+	addr := newTestServer(t)
+
+	req := domain.NewGRPCRequest("health")
+	req.Spec.GRPC.Host = addr
+	req.Spec.GRPC.Method = "grpc.health.v1.Health/Check"
+	req.Spec.GRPC.Body = `{"service": "chapar"}`
+	req.Spec.GRPC.Metadata = []domain.KeyValue{
+		{ID: "1", Key: "X-Tenant", Value: "{{tenant}}", Enable: true},
+		{ID: "2", Key: "x-disabled", Value: "1", Enable: false},
+	}
+	req.Spec.GRPC.Auth = domain.Auth{Type: domain.AuthTypeToken, TokenAuth: &domain.TokenAuth{Token: "{{token}}"}}
+	req.Spec.GRPC.Settings.Deadline = time.Minute
+
+	// the tenant is a collection variable and the token a global one, like for http requests
+	col := domain.NewCollection("health")
+	col.Spec.Variables = []domain.KeyValue{{ID: "1", Key: "tenant", Value: "acme", Enable: true}}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+	requests.AddCollection(col)
+	requests.AddRequestToCollection(col, req)
+	req.CollectionID = col.MetaData.ID
+
+	env := domain.NewEnvironment("local")
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+	if err := environments.SetGlobalValues([]domain.KeyValue{{Key: "token", Value: "secret"}}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := New(requests, environments, nil)
+
+	res, err := s.Invoke(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trailers := map[string]string{}
+	for _, kv := range res.Trailers {
+		trailers[kv.Key] = kv.Value
+	}
+
+	if trailers["echo-authorization"] != "Bearer secret" || trailers["echo-x-tenant"] != "acme" {
+		t.Errorf("expected the metadata to be sent but got trailers %+v", res.Trailers)
+	}
+
+	req.Spec.GRPC.Body = `{"service": "details"}`
+	res, err = s.Invoke(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Status != "InvalidArgument" || res.StatusMessage != "invalid service" {
+		t.Errorf("unexpected status %s %s", res.Status, res.StatusMessage)
+	}
+
+	if !strings.Contains(res.Body, "google.rpc.BadRequest") || !strings.Contains(res.Body, "reserved name") {
+		t.Errorf("expected the details to be decoded but got %s", res.Body)
+	}
+}
+
+func Test_transportCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings domain.GRPCSettings
+		protocol string
+		wantErr  bool
+	}{
+		{name: "default", settings: domain.GRPCSettings{}, protocol: "insecure"},
+		{name: "plaintext", settings: domain.GRPCSettings{Transport: domain.GRPCTransportPlaintext}, protocol: "insecure"},
+		{name: "tls", settings: domain.GRPCSettings{Transport: domain.GRPCTransportTLS}, protocol: "tls"},
+		{name: "mtls without certificate", settings: domain.GRPCSettings{Transport: domain.GRPCTransportMTLS}, wantErr: true},
+		{name: "unknown", settings: domain.GRPCSettings{Transport: "quic"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := transportCredentials(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err == nil && creds.Info().SecurityProtocol != tt.protocol {
+				t.Errorf("expected %s but got %s", tt.protocol, creds.Info().SecurityProtocol)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/reporter"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ProtoFileError is an error in a proto file along with where it was found.
+type ProtoFileError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ProtoFileError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *ProtoFileError) Unwrap() error {
+	return e.Err
+}
+
+// ParseProtoFiles parses the files of the list, imports are resolved from the import paths and then from the directory of each file.
+// the errors of all the files are returned as ProtoFileErrors.
+func ParseProtoFiles(ctx context.Context, spec domain.ProtoFileListSpec) ([]protoreflect.FileDescriptor, error) {
+	importPaths, names := protoFileNames(spec)
+
+	var errs []error
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+		Reporter: reporter.NewReporter(func(err reporter.ErrorWithPos) error {
+			pos := err.GetPosition()
+			errs = append(errs, &ProtoFileError{File: pos.Filename, Line: pos.Line, Column: pos.Col, Err: err.Unwrap()})
+			// keep going to report the errors of all the files at once
+			return nil
+		}, nil),
+	}
+
+	files, err := compiler.Compile(ctx, names...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]protoreflect.FileDescriptor, 0, len(files))
+	for _, f := range files {
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// protoFileNames returns the import paths to use and the names of the files relative to them.
+func protoFileNames(spec domain.ProtoFileListSpec) ([]string, []string) {
+	importPaths := append([]string{}, spec.ImportPaths...)
+	names := make([]string, 0, len(spec.Files))
+
+	for _, f := range spec.Files {
+		name := ""
+		for _, dir := range importPaths {
+			if rel, err := filepath.Rel(dir, f.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				name = filepath.ToSlash(rel)
+				break
+			}
+		}
+
+		if name == "" {
+			dir := filepath.Dir(f.Path)
+			importPaths = append(importPaths, dir)
+			name = filepath.Base(f.Path)
+		}
+		names = append(names, name)
+	}
+
+	return importPaths, names
+}
+
+// ProtoFileInfo returns the package and the services of the parsed file, so the file can be listed without parsing it again.
+func ProtoFileInfo(fd protoreflect.FileDescriptor) (string, []string) {
+	services := make([]string, 0, fd.Services().Len())
+	for i := 0; i < fd.Services().Len(); i++ {
+		services = append(services, string(fd.Services().Get(i).Name()))
+	}
+	return string(fd.Package()), services
+}
+
+// filesSource reads the services from parsed proto files.
+type filesSource struct {
+	services map[string]protoreflect.ServiceDescriptor
+}
+
+func newFilesSource(files []protoreflect.FileDescriptor) *filesSource {
+	src := &filesSource{services: make(map[string]protoreflect.ServiceDescriptor)}
+	for _, f := range files {
+		for i := 0; i < f.Services().Len(); i++ {
+			sd := f.Services().Get(i)
+			src.services[string(sd.FullName())] = sd
+		}
+	}
+	return src
+}
+
+func (f *filesSource) listServices() ([]protoreflect.ServiceDescriptor, error) {
+	services := make([]protoreflect.ServiceDescriptor, 0, len(f.services))
+	for _, sd := range f.services {
+		services = append(services, sd)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].FullName() < services[j].FullName()
+	})
+	return services, nil
+}
+
+func (f *filesSource) findService(name string) (protoreflect.ServiceDescriptor, error) {
+	sd, ok := f.services[name]
+	if !ok {
+		return nil, fmt.Errorf("service %s not found in the proto files", name)
+	}
+	return sd, nil
+}
+
+func (f *filesSource) close() {}
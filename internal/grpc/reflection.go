@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// reflectionService is the service of server reflection itself, it is not listed as one of the host services.
+const reflectionService = "grpc.reflection."
+
+// reflectionSource reads the services from the host using server reflection.
+type reflectionSource struct {
+	client *grpcreflect.Client
+}
+
+func newReflectionSource(ctx context.Context, conn *grpc.ClientConn) *reflectionSource {
+	return &reflectionSource{client: grpcreflect.NewClientAuto(ctx, conn)}
+}
+
+func (r *reflectionSource) listServices() ([]protoreflect.ServiceDescriptor, error) {
+	names, err := r.client.ListServices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services using server reflection: %w", err)
+	}
+	sort.Strings(names)
+
+	services := make([]protoreflect.ServiceDescriptor, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, reflectionService) {
+			continue
+		}
+
+		sd, err := r.findService(name)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, sd)
+	}
+
+	return services, nil
+}
+
+func (r *reflectionSource) findService(name string) (protoreflect.ServiceDescriptor, error) {
+	sd, err := r.client.ResolveService(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service %s: %w", name, err)
+	}
+	return sd.UnwrapService(), nil
+}
+
+func (r *reflectionSource) close() {
+	r.client.Reset()
+}
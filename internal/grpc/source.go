@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// descriptorSource provides the descriptors of the services of a host.
+type descriptorSource interface {
+	// listServices returns the services sorted by name
+	listServices() ([]protoreflect.ServiceDescriptor, error)
+	findService(name string) (protoreflect.ServiceDescriptor, error)
+	close()
+}
+
+// newDescriptorSource returns the source of the request, which is either server reflection over conn or the proto files of the workspace.
+func (s *Service) newDescriptorSource(ctx context.Context, spec *domain.GRPCRequestSpec, conn *grpc.ClientConn) (descriptorSource, error) {
+	if spec.ServerReflection {
+		return newReflectionSource(ctx, conn), nil
+	}
+
+	if s.protoFiles == nil {
+		return nil, fmt.Errorf("server reflection is disabled and there are no proto files")
+	}
+
+	list, err := s.protoFiles.GetProtoFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list.Spec.Files) == 0 {
+		return nil, fmt.Errorf("server reflection is disabled and there are no proto files in the workspace")
+	}
+
+	files, err := ParseProtoFiles(ctx, list.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return newFilesSource(files), nil
+}
+
+// findMethod returns the descriptor of the method with the given full name.
+func findMethod(src descriptorSource, method string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid method name %s, expected package.Service/Method", method)
+	}
+
+	sd, err := src.findService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(methodName))
+	if md == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+	return md, nil
+}
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/chapar-rest/chapar/internal/rest"
+	// registers the standard error details, like BadRequest and RetryInfo, so they can be decoded
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type statusJSON struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// statusBody returns the status of a failed call in JSON, the details are decoded when their type is known.
+func statusBody(st *status.Status) string {
+	out := statusJSON{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	}
+
+	for _, detail := range st.Proto().GetDetails() {
+		data, err := protojson.Marshal(detail)
+		if err != nil {
+			// the type is not known, so only its raw value can be shown
+			data, _ = json.Marshal(map[string]string{
+				"@type": detail.GetTypeUrl(),
+				"value": base64.StdEncoding.EncodeToString(detail.GetValue()),
+			})
+		}
+		out.Details = append(out.Details, data)
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return st.Message()
+	}
+
+	body, err := rest.PrettyJSON(data)
+	if err != nil {
+		return string(data)
+	}
+	return body
+}
@@ -0,0 +1,198 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/rest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	StreamDirectionIn  = "in"
+	StreamDirectionOut = "out"
+)
+
+// StreamMessage is a message sent or received on a stream.
+type StreamMessage struct {
+	Direction string
+	// Body is the message in JSON
+	Body string
+	Time time.Time
+}
+
+// Stream is an open call of a streaming method.
+type Stream struct {
+	// ClientStreaming is true when several messages can be sent before half-closing the stream.
+	ClientStreaming bool
+	ServerStreaming bool
+
+	conn      *grpc.ClientConn
+	stream    grpc.ClientStream
+	method    protoreflect.MethodDescriptor
+	variables map[string]string
+	start     time.Time
+	cancel    context.CancelFunc
+
+	sendMx     *sync.Mutex
+	halfClosed bool
+}
+
+// OpenStream opens a call of the streaming method of the request with the given id using the given environment.
+// the call lasts until ctx is canceled or the server ends it, Receive should be called to read it until the end.
+func (s *Service) OpenStream(ctx context.Context, requestID, activeEnvironmentID string) (*Stream, error) {
+	spec, variables, err := s.prepare(requestID, activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Method == "" {
+		return nil, errors.New("no method is selected")
+	}
+
+	conn, err := dial(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := s.resolveMethod(ctx, conn, spec, spec.Method)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if !md.IsStreamingClient() && !md.IsStreamingServer() {
+		conn.Close()
+		return nil, fmt.Errorf("method %s is not a streaming method", spec.Method)
+	}
+
+	desc := &grpc.StreamDesc{
+		StreamName:    string(md.Name()),
+		ClientStreams: md.IsStreamingClient(),
+		ServerStreams: md.IsStreamingServer(),
+	}
+
+	// the deadline covers the whole stream, it is released once the stream ends
+	ctx, cancel := callContext(ctx, spec)
+
+	start := time.Now()
+	stream, err := conn.NewStream(ctx, desc, invokePath(spec.Method))
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+
+	return &Stream{
+		ClientStreaming: md.IsStreamingClient(),
+		ServerStreaming: md.IsStreamingServer(),
+		conn:            conn,
+		stream:          stream,
+		method:          md,
+		variables:       variables,
+		start:           start,
+		cancel:          cancel,
+		sendMx:          &sync.Mutex{},
+	}, nil
+}
+
+// Send sends a JSON message after applying the environment variables, it returns the sent message.
+func (s *Stream) Send(body string) (StreamMessage, error) {
+	s.sendMx.Lock()
+	defer s.sendMx.Unlock()
+
+	if s.halfClosed {
+		return StreamMessage{}, errors.New("stream is half-closed, no more messages can be sent")
+	}
+
+	msg := dynamicpb.NewMessage(s.method.Input())
+	if err := unmarshalMessage(rest.ReplaceText(body, s.variables), msg); err != nil {
+		return StreamMessage{}, err
+	}
+
+	if err := s.stream.SendMsg(msg); err != nil {
+		// the real error is returned by Receive
+		if errors.Is(err, io.EOF) {
+			return StreamMessage{}, errors.New("stream is closed")
+		}
+		return StreamMessage{}, err
+	}
+
+	sent, err := marshalMessage(msg)
+	if err != nil {
+		return StreamMessage{}, err
+	}
+
+	return StreamMessage{Direction: StreamDirectionOut, Body: sent, Time: time.Now()}, nil
+}
+
+// CloseSend half-closes the stream, telling the server no more messages are sent.
+func (s *Stream) CloseSend() error {
+	s.sendMx.Lock()
+	defer s.sendMx.Unlock()
+
+	if s.halfClosed {
+		return nil
+	}
+	s.halfClosed = true
+	return s.stream.CloseSend()
+}
+
+// Receive calls onMessage for every received message until the call ends and returns its headers, trailers and status.
+// statuses other than OK are reported in the response, the error is only for failures which have no status.
+func (s *Stream) Receive(onMessage func(msg StreamMessage)) (*Response, error) {
+	defer s.conn.Close()
+	defer s.cancel()
+
+	size := 0
+	var recvErr error
+	for {
+		msg := dynamicpb.NewMessage(s.method.Output())
+		if err := s.stream.RecvMsg(msg); err != nil {
+			recvErr = err
+			break
+		}
+
+		body, err := marshalMessage(msg)
+		if err != nil {
+			body = err.Error()
+		}
+
+		size += proto.Size(msg)
+		onMessage(StreamMessage{Direction: StreamDirectionIn, Body: body, Time: time.Now()})
+	}
+
+	// the headers are not available when the call failed before the server responded
+	header, _ := s.stream.Header()
+
+	res := &Response{
+		Headers:    metadataToKeyValue(header),
+		Trailers:   metadataToKeyValue(s.stream.Trailer()),
+		Status:     codes.OK.String(),
+		TimePassed: time.Since(s.start),
+		Size:       size,
+	}
+
+	if errors.Is(recvErr, io.EOF) {
+		return res, nil
+	}
+
+	st, ok := status.FromError(recvErr)
+	if !ok {
+		return res, recvErr
+	}
+
+	res.Body = statusBody(st)
+	res.Status = st.Code().String()
+	res.StatusMessage = st.Message()
+	return res, nil
+}
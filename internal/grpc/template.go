@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// templateMaxDepth limits the nested messages in a template, so recursive messages end.
+const templateMaxDepth = 5
+
+// messageTemplate returns the message in JSON with every field set to an example value, repeated fields and maps get one item.
+func messageTemplate(md protoreflect.MessageDescriptor) (string, error) {
+	msg := dynamicpb.NewMessage(md)
+	fillMessage(msg, 0)
+	return marshalMessage(msg)
+}
+
+func fillMessage(msg protoreflect.Message, depth int) {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if skipTemplateField(fd, depth) {
+			continue
+		}
+
+		switch {
+		case fd.IsMap():
+			m := msg.Mutable(fd).Map()
+			m.Set(exampleValue(fd.MapKey(), depth).MapKey(), exampleValue(fd.MapValue(), depth))
+		case fd.IsList():
+			list := msg.Mutable(fd).List()
+			list.Append(exampleValue(fd, depth))
+		default:
+			msg.Set(fd, exampleValue(fd, depth))
+		}
+	}
+}
+
+// skipTemplateField reports whether the field is left out of the template, it is then shown with its default value.
+func skipTemplateField(fd protoreflect.FieldDescriptor, depth int) bool {
+	// only the first field of a oneof is set, setting the others would clear it
+	if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() && oneof.Fields().Get(0) != fd {
+		return true
+	}
+
+	if fd.IsMap() {
+		fd = fd.MapValue()
+	}
+
+	md := fd.Message()
+	if md == nil {
+		return false
+	}
+
+	if depth >= templateMaxDepth {
+		return true
+	}
+
+	// well known types have their own JSON form, only the ones with a meaningful empty value are filled
+	if strings.HasPrefix(string(md.FullName()), "google.protobuf.") {
+		switch md.FullName() {
+		case "google.protobuf.Timestamp", "google.protobuf.Duration":
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+// exampleValue returns the value of a single item of the field.
+func exampleValue(fd protoreflect.FieldDescriptor, depth int) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := dynamicpb.NewMessage(fd.Message())
+		if !strings.HasPrefix(string(fd.Message().FullName()), "google.protobuf.") {
+			fillMessage(msg, depth+1)
+		}
+		return protoreflect.ValueOfMessage(msg)
+	case protoreflect.EnumKind:
+		if values := fd.Enum().Values(); values.Len() > 0 {
+			return protoreflect.ValueOfEnum(values.Get(0).Number())
+		}
+		return protoreflect.ValueOfEnum(0)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(nil)
+	}
+
+	return fd.Default()
+}
@@ -0,0 +1,319 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// histogramBuckets is the number of buckets the latencies are divided into.
+const histogramBuckets = 10
+
+// progressInterval is how often the stats of a running test are reported.
+const progressInterval = 250 * time.Millisecond
+
+// Options configures a load test, it stops once Requests are sent or Duration has passed, whichever comes first.
+type Options struct {
+	// Concurrency is the number of requests in flight at the same time
+	Concurrency int
+	Requests    int
+	Duration    time.Duration
+	// RPS limits the requests per second of all the workers together, zero sends them as fast as possible
+	RPS int
+}
+
+func (o Options) Validate() error {
+	if o.Concurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+
+	if o.Requests <= 0 && o.Duration <= 0 {
+		return errors.New("set the number of requests or the duration of the test")
+	}
+
+	if o.Requests < 0 || o.Duration < 0 || o.RPS < 0 {
+		return errors.New("requests, duration and rps can not be negative")
+	}
+	return nil
+}
+
+// Sender sends a request and returns the status code of its response.
+type Sender func(ctx context.Context) (int, error)
+
+// Bucket is the number of responses whose latency is in [From, To).
+type Bucket struct {
+	From  time.Duration
+	To    time.Duration
+	Count int
+}
+
+// Point is the number of requests completed in a second of the test and their mean latency.
+type Point struct {
+	Requests    int
+	Errors      int
+	MeanLatency time.Duration
+}
+
+// Stats is the state of a load test, latencies are of the requests which got a response.
+type Stats struct {
+	Elapsed time.Duration
+	// Total is the number of completed requests, including the ones which failed without a response
+	Total  int
+	Errors int
+	RPS    float64
+
+	Min  time.Duration
+	Mean time.Duration
+	Max  time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+
+	Histogram   []Bucket
+	StatusCodes map[int]int
+	// ErrorMessages is the number of requests failed with each error
+	ErrorMessages map[string]int
+	Timeline      []Point
+
+	// Done is set once the test is over
+	Done bool
+	// Stopped is set when the test was canceled before it completed
+	Stopped bool
+}
+
+// Run sends requests with send until the limits of opts are reached or ctx is canceled, onProgress is called
+// with the stats while the test runs and once it is done, it can be nil. requests in flight when ctx is canceled are not counted.
+func Run(ctx context.Context, send Sender, opts Options, onProgress func(Stats)) (Stats, error) {
+	if err := opts.Validate(); err != nil {
+		return Stats{}, err
+	}
+
+	rec := newRecorder(time.Now())
+
+	// stop is closed once the duration passed or ctx is canceled
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	closeStop := func() { stopOnce.Do(func() { close(stop) }) }
+
+	if opts.Duration > 0 {
+		timer := time.AfterFunc(opts.Duration, closeStop)
+		defer timer.Stop()
+	}
+
+	var ticks <-chan time.Time
+	if opts.RPS > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(opts.RPS))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	var sent atomic.Int64
+	next := func() bool {
+		select {
+		case <-stop:
+			return false
+		case <-ctx.Done():
+			return false
+		default:
+		}
+
+		if opts.Requests > 0 && sent.Add(1) > int64(opts.Requests) {
+			return false
+		}
+
+		if ticks != nil {
+			select {
+			case <-ticks:
+			case <-stop:
+				return false
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next() {
+				start := time.Now()
+				code, err := send(ctx)
+				end := time.Now()
+
+				// the requests stopped by canceling the test did not fail
+				if ctx.Err() != nil {
+					return
+				}
+				rec.add(code, end.Sub(start), err, end)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if onProgress != nil {
+				onProgress(rec.stats())
+			}
+		case <-done:
+			closeStop()
+			stats := rec.stats()
+			stats.Done = true
+			stats.Stopped = ctx.Err() != nil
+			if onProgress != nil {
+				onProgress(stats)
+			}
+			return stats, nil
+		}
+	}
+}
+
+type recorder struct {
+	mx      sync.Mutex
+	started time.Time
+
+	latencies   []time.Duration
+	total       int
+	errors      int
+	statusCodes map[int]int
+	messages    map[string]int
+	timeline    []second
+}
+
+// second holds the requests completed in a second of the test.
+type second struct {
+	requests int
+	errors   int
+	latency  time.Duration
+}
+
+func newRecorder(started time.Time) *recorder {
+	return &recorder{
+		started:     started,
+		statusCodes: make(map[int]int),
+		messages:    make(map[string]int),
+	}
+}
+
+func (r *recorder) add(code int, latency time.Duration, err error, at time.Time) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.total++
+	index := int(at.Sub(r.started) / time.Second)
+	for len(r.timeline) <= index {
+		r.timeline = append(r.timeline, second{})
+	}
+	s := &r.timeline[index]
+	s.requests++
+
+	if err != nil {
+		r.errors++
+		r.messages[err.Error()]++
+		s.errors++
+		return
+	}
+
+	r.statusCodes[code]++
+	r.latencies = append(r.latencies, latency)
+	s.latency += latency
+}
+
+func (r *recorder) stats() Stats {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	stats := Stats{
+		Elapsed:       time.Since(r.started),
+		Total:         r.total,
+		Errors:        r.errors,
+		StatusCodes:   make(map[int]int, len(r.statusCodes)),
+		ErrorMessages: make(map[string]int, len(r.messages)),
+		Timeline:      make([]Point, 0, len(r.timeline)),
+	}
+
+	if stats.Elapsed > 0 {
+		stats.RPS = float64(r.total) / stats.Elapsed.Seconds()
+	}
+
+	for k, v := range r.statusCodes {
+		stats.StatusCodes[k] = v
+	}
+	for k, v := range r.messages {
+		stats.ErrorMessages[k] = v
+	}
+
+	for _, s := range r.timeline {
+		p := Point{Requests: s.requests, Errors: s.errors}
+		if responses := s.requests - s.errors; responses > 0 {
+			p.MeanLatency = s.latency / time.Duration(responses)
+		}
+		stats.Timeline = append(stats.Timeline, p)
+	}
+
+	if len(r.latencies) == 0 {
+		return stats
+	}
+
+	sorted := make([]time.Duration, len(r.latencies))
+	copy(sorted, r.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Mean = sum / time.Duration(len(sorted))
+	stats.P50 = percentile(sorted, 0.50)
+	stats.P90 = percentile(sorted, 0.90)
+	stats.P99 = percentile(sorted, 0.99)
+	stats.Histogram = histogram(sorted)
+	return stats
+}
+
+// percentile returns the latency below which p of the sorted latencies are.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(index, 0)]
+}
+
+// histogram divides the range of the sorted latencies into buckets of the same width, the last one includes the max.
+func histogram(sorted []time.Duration) []Bucket {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	width := (hi - lo) / histogramBuckets
+	if width <= 0 {
+		return []Bucket{{From: lo, To: hi, Count: len(sorted)}}
+	}
+
+	buckets := make([]Bucket, histogramBuckets)
+	for i := range buckets {
+		buckets[i].From = lo + time.Duration(i)*width
+		buckets[i].To = buckets[i].From + width
+	}
+	buckets[len(buckets)-1].To = hi
+
+	for _, l := range sorted {
+		i := min(int((l-lo)/width), len(buckets)-1)
+		buckets[i].Count++
+	}
+	return buckets
+}
@@ -0,0 +1,95 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var count atomic.Int64
+	send := func(ctx context.Context) (int, error) {
+		n := count.Add(1)
+		time.Sleep(time.Duration(n%5) * time.Millisecond)
+		switch {
+		case n%10 == 0:
+			return 0, errors.New("connection refused")
+		case n%5 == 0:
+			return http.StatusInternalServerError, nil
+		}
+		return http.StatusOK, nil
+	}
+
+	var progress []Stats
+	stats, err := Run(context.Background(), send, Options{Concurrency: 4, Requests: 100}, func(s Stats) {
+		progress = append(progress, s)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Total != 100 || stats.Errors != 10 || !stats.Done || stats.Stopped {
+		t.Fatalf("expected 100 requests with 10 errors, got %+v", stats)
+	}
+
+	if stats.StatusCodes[http.StatusOK] != 80 || stats.StatusCodes[http.StatusInternalServerError] != 10 || stats.ErrorMessages["connection refused"] != 10 {
+		t.Errorf("unexpected distribution, %v and %v", stats.StatusCodes, stats.ErrorMessages)
+	}
+
+	if stats.Min > stats.P50 || stats.P50 > stats.P90 || stats.P90 > stats.P99 || stats.P99 > stats.Max {
+		t.Errorf("expected ordered percentiles, got %+v", stats)
+	}
+
+	histogramCount := 0
+	for _, b := range stats.Histogram {
+		histogramCount += b.Count
+	}
+	if len(stats.Histogram) != histogramBuckets || histogramCount != 90 {
+		t.Errorf("expected the responses in %d buckets, got %+v", histogramBuckets, stats.Histogram)
+	}
+
+	if len(progress) == 0 || !progress[len(progress)-1].Done {
+		t.Errorf("expected the final stats to be reported")
+	}
+}
+
+func TestRun_DurationAndRPS(t *testing.T) {
+	send := func(ctx context.Context) (int, error) {
+		return http.StatusOK, nil
+	}
+
+	stats, err := Run(context.Background(), send, Options{Concurrency: 10, Duration: 500 * time.Millisecond, RPS: 20}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 20 requests per second for half a second, with some room for slow machines
+	if stats.Total < 5 || stats.Total > 11 {
+		t.Errorf("expected about 10 requests, got %d", stats.Total)
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	send := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	stats, err := Run(ctx, send, Options{Concurrency: 2, Duration: time.Minute}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !stats.Stopped || stats.Total != 0 {
+		t.Errorf("expected the canceled requests not to be counted, got %+v", stats)
+	}
+
+	if _, err := Run(context.Background(), send, Options{Concurrency: 1}, nil); err == nil {
+		t.Errorf("expected an error without requests or duration")
+	}
+}
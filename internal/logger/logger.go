@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+const (
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+var (
+	mx          sync.Mutex
+	subscribers []func(domain.Log)
+)
+
+// Subscribe registers f to receive the logs, like the output of scripts, it is called on the goroutine which logged.
+func Subscribe(f func(domain.Log)) {
+	mx.Lock()
+	defer mx.Unlock()
+	subscribers = append(subscribers, f)
+}
+
+func Info(message string) {
+	log(LevelInfo, message)
+}
+
+func Infof(format string, args ...any) {
+	log(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func Warn(message string) {
+	log(LevelWarn, message)
+}
+
+func Error(message string) {
+	log(LevelError, message)
+}
+
+func Errorf(format string, args ...any) {
+	log(LevelError, fmt.Sprintf(format, args...))
+}
+
+func log(level, message string) {
+	mx.Lock()
+	subs := make([]func(domain.Log), len(subscribers))
+	copy(subs, subscribers)
+	mx.Unlock()
+
+	l := domain.Log{Time: time.Now(), Level: level, Message: message}
+	for _, f := range subs {
+		f(l)
+	}
+}
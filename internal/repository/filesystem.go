@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/chapar-rest/chapar/internal/domain"
@@ -19,12 +20,20 @@ const (
 	collectionsDir  = "collections"
 	requestsDir     = "requests"
 	preferencesDir  = "preferences"
+	cookiesDir      = "cookies"
+	historyDir      = "history"
+
+	protoFilesFile = "protofiles.yaml"
+	globalsFile    = "globals.yaml"
 )
 
 var _ Repository = &Filesystem{}
 
 type Filesystem struct {
 	ActiveWorkspace *domain.Workspace
+
+	// workspaceDir is set when the filesystem is opened on a workspace directory outside the config directory.
+	workspaceDir string
 }
 
 func NewFilesystem() (*Filesystem, error) {
@@ -61,6 +70,26 @@ func NewFilesystem() (*Filesystem, error) {
 	return fs, nil
 }
 
+// NewFilesystemFromDir opens the workspace in the given directory, like a workspace checked out in a CI pipeline.
+// the config of the user is neither read nor changed.
+func NewFilesystemFromDir(dir string) (*Filesystem, error) {
+	if !dirExist(dir) {
+		return nil, fmt.Errorf("workspace directory %s does not exist", dir)
+	}
+
+	filePath := filepath.Join(dir, "_workspace.yaml")
+	ws, err := LoadFromYaml[domain.Workspace](filePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		ws = domain.NewWorkspace(filepath.Base(dir))
+	}
+
+	ws.FilePath = filePath
+	return &Filesystem{ActiveWorkspace: ws, workspaceDir: dir}, nil
+}
+
 func (f *Filesystem) SetActiveWorkspace(workspace *domain.Workspace) error {
 	config, err := f.GetConfig()
 	if err != nil {
@@ -319,13 +348,27 @@ func (f *Filesystem) loadCollection(collectionPath string) (*domain.Collection,
 	return collection, nil
 }
 
-func (f *Filesystem) GetCollectionsDir() (string, error) {
+// getWorkspaceDir returns the directory of the active workspace.
+func (f *Filesystem) getWorkspaceDir() (string, error) {
+	if f.workspaceDir != "" {
+		return f.workspaceDir, nil
+	}
+
 	dir, err := CreateConfigDir()
 	if err != nil {
 		return "", err
 	}
 
-	cdir := filepath.Join(dir, f.ActiveWorkspace.MetaData.Name, collectionsDir)
+	return filepath.Join(dir, f.ActiveWorkspace.MetaData.Name), nil
+}
+
+func (f *Filesystem) GetCollectionsDir() (string, error) {
+	dir, err := f.getWorkspaceDir()
+	if err != nil {
+		return "", err
+	}
+
+	cdir := filepath.Join(dir, collectionsDir)
 	if err := makeDir(cdir); err != nil {
 		return "", err
 	}
@@ -435,12 +478,12 @@ func (f *Filesystem) GetEnvironment(filepath string) (*domain.Environment, error
 }
 
 func (f *Filesystem) GetEnvironmentDir() (string, error) {
-	dir, err := CreateConfigDir()
+	dir, err := f.getWorkspaceDir()
 	if err != nil {
 		return "", err
 	}
 
-	envDir := filepath.Join(dir, f.ActiveWorkspace.MetaData.Name, environmentsDir)
+	envDir := filepath.Join(dir, environmentsDir)
 	if err := makeDir(envDir); err != nil {
 		return "", err
 	}
@@ -478,23 +521,227 @@ func (f *Filesystem) DeleteEnvironment(env *domain.Environment) error {
 	return os.Remove(env.FilePath)
 }
 
+func (f *Filesystem) getCookiesDir() (string, error) {
+	dir, err := f.getWorkspaceDir()
+	if err != nil {
+		return "", err
+	}
+
+	cDir := filepath.Join(dir, cookiesDir)
+	if err := makeDir(cDir); err != nil {
+		return "", err
+	}
+
+	return cDir, nil
+}
+
+// LoadCookieJar loads the cookie jar with the given name, an empty jar is returned when it does not exist yet.
+func (f *Filesystem) LoadCookieJar(name string) (*domain.CookieJar, error) {
+	dir, err := f.getCookiesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	jar, err := LoadFromYaml[domain.CookieJar](filepath.Join(dir, name+".yaml"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.NewCookieJar(name), nil
+		}
+		return nil, err
+	}
+
+	return jar, nil
+}
+
+func (f *Filesystem) UpdateCookieJar(jar *domain.CookieJar) error {
+	dir, err := f.getCookiesDir()
+	if err != nil {
+		return err
+	}
+
+	return SaveToYaml(filepath.Join(dir, jar.MetaData.Name+".yaml"), jar)
+}
+
+// LoadGlobals loads the global variables of the workspace, empty globals are returned when they are not saved yet.
+func (f *Filesystem) LoadGlobals() (*domain.Globals, error) {
+	dir, err := f.getWorkspaceDir()
+	if err != nil {
+		return nil, err
+	}
+
+	globals, err := LoadFromYaml[domain.Globals](filepath.Join(dir, globalsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.NewGlobals(), nil
+		}
+		return nil, err
+	}
+
+	return globals, nil
+}
+
+func (f *Filesystem) UpdateGlobals(globals *domain.Globals) error {
+	dir, err := f.getWorkspaceDir()
+	if err != nil {
+		return err
+	}
+
+	return SaveToYaml(filepath.Join(dir, globalsFile), globals)
+}
+
+func (f *Filesystem) getProtoFilesPath() (string, error) {
+	dir, err := f.getWorkspaceDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := makeDir(dir); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, protoFilesFile), nil
+}
+
+// LoadProtoFiles loads the proto files of the active workspace, an empty list is returned when there is none yet.
+func (f *Filesystem) LoadProtoFiles() (*domain.ProtoFileList, error) {
+	path, err := f.getProtoFilesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := LoadFromYaml[domain.ProtoFileList](path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.NewProtoFileList(f.ActiveWorkspace.MetaData.Name), nil
+		}
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (f *Filesystem) UpdateProtoFiles(list *domain.ProtoFileList) error {
+	path, err := f.getProtoFilesPath()
+	if err != nil {
+		return err
+	}
+
+	return SaveToYaml(path, list)
+}
+
+func (f *Filesystem) getHistoryDir() (string, error) {
+	dir, err := f.getWorkspaceDir()
+	if err != nil {
+		return "", err
+	}
+
+	hDir := filepath.Join(dir, historyDir)
+	if err := makeDir(hDir); err != nil {
+		return "", err
+	}
+
+	return hDir, nil
+}
+
+// getResponseHistoryDir returns the directory of the history of the request, each response is stored in its own file
+// so adding a response does not rewrite the others.
+func (f *Filesystem) getResponseHistoryDir(requestID string) (string, error) {
+	dir, err := f.getHistoryDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, requestID), nil
+}
+
+// LoadResponseHistory loads the response history of the request, an empty history is returned when it does not exist yet.
+func (f *Filesystem) LoadResponseHistory(requestID string) (*domain.ResponseHistory, error) {
+	dir, err := f.getResponseHistoryDir(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := domain.NewResponseHistory(requestID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return history, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+
+		response, err := LoadFromYaml[domain.HTTPResponse](filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		history.Spec.Responses = append(history.Spec.Responses, *response)
+	}
+
+	sort.Slice(history.Spec.Responses, func(i, j int) bool {
+		return history.Spec.Responses[i].Time.After(history.Spec.Responses[j].Time)
+	})
+
+	return history, nil
+}
+
+func (f *Filesystem) AddResponseHistoryEntry(requestID string, response domain.HTTPResponse) error {
+	dir, err := f.getResponseHistoryDir(requestID)
+	if err != nil {
+		return err
+	}
+
+	if err := makeDir(dir); err != nil {
+		return err
+	}
+
+	return SaveToYaml(filepath.Join(dir, response.ID+".yaml"), &response)
+}
+
+func (f *Filesystem) DeleteResponseHistoryEntries(requestID string, responseIDs []string) error {
+	dir, err := f.getResponseHistoryDir(requestID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range responseIDs {
+		if err := os.Remove(filepath.Join(dir, id+".yaml")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Filesystem) DeleteResponseHistory(requestID string) error {
+	dir, err := f.getResponseHistoryDir(requestID)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
+
 func (f *Filesystem) ReadPreferencesData() (*domain.Preferences, error) {
-	dir, err := GetConfigDir()
+	dir, err := f.getWorkspaceDir()
 	if err != nil {
 		return nil, err
 	}
-	pdir := filepath.Join(dir, f.ActiveWorkspace.MetaData.Name, preferencesDir)
+	pdir := filepath.Join(dir, preferencesDir)
 	filePath := filepath.Join(pdir, "preferences.yaml")
 	return LoadFromYaml[domain.Preferences](filePath)
 }
 
 func (f *Filesystem) UpdatePreferences(pref *domain.Preferences) error {
-	dir, err := GetConfigDir()
+	dir, err := f.getWorkspaceDir()
 	if err != nil {
 		return err
 	}
 
-	pdir := filepath.Join(dir, f.ActiveWorkspace.MetaData.Name, preferencesDir)
+	pdir := filepath.Join(dir, preferencesDir)
 	if err := makeDir(pdir); err != nil {
 		return err
 	}
@@ -554,12 +801,12 @@ func (f *Filesystem) GetRequest(filepath string) (*domain.Request, error) {
 }
 
 func (f *Filesystem) GetRequestsDir() (string, error) {
-	dir, err := CreateConfigDir()
+	dir, err := f.getWorkspaceDir()
 	if err != nil {
 		return "", err
 	}
 
-	rdir := filepath.Join(dir, f.ActiveWorkspace.MetaData.Name, requestsDir)
+	rdir := filepath.Join(dir, requestsDir)
 	if err := makeDir(rdir); err != nil {
 		return "", err
 	}
@@ -23,6 +23,20 @@ type Repository interface {
 	DeleteEnvironment(env *domain.Environment) error
 	GetNewEnvironmentFilePath(name string) (*FilePath, error)
 
+	LoadCookieJar(name string) (*domain.CookieJar, error)
+	UpdateCookieJar(jar *domain.CookieJar) error
+
+	LoadGlobals() (*domain.Globals, error)
+	UpdateGlobals(globals *domain.Globals) error
+
+	LoadProtoFiles() (*domain.ProtoFileList, error)
+	UpdateProtoFiles(list *domain.ProtoFileList) error
+
+	LoadResponseHistory(requestID string) (*domain.ResponseHistory, error)
+	AddResponseHistoryEntry(requestID string, response domain.HTTPResponse) error
+	DeleteResponseHistoryEntries(requestID string, responseIDs []string) error
+	DeleteResponseHistory(requestID string) error
+
 	ReadPreferencesData() (*domain.Preferences, error)
 	UpdatePreferences(pref *domain.Preferences) error
 
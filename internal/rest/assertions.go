@@ -0,0 +1,157 @@
+package rest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// evaluateAssertions checks the enabled assertions against the response, disabled ones have no result.
+func evaluateAssertions(assertions []domain.Assertion, response *Response) []domain.AssertionResult {
+	if len(assertions) == 0 || response == nil {
+		return nil
+	}
+
+	results := make([]domain.AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		if !a.Enable {
+			continue
+		}
+
+		passed, message := evaluateAssertion(a, response)
+		results = append(results, domain.AssertionResult{Assertion: a, Passed: passed, Message: message})
+	}
+
+	return results
+}
+
+func evaluateAssertion(a domain.Assertion, response *Response) (bool, string) {
+	switch a.Type {
+	case domain.AssertionTypeStatus:
+		return assertStatus(a, response.StatusCode)
+	case domain.AssertionTypeJSONPath:
+		return assertJSONPath(a, response)
+	case domain.AssertionTypeHeader:
+		return assertHeader(a, response.Headers)
+	case domain.AssertionTypeBodyContains:
+		if strings.Contains(string(response.Body), a.Value) {
+			return true, "body contains the text"
+		}
+		return false, "body does not contain the text"
+	case domain.AssertionTypeLatency:
+		ms, err := strconv.ParseInt(strings.TrimSpace(a.Value), 10, 64)
+		if err != nil {
+			return false, fmt.Sprintf("invalid latency %q, it should be a number of milliseconds", a.Value)
+		}
+
+		if response.TimePassed < time.Duration(ms)*time.Millisecond {
+			return true, fmt.Sprintf("took %s", response.TimePassed)
+		}
+		return false, fmt.Sprintf("took %s", response.TimePassed)
+	}
+
+	return false, fmt.Sprintf("unknown assertion type %q", a.Type)
+}
+
+func assertStatus(a domain.Assertion, statusCode int) (bool, string) {
+	actual := fmt.Sprintf("status is %d", statusCode)
+
+	switch a.Operator {
+	case domain.AssertionOperatorEquals:
+		expected, err := strconv.Atoi(strings.TrimSpace(a.Value))
+		if err != nil {
+			return false, fmt.Sprintf("invalid status code %q", a.Value)
+		}
+		return statusCode == expected, actual
+	case domain.AssertionOperatorInRange:
+		from, to, ok := parseRange(a.Value)
+		if !ok {
+			return false, fmt.Sprintf("invalid range %q, it should be like 200-299", a.Value)
+		}
+		return statusCode >= from && statusCode <= to, actual
+	}
+
+	return false, fmt.Sprintf("unknown operator %q", a.Operator)
+}
+
+// parseRange parses ranges like 200-299, the bounds are inclusive.
+func parseRange(value string) (int, int, bool) {
+	fromText, toText, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	from, err := strconv.Atoi(strings.TrimSpace(fromText))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	to, err := strconv.Atoi(strings.TrimSpace(toText))
+	if err != nil || to < from {
+		return 0, 0, false
+	}
+
+	return from, to, true
+}
+
+func assertJSONPath(a domain.Assertion, response *Response) (bool, string) {
+	if response.Binary || response.Truncated || !IsJSON(string(response.Body)) {
+		return false, "body is not json"
+	}
+
+	data, err := GetJSONPATH(string(response.Body), a.Target)
+	if err != nil {
+		if a.Operator == domain.AssertionOperatorExists {
+			return false, fmt.Sprintf("%s does not exist", a.Target)
+		}
+		return false, err.Error()
+	}
+
+	if a.Operator == domain.AssertionOperatorExists {
+		return true, fmt.Sprintf("%s exists", a.Target)
+	}
+
+	return compareValue(a, FormatValue(data))
+}
+
+func assertHeader(a domain.Assertion, headers map[string]string) (bool, string) {
+	value, found := "", false
+	for k, v := range headers {
+		if strings.EqualFold(k, a.Target) {
+			value, found = v, true
+			break
+		}
+	}
+
+	if !found {
+		return false, fmt.Sprintf("header %s is not present", a.Target)
+	}
+
+	if a.Operator == domain.AssertionOperatorExists {
+		return true, fmt.Sprintf("header %s is present", a.Target)
+	}
+
+	return compareValue(a, value)
+}
+
+// compareValue checks the actual value with the equals and matches operators.
+func compareValue(a domain.Assertion, actual string) (bool, string) {
+	message := fmt.Sprintf("value is %q", actual)
+
+	switch a.Operator {
+	case domain.AssertionOperatorEquals:
+		return actual == a.Value, message
+	case domain.AssertionOperatorMatches:
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			return false, fmt.Sprintf("invalid regular expression: %s", err)
+		}
+		return re.MatchString(actual), message
+	}
+
+	return false, fmt.Sprintf("unknown operator %q", a.Operator)
+}
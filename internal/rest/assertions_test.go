@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+func Test_evaluateAssertions(t *testing.T) {
+	response := &Response{
+		StatusCode: 201,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       []byte(`{"id": 7, "name": "chapar", "tags": ["a"], "empty": null}`),
+		TimePassed: 120 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name      string
+		assertion domain.Assertion
+		passed    bool
+	}{
+		{"status equals", domain.Assertion{Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorEquals, Value: "201"}, true},
+		{"status not equals", domain.Assertion{Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorEquals, Value: "200"}, false},
+		{"status in range", domain.Assertion{Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorInRange, Value: "200-299"}, true},
+		{"status out of range", domain.Assertion{Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorInRange, Value: "400-499"}, false},
+		{"invalid range", domain.Assertion{Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorInRange, Value: "2xx"}, false},
+		{"json string equals", domain.Assertion{Type: domain.AssertionTypeJSONPath, Target: "$.name", Operator: domain.AssertionOperatorEquals, Value: "chapar"}, true},
+		{"json number equals", domain.Assertion{Type: domain.AssertionTypeJSONPath, Target: "$.id", Operator: domain.AssertionOperatorEquals, Value: "7"}, true},
+		{"json matches", domain.Assertion{Type: domain.AssertionTypeJSONPath, Target: "$.name", Operator: domain.AssertionOperatorMatches, Value: "^cha"}, true},
+		{"json exists", domain.Assertion{Type: domain.AssertionTypeJSONPath, Target: "$.tags[0]", Operator: domain.AssertionOperatorExists}, true},
+		{"json null exists", domain.Assertion{Type: domain.AssertionTypeJSONPath, Target: "$.empty", Operator: domain.AssertionOperatorExists}, true},
+		{"json missing", domain.Assertion{Type: domain.AssertionTypeJSONPath, Target: "$.missing", Operator: domain.AssertionOperatorExists}, false},
+		{"header present", domain.Assertion{Type: domain.AssertionTypeHeader, Target: "content-type", Operator: domain.AssertionOperatorExists}, true},
+		{"header missing", domain.Assertion{Type: domain.AssertionTypeHeader, Target: "X-Request-ID", Operator: domain.AssertionOperatorExists}, false},
+		{"header equals", domain.Assertion{Type: domain.AssertionTypeHeader, Target: "Content-Type", Operator: domain.AssertionOperatorEquals, Value: "application/json"}, true},
+		{"body contains", domain.Assertion{Type: domain.AssertionTypeBodyContains, Operator: domain.AssertionOperatorContains, Value: `"chapar"`}, true},
+		{"body does not contain", domain.Assertion{Type: domain.AssertionTypeBodyContains, Operator: domain.AssertionOperatorContains, Value: "error"}, false},
+		{"latency under", domain.Assertion{Type: domain.AssertionTypeLatency, Operator: domain.AssertionOperatorLessThan, Value: "500"}, true},
+		{"latency over", domain.Assertion{Type: domain.AssertionTypeLatency, Operator: domain.AssertionOperatorLessThan, Value: "100"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion.Enable = true
+			results := evaluateAssertions([]domain.Assertion{tt.assertion}, response)
+			if len(results) != 1 {
+				t.Fatalf("expected one result but got %d", len(results))
+			}
+
+			if results[0].Passed != tt.passed {
+				t.Errorf("expected passed to be %v but got %v, %s", tt.passed, results[0].Passed, results[0].Message)
+			}
+		})
+	}
+
+	disabled := domain.Assertion{Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorEquals, Value: "200"}
+	if results := evaluateAssertions([]domain.Assertion{disabled}, response); len(results) != 0 {
+		t.Errorf("expected disabled assertions to be skipped but got %+v", results)
+	}
+}
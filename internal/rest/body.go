@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// responsePreviewSize is how much of the body is kept in memory when the response is larger than the max response size.
+const responsePreviewSize = 256 << 10
+
+// readBody reads the body in memory when it is not larger than maxSize,
+// larger bodies are written to a temp file and only their beginning is returned along with the path of the file.
+func readBody(body io.Reader, maxSize int64) ([]byte, string, int64, error) {
+	b := &bodyBuffer{maxSize: maxSize}
+	_, err := io.Copy(b, body)
+	return b.finish(err)
+}
+
+// bodyBuffer keeps the body in memory up to maxSize, once it is larger the whole body is written to a temp file.
+type bodyBuffer struct {
+	maxSize int64
+	buf     bytes.Buffer
+	file    *os.File
+	size    int64
+}
+
+func (b *bodyBuffer) Write(p []byte) (int, error) {
+	b.size += int64(len(p))
+	if b.file == nil && b.size <= b.maxSize {
+		return b.buf.Write(p)
+	}
+
+	n := 0
+	if b.file == nil {
+		file, err := os.CreateTemp("", "chapar-response-*")
+		if err != nil {
+			return 0, err
+		}
+		b.file = file
+
+		// the buffer is filled up, so its beginning can be used as the preview
+		n = int(max(b.maxSize-int64(b.buf.Len()), 0))
+		n = min(n, len(p))
+		b.buf.Write(p[:n])
+		if _, err := file.Write(b.buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	m, err := b.file.Write(p[n:])
+	return n + m, err
+}
+
+// finish returns the body, or its beginning and the path of the file holding all of it. err is the error of reading the body.
+func (b *bodyBuffer) finish(err error) ([]byte, string, int64, error) {
+	if b.file == nil {
+		if err != nil {
+			return nil, "", 0, err
+		}
+		return b.buf.Bytes(), "", b.size, nil
+	}
+
+	if closeErr := b.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(b.file.Name())
+		return nil, "", 0, err
+	}
+
+	preview := b.buf.Bytes()[:min(b.buf.Len(), responsePreviewSize)]
+	return preview, b.file.Name(), b.size, nil
+}
+
+// isBinary reports whether the body is not text, using the content type and sniffing the body when the type is unknown.
+func isBinary(contentType string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.Contains(mediaType, "json"),
+		strings.Contains(mediaType, "xml"),
+		strings.Contains(mediaType, "javascript"),
+		strings.Contains(mediaType, "yaml"),
+		strings.Contains(mediaType, "graphql"),
+		mediaType == "application/x-www-form-urlencoded":
+		return false
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "font/"),
+		mediaType == "application/octet-stream":
+		return true
+	}
+
+	// other types are text when their beginning is valid utf-8
+	sample := body
+	if len(sample) > 512 {
+		sample = sample[:512]
+		// a rune cut at the end of the sample is not invalid
+		for i := 0; i < utf8.UTFMax-1 && !utf8.Valid(sample); i++ {
+			sample = sample[:len(sample)-1]
+		}
+	}
+	return !utf8.Valid(sample) || bytes.IndexByte(sample, 0) >= 0
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_readBody(t *testing.T) {
+	small := strings.Repeat("a", 100)
+	body, file, size, err := readBody(strings.NewReader(small), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(body) != small || file != "" || size != 100 {
+		t.Errorf("expected the body to be read in memory, got %d bytes, file %q, size %d", len(body), file, size)
+	}
+
+	large := bytes.Repeat([]byte("b"), responsePreviewSize*2)
+	body, file, size, err = readBody(bytes.NewReader(large), responsePreviewSize)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(file)
+
+	if file == "" || size != int64(len(large)) || len(body) != responsePreviewSize {
+		t.Fatalf("expected the body to be written to a file with a preview, got %d bytes, file %q, size %d", len(body), file, size)
+	}
+
+	written, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(written, large) {
+		t.Errorf("expected the file to hold the whole body, got %d bytes", len(written))
+	}
+}
+
+func Test_isBinary(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		expected    bool
+	}{
+		{name: "json", contentType: "application/json; charset=utf-8", body: []byte(`{"a": 1}`), expected: false},
+		{name: "html", contentType: "text/html", body: []byte("<html></html>"), expected: false},
+		{name: "image", contentType: "image/png", body: png, expected: true},
+		{name: "sniffed image", contentType: "", body: png, expected: true},
+		{name: "sniffed text", contentType: "application/octet-stream", body: []byte("plain text"), expected: false},
+		{name: "unknown text", contentType: "application/x-custom", body: []byte("héllo"), expected: false},
+		{name: "unknown binary", contentType: "application/x-custom", body: []byte{0x00, 0xff, 0xfe}, expected: true},
+		{name: "empty", contentType: "", body: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinary(tt.contentType, tt.body); got != tt.expected {
+				t.Errorf("expected %t but got %t", tt.expected, got)
+			}
+		})
+	}
+}
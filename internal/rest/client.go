@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// httpClient returns a client honoring the given settings, clients are cached so connections can be reused between requests.
+func (s *Service) httpClient(settings domain.HTTPClientSettings) (*http.Client, error) {
+	key := clientKey(settings)
+	if client, ok := s.clients.Get(key); ok {
+		return client, nil
+	}
+
+	client, err := newHTTPClient(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clients.Set(key, client)
+	return client, nil
+}
+
+func clientKey(settings domain.HTTPClientSettings) string {
+	key := fmt.Sprintf("%t|%d|%t|%s",
+		settings.ShouldFollowRedirects(),
+		settings.GetMaxRedirects(),
+		settings.ShouldSkipTLSVerify(),
+		settings.CACertPath,
+	)
+
+	if p := settings.Proxy; p != nil {
+		key += fmt.Sprintf("|%t|%s|%s|%s|%s|%s", p.Enabled, p.Type, p.Host, p.Username, p.Password, strings.Join(p.Bypass, ","))
+	}
+
+	return key
+}
+
+func newHTTPClient(settings domain.HTTPClientSettings) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := proxyFunc(settings.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = proxy
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !settings.ShouldFollowRedirects() {
+				return http.ErrUseLastResponse
+			}
+
+			if len(via) >= settings.GetMaxRedirects() {
+				return fmt.Errorf("stopped after %d redirects", settings.GetMaxRedirects())
+			}
+			return nil
+		},
+	}, nil
+}
+
+func newTLSConfig(settings domain.HTTPClientSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		// it is up to the user to disable the verification, e.g. for self-signed staging certificates
+		InsecureSkipVerify: settings.ShouldSkipTLSVerify(),
+	}
+
+	if settings.CACertPath != "" {
+		pool, err := LoadCertPool(settings.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// LoadCertPool returns the system cert pool extended with the certificates of the given PEM file.
+func LoadCertPool(caCertPath string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	data, err := os.ReadFile(caCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca certificate: %w", err)
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificate found in %s", caCertPath)
+	}
+
+	return pool, nil
+}
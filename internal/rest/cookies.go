@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+var _ http.CookieJar = &cookieJar{}
+
+// cookieJar exposes the persisted cookies of an environment as an http.CookieJar,
+// so cookies set during redirects are stored and sent as well.
+type cookieJar struct {
+	cookies *state.Cookies
+	name    string
+}
+
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if err := j.cookies.SetCookiesFromResponse(j.name, u, cookies, state.SourceRestService); err != nil {
+		fmt.Println("failed to store cookies", err)
+	}
+}
+
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	cookies, err := j.cookies.CookiesForURL(j.name, u)
+	if err != nil {
+		fmt.Println("failed to load cookies", err)
+		return nil
+	}
+	return cookies
+}
+
+// cookieJar returns the jar of the given environment, or nil when cookies are not persisted.
+func (s *Service) cookieJar(env *domain.Environment) http.CookieJar {
+	if s.cookies == nil {
+		return nil
+	}
+
+	name := domain.DefaultCookieJarName
+	if env != nil {
+		name = domain.CookieJarName(env.MetaData.ID)
+	}
+	return &cookieJar{cookies: s.cookies, name: name}
+}
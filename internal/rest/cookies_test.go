@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func Test_cookieJarDomains(t *testing.T) {
+	jar := &cookieJar{cookies: state.NewCookies(nil), name: domain.DefaultCookieJarName}
+
+	mustParse := func(raw string) *url.URL {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return u
+	}
+
+	// a response of one site can not set cookies of another one, or of every site under a public suffix
+	jar.SetCookies(mustParse("https://attacker.example/"), []*http.Cookie{
+		{Name: "session", Value: "stolen", Domain: "bank.test"},
+		{Name: "tracker", Value: "all", Domain: "example"},
+	})
+	jar.SetCookies(mustParse("https://shop.co.uk/"), []*http.Cookie{{Name: "tracker", Value: "all", Domain: ".co.uk"}})
+	jar.SetCookies(mustParse("http://127.0.0.1/"), []*http.Cookie{{Name: "ip", Value: "other", Domain: "127.0.0.2"}})
+
+	for _, raw := range []string{"https://bank.test/", "https://attacker.example/", "https://other.co.uk/", "http://127.0.0.2/"} {
+		if cookies := jar.Cookies(mustParse(raw)); len(cookies) != 0 {
+			t.Errorf("expected no cookies for %s, got %v", raw, cookies)
+		}
+	}
+
+	// the domain of the host and its parents are allowed
+	jar.SetCookies(mustParse("https://api.bank.test/login"), []*http.Cookie{
+		{Name: "session", Value: "abc", Domain: ".bank.test", Path: "/"},
+		{Name: "host", Value: "only", Path: "/"},
+	})
+
+	if cookies := jar.Cookies(mustParse("https://www.bank.test/")); len(cookies) != 1 || cookies[0].Value != "abc" {
+		t.Errorf("expected the cookie of the parent domain, got %v", cookies)
+	}
+
+	if cookies := jar.Cookies(mustParse("https://api.bank.test/")); len(cookies) != 2 {
+		t.Errorf("expected the cookies of the host, got %v", cookies)
+	}
+}
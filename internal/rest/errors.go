@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CanceledError is returned when a request is stopped before its response is fully received,
+// either because the user canceled it or because it ran past its timeout.
+type CanceledError struct {
+	// Timeout is the deadline that was exceeded, it is zero when the request was canceled by the user.
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *CanceledError) Error() string {
+	if e.TimedOut() {
+		return fmt.Sprintf("request timed out after %s", e.Timeout)
+	}
+	return "request canceled"
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// TimedOut reports whether the request was stopped by its timeout rather than by the user.
+func (e *CanceledError) TimedOut() bool {
+	return e.Timeout > 0
+}
+
+// wrapContextError converts errors caused by the given context being done into a CanceledError.
+func wrapContextError(ctx context.Context, timeout time.Duration, err error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &CanceledError{Timeout: timeout, Err: err}
+	case errors.Is(ctx.Err(), context.Canceled):
+		return &CanceledError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/graphql"
+)
+
+// FetchGraphQLSchema sends the introspection query to the endpoint of the GraphQL request with the given id,
+// using the same headers, auth and settings as the request itself.
+func (s *Service) FetchGraphQLSchema(ctx context.Context, requestID, activeEnvironmentID string) (*graphql.Schema, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	if req.Spec.GraphQL == nil {
+		return nil, fmt.Errorf("request %s is not a graphql request", req.MetaData.Name)
+	}
+
+	introspection := req.Spec.GraphQL.Clone()
+	introspection.Query = graphql.IntrospectionQuery
+	introspection.OperationName = graphql.IntrospectionOperationName
+	introspection.Variables = ""
+
+	spec, err := introspection.ToHTTPRequestSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	activeEnvironment, err := s.getEnvironment(activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := s.newScope(req.CollectionID, activeEnvironment, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.send(ctx, spec, sc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := graphql.ParseIntrospection(response.Body)
+	if err != nil && response.StatusCode >= 400 {
+		return nil, fmt.Errorf("introspection failed with status %d: %w", response.StatusCode, err)
+	}
+
+	return schema, err
+}
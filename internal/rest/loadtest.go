@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// LoadTestClient sends the same request over and over for a load test. The request and the environment are read once,
+// the body is discarded and the scripts, post request, tests and cookies are left out, so the environment is not changed
+// and Send can be called by many goroutines.
+type LoadTestClient struct {
+	req *domain.Request
+	// scope holds a copy of the environment, so changes to it do not affect the test
+	scope   scope
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewLoadTestClient returns a client for the request which keeps up to concurrency connections open to the server.
+func (s *Service) NewLoadTestClient(requestID, activeEnvironmentID string, concurrency int) (*LoadTestClient, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	env, err := s.getEnvironment(activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if env != nil {
+		env = env.Clone()
+	}
+
+	sc, err := s.newScope(req.CollectionID, env, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the spec is built once to check the request, it is built again for every request of the test
+	r := req.Clone()
+	spec, err := httpRequestSpec(r, sc.resolve())
+	if err != nil {
+		return nil, err
+	}
+
+	settings := s.clientSettings(spec.Settings, env)
+	// the client is not shared with other requests, as its connections are sized for the test
+	client, err := newHTTPClient(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		transport.MaxIdleConnsPerHost = max(concurrency, 1)
+		transport.MaxIdleConns = max(concurrency, transport.MaxIdleConns)
+	}
+
+	return &LoadTestClient{
+		req:     r,
+		scope:   sc,
+		timeout: settings.Timeout,
+		client:  client,
+	}, nil
+}
+
+// Send sends the request and returns the status code of the response once its body is read.
+func (c *LoadTestClient) Send(ctx context.Context) (int, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// variables are replaced for every request, so internal variables like random values change
+	variables := c.scope.resolve()
+	spec, err := httpRequestSpec(c.req, variables)
+	if err != nil {
+		return 0, err
+	}
+	spec = replaceVariables(spec.Clone(), variables)
+
+	httpReq, err := newHTTPRequest(ctx, spec)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := c.client.Do(httpReq)
+	if err != nil {
+		return 0, c.error(ctx, err)
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(io.Discard, res.Body); err != nil {
+		return res.StatusCode, c.error(ctx, err)
+	}
+	return res.StatusCode, nil
+}
+
+// error drops the method and url from the errors of the client, as they are the same for every request of the test.
+func (c *LoadTestClient) error(ctx context.Context, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return wrapContextError(ctx, c.timeout, err)
+}
+
+// Close closes the idle connections of the client.
+func (c *LoadTestClient) Close() {
+	c.client.CloseIdleConnections()
+}
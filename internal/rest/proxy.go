@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// proxyFunc returns the function used by the transport to pick the proxy of each request.
+func proxyFunc(proxy *domain.ProxySettings) (func(*http.Request) (*url.URL, error), error) {
+	// not configured, honor the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	if proxy == nil {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	if !proxy.Enabled {
+		return nil, nil
+	}
+
+	proxyURL, err := proxyURL(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if shouldBypassProxy(req.URL.Hostname(), proxy.Bypass) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+func proxyURL(proxy *domain.ProxySettings) (*url.URL, error) {
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("proxy host is empty")
+	}
+
+	scheme := proxy.Type
+	if scheme == "" {
+		scheme = domain.ProxyTypeHTTP
+	}
+
+	if scheme != domain.ProxyTypeHTTP && scheme != domain.ProxyTypeSOCKS5 {
+		return nil, fmt.Errorf("unsupported proxy type %q", proxy.Type)
+	}
+
+	u := &url.URL{Scheme: scheme, Host: proxy.Host}
+	if proxy.Username != "" {
+		u.User = url.UserPassword(proxy.Username, proxy.Password)
+	}
+
+	return u, nil
+}
+
+// shouldBypassProxy reports whether the host matches one of the bypass entries,
+// an entry can be *, a host name which also matches its subdomains (optionally prefixed with . or *.),
+// an IP address or a CIDR range.
+func shouldBypassProxy(host string, bypass []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range bypass {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if entry == "*" {
+			return true
+		}
+
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if entryIP := net.ParseIP(entry); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+
+	return false
+}
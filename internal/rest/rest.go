@@ -2,11 +2,13 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
 	"strconv"
@@ -14,7 +16,10 @@ import (
 	"time"
 
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/logger"
+	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/internal/state"
+	"github.com/chapar-rest/chapar/internal/tunnel"
 	"github.com/google/uuid"
 )
 
@@ -25,24 +30,88 @@ type Response struct {
 	Body       []byte
 
 	TimePassed time.Duration
+	Timings    domain.HTTPTimings
+
+	ContentType string
+	// Size is the size of the whole body, Body only holds its beginning when it is Truncated
+	Size int64
+	// Binary is true when the body is not text, like images or archives
+	Binary bool
+	// Truncated is true when the body was larger than the max response size, the whole body is in BodyFile
+	Truncated bool
+	BodyFile  string
 
 	IsJSON bool
 	JSON   string
+
+	// Streamed is true when the body was read as a stream of events
+	Streamed bool
+
+	// TestResults are the results of the enabled assertions of the request
+	TestResults []domain.AssertionResult
 }
 
 type Service struct {
 	requests     *state.Requests
 	environments *state.Environments
+	cookies      *state.Cookies
+
+	// settings are the workspace http client settings, requests can override them.
+	settings domain.HTTPClientSettings
+	clients  *safemap.Map[*http.Client]
+
+	// tunnels are the tunnels of the pre requests, shared between the requests
+	tunnels *tunnel.Manager
+
+	// stateOnly keeps the variables post requests and scripts set in memory instead of writing them to the workspace
+	stateOnly bool
 }
 
-func New(requests *state.Requests, environments *state.Environments) *Service {
+func New(requests *state.Requests, environments *state.Environments, cookies *state.Cookies) *Service {
 	return &Service{
 		requests:     requests,
 		environments: environments,
+		cookies:      cookies,
+		clients:      safemap.New[*http.Client](),
+		tunnels:      tunnel.NewManager(),
 	}
 }
 
-func (s *Service) SendRequest(requestID, activeEnvironmentID string) (*Response, error) {
+// Tunnels returns the manager of the tunnels requests are sent through.
+func (s *Service) Tunnels() *tunnel.Manager {
+	return s.tunnels
+}
+
+// SetStateOnly sets whether the variables post requests and scripts set are only kept in memory,
+// like for the cli which should not change the workspace it runs.
+func (s *Service) SetStateOnly(stateOnly bool) {
+	s.stateOnly = stateOnly
+}
+
+// SetHTTPClientSettings sets the workspace http client settings used for requests which do not override them.
+func (s *Service) SetHTTPClientSettings(settings domain.HTTPClientSettings) {
+	s.settings = settings
+}
+
+// SendRequest sends the request with the given id using the given environment.
+// the request is aborted with a CanceledError when ctx is canceled or the request timeout is reached.
+func (s *Service) SendRequest(ctx context.Context, requestID, activeEnvironmentID string) (*Response, error) {
+	return s.SendStreamingRequest(ctx, requestID, activeEnvironmentID, nil)
+}
+
+// SendStreamingRequest is SendRequest reporting streamed responses, like server-sent events, to the handler while they arrive.
+// the post request is applied to every event, and canceling ctx stops the stream keeping what is received so far.
+func (s *Service) SendStreamingRequest(ctx context.Context, requestID, activeEnvironmentID string, handler *StreamHandler) (*Response, error) {
+	return s.sendStreamingRequest(ctx, requestID, activeEnvironmentID, nil, handler)
+}
+
+// SendRequestWithVariables is SendRequest with extra variables, like the values of a row of a data file,
+// they take precedence over the variables of the environment.
+func (s *Service) SendRequestWithVariables(ctx context.Context, requestID, activeEnvironmentID string, variables map[string]string) (*Response, error) {
+	return s.sendStreamingRequest(ctx, requestID, activeEnvironmentID, variables, nil)
+}
+
+func (s *Service) sendStreamingRequest(ctx context.Context, requestID, activeEnvironmentID string, variables map[string]string, handler *StreamHandler) (*Response, error) {
 	req := s.requests.GetRequest(requestID)
 	if req == nil {
 		return nil, fmt.Errorf("request with id %s not found", requestID)
@@ -51,164 +120,439 @@ func (s *Service) SendRequest(requestID, activeEnvironmentID string) (*Response,
 	// clone the request to make sure we do not modify the original request
 	r := req.Clone()
 
-	var activeEnvironment *domain.Environment
-	// Get environment if provided
-	if activeEnvironmentID != "" {
-		activeEnvironment = s.environments.GetEnvironment(activeEnvironmentID)
-		if activeEnvironment == nil {
-			return nil, fmt.Errorf("environment with id %s not found", activeEnvironmentID)
-		}
+	activeEnvironment, err := s.getEnvironment(activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.newScope(req.CollectionID, activeEnvironment, variables)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := s.sendRequest(r.Spec.HTTP, activeEnvironment)
+	spec, err := httpRequestSpec(r, scope.resolve())
 	if err != nil {
 		return nil, err
 	}
 
-	// handle post request
-	if err := s.handlePostRequest(r.Spec.HTTP.Request.PostRequest, response, activeEnvironment); err != nil {
+	response, err := s.send(ctx, spec, scope, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	response.TestResults = evaluateAssertions(spec.Request.Tests, response)
+
+	// failures of the post request script are only logged, as the response is already received
+	if post := spec.Request.PostRequest; (post.Type == domain.PostRequestTypePythonScript || post.Type == domain.PostRequestTypeShellScript) && strings.TrimSpace(post.Script) != "" {
+		if err := s.runPostRequestScript(ctx, spec, response, scope); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+
+	// handle post request, it is already applied to the events of streamed responses
+	if response.Streamed {
+		return response, nil
+	}
+
+	if err := s.handlePostRequest(spec.Request.PostRequest, response, scope); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
-func (s *Service) handlePostRequest(r domain.PostRequest, response *Response, env *domain.Environment) error {
-	if r == (domain.PostRequest{}) {
-		return nil
+// scope holds what a request is sent with besides its spec, the environment and collection
+// its post request updates and the variables of all the scopes.
+type scope struct {
+	env        *domain.Environment
+	collection *domain.Collection
+	// variables are the global and collection variables, the variables of the environment override them
+	variables map[string]string
+	// extra are variables like the values of a row of a data file, they override the environment
+	extra map[string]string
+}
+
+// resolve returns the variables of all the scopes, internal variables like randomUUID4 get new values on every call.
+func (sc scope) resolve() map[string]string {
+	var envSpec *domain.EnvSpec
+	if sc.env != nil {
+		env := sc.env.Clone()
+		envSpec = &env.Spec
 	}
+	return mergeVariables(sc.variables, envSpec, sc.extra)
+}
 
-	if response == nil {
-		return nil
+// newScope returns the scope of a request of the given collection, collectionID is empty for requests without a collection.
+func (s *Service) newScope(collectionID string, env *domain.Environment, extra map[string]string) (scope, error) {
+	return newScope(s.requests, s.environments, collectionID, env, extra)
+}
+
+// ResolveVariables returns the variables a request of the given collection is sent with, the global, collection and
+// environment variables along with the internal ones, so requests of other protocols resolve them like http requests.
+// env is nil when no environment is active.
+func ResolveVariables(requests *state.Requests, environments *state.Environments, collectionID string, env *domain.Environment) (map[string]string, error) {
+	sc, err := newScope(requests, environments, collectionID, env, nil)
+	if err != nil {
+		return nil, err
+	}
+	return sc.resolve(), nil
+}
+
+func newScope(requests *state.Requests, environments *state.Environments, collectionID string, env *domain.Environment, extra map[string]string) (scope, error) {
+	globals, err := environments.GetGlobals()
+	if err != nil {
+		return scope{}, err
 	}
 
-	switch r.Type {
-	case domain.PostRequestTypeSetEnv:
-		// only handle post request if the status code is the same as the one provided
-		if response.StatusCode != r.PostRequestSet.StatusCode {
-			return nil
+	sc := scope{
+		env:       env,
+		variables: make(map[string]string),
+		extra:     extra,
+	}
+
+	for _, kv := range globals.Spec.Values {
+		if kv.Enable {
+			sc.variables[kv.Key] = kv.Value
 		}
+	}
 
-		switch r.PostRequestSet.From {
-		case domain.PostRequestSetFromResponseBody:
-			if err := s.handlePostRequestFromBody(r, response, env); err != nil {
-				return err
-			}
-		case domain.PostRequestSetFromResponseHeader:
-			if err := s.handlePostRequestFromHeader(r, response, env); err != nil {
-				return err
-			}
-		case domain.PostRequestSetFromResponseCookie:
-			if err := s.handlePostRequestFromCookie(r, response, env); err != nil {
-				return err
+	if collectionID != "" {
+		sc.collection = requests.GetCollection(collectionID)
+	}
+
+	if sc.collection != nil {
+		for _, kv := range sc.collection.Spec.Variables {
+			if kv.Enable {
+				sc.variables[kv.Key] = kv.Value
 			}
 		}
 	}
 
-	return nil
+	return sc, nil
+}
+
+// httpRequestSpec returns the http request to send for the given request, GraphQL operations are sent over http.
+// the variables are replaced in the operation before it is encoded, so templates like {"id": {{id}}} are valid variables.
+func httpRequestSpec(r *domain.Request, variables map[string]string) (*domain.HTTPRequestSpec, error) {
+	switch {
+	case r.Spec.GraphQL != nil:
+		g := r.Spec.GraphQL.Clone()
+		g.Query = ReplaceText(g.Query, variables)
+		g.Variables = ReplaceText(g.Variables, variables)
+		return g.ToHTTPRequestSpec()
+	case r.Spec.HTTP != nil:
+		return r.Spec.HTTP, nil
+	}
+	return nil, fmt.Errorf("request %s is not an http request", r.MetaData.Name)
+}
+
+func (s *Service) getEnvironment(id string) (*domain.Environment, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	env := s.environments.GetEnvironment(id)
+	if env == nil {
+		return nil, fmt.Errorf("environment with id %s not found", id)
+	}
+	return env, nil
 }
 
-func (s *Service) handlePostRequestFromBody(r domain.PostRequest, response *Response, env *domain.Environment) error {
-	// handle post request
-	if r.PostRequestSet.From != domain.PostRequestSetFromResponseBody {
+// clientSettings returns the settings to send a request with, precedence is workspace < active environment < request.
+func (s *Service) clientSettings(requestSettings *domain.HTTPClientSettings, activeEnvironment *domain.Environment) domain.HTTPClientSettings {
+	settings := s.settings
+	if activeEnvironment != nil && activeEnvironment.Spec.Proxy != nil {
+		settings.Proxy = activeEnvironment.Spec.Proxy
+	}
+	return settings.Merge(requestSettings)
+}
+
+// send sends the request applying the client settings and the timeout.
+func (s *Service) send(ctx context.Context, spec *domain.HTTPRequestSpec, sc scope, handler *StreamHandler) (*Response, error) {
+	settings := s.clientSettings(spec.Settings, sc.env)
+
+	if settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, settings.Timeout)
+		defer cancel()
+	}
+
+	response, err := s.sendRequest(ctx, spec, sc, settings, handler)
+	if err != nil {
+		return nil, wrapContextError(ctx, settings.Timeout, err)
+	}
+	return response, nil
+}
+
+// handlePostRequest stores the values the rules of the post request extract from the response in the variables of their scopes.
+func (s *Service) handlePostRequest(r domain.PostRequest, response *Response, sc scope) error {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	if response == nil {
 		return nil
 	}
 
-	if response.JSON != "" && response.IsJSON {
-		data, err := GetJSONPATH(response.JSON, r.PostRequestSet.FromKey)
+	if r.Type != domain.PostRequestTypeSetEnv {
+		return nil
+	}
+
+	var envChanged, collectionChanged bool
+	var globals []domain.KeyValue
+	for _, rule := range r.GetRules() {
+		// only handle the rules which match the status code of the response
+		if rule.Target == "" || !rule.MatchStatusCode(response.StatusCode) {
+			continue
+		}
+
+		value, ok, err := postRequestValue(rule, response)
 		if err != nil {
 			return err
 		}
 
-		if data == nil {
-			return nil
+		if !ok {
+			continue
 		}
 
-		if result, ok := data.(string); ok {
-			if env != nil {
-				env.SetKey(r.PostRequestSet.Target, result)
-
-				if err := s.environments.UpdateEnvironment(env, state.SourceRestService, false); err != nil {
-					return err
-				}
-			}
+		switch {
+		case rule.Scope == domain.PostRequestScopeCollection && sc.collection != nil:
+			sc.collection.SetKey(rule.Target, value)
+			collectionChanged = true
+		case (rule.Scope == "" || rule.Scope == domain.PostRequestScopeEnvironment) && sc.env != nil:
+			sc.env.SetKey(rule.Target, value)
+			envChanged = true
+		default:
+			// global rules and the ones whose environment or collection does not exist
+			globals = append(globals, domain.KeyValue{Key: rule.Target, Value: value})
 		}
 	}
-	return nil
-}
 
-func (s *Service) handlePostRequestFromHeader(r domain.PostRequest, response *Response, env *domain.Environment) error {
-	if r.PostRequestSet.From != domain.PostRequestSetFromResponseHeader {
-		return nil
+	if envChanged {
+		if err := s.environments.UpdateEnvironment(sc.env, state.SourceRestService, s.stateOnly); err != nil {
+			return err
+		}
 	}
 
-	if result, ok := response.Headers[r.PostRequestSet.FromKey]; ok {
-		if env != nil {
-			env.SetKey(r.PostRequestSet.Target, result)
+	if collectionChanged {
+		if err := s.requests.UpdateCollection(sc.collection, s.stateOnly); err != nil {
+			return err
+		}
+	}
 
-			if err := s.environments.UpdateEnvironment(env, state.SourceRestService, false); err != nil {
-				return err
-			}
+	if len(globals) > 0 {
+		if err := s.environments.SetGlobalValues(globals, s.stateOnly); err != nil {
+			return err
 		}
 	}
+
 	return nil
 }
 
-func (s *Service) handlePostRequestFromCookie(r domain.PostRequest, response *Response, env *domain.Environment) error {
-	if r.PostRequestSet.From != domain.PostRequestSetFromResponseCookie {
-		return nil
-	}
+// postRequestValue returns the value the rule extracts from the response, ok is false when the response does not have it.
+func postRequestValue(rule domain.PostRequestSet, response *Response) (string, bool, error) {
+	switch rule.From {
+	case domain.PostRequestSetFromResponseBody:
+		if response.JSON == "" || !response.IsJSON {
+			return "", false, nil
+		}
 
-	for _, c := range response.Cookies {
-		if c.Name == r.PostRequestSet.FromKey {
-			if env != nil {
-				env.SetKey(r.PostRequestSet.Target, c.Value)
+		data, err := GetJSONPATH(response.JSON, rule.FromKey)
+		if err != nil {
+			return "", false, err
+		}
 
-				if err := s.environments.UpdateEnvironment(env, state.SourceRestService, false); err != nil {
-					return err
-				}
+		if data == nil {
+			return "", false, nil
+		}
+		return FormatValue(data), true, nil
+	case domain.PostRequestSetFromResponseHeader:
+		result, ok := response.Headers[rule.FromKey]
+		return result, ok, nil
+	case domain.PostRequestSetFromResponseCookie:
+		for _, c := range response.Cookies {
+			if c.Name == rule.FromKey {
+				return c.Value, true, nil
 			}
 		}
 	}
-	return nil
+	return "", false, nil
+}
+
+// FormatValue returns the value of a JSONPath as text, strings are kept as they are and other values,
+// like numbers, booleans and objects, are encoded as compact JSON. it is used for variables and assertions,
+// so 1 and true can be compared with their text.
+func FormatValue(data any) string {
+	if str, ok := data.(string); ok {
+		return str
+	}
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprint(data)
+	}
+	return string(out)
 }
 
-func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment) (*Response, error) {
+func (s *Service) sendRequest(ctx context.Context, req *domain.HTTPRequestSpec, sc scope, settings domain.HTTPClientSettings, handler *StreamHandler) (*Response, error) {
 	// prepare request
 	// - apply environment
 	// - apply variables
 	// - apply authentication (if any) is not already applied to the headers
 
-	if e == nil {
-		applyVariables(req, nil)
-	} else {
-		env := e.Clone()
-		applyVariables(req, &env.Spec)
+	variables := sc.resolve()
+	pre := req.Request.PreRequest
+	hasScript := strings.TrimSpace(pre.Script) != ""
+
+	// shell scripts usually fetch values like tokens for the request, so it is resolved with the variables they set
+	if pre.Type == domain.PrePostTypeShell && hasScript {
+		resolved := replaceVariables(req.Clone(), variables)
+		var err error
+		if variables, err = s.runPreRequestScript(ctx, resolved, variables, sc); err != nil {
+			return nil, err
+		}
+	}
+
+	replaceVariables(req, variables)
+
+	// python scripts get the resolved request, the variables they set are applied to what they changed
+	if pre.Type == domain.PrePostTypePython && hasScript {
+		var err error
+		if variables, err = s.runPreRequestScript(ctx, req, variables, sc); err != nil {
+			return nil, err
+		}
+		replaceVariables(req, variables)
 	}
 
-	httpReq, err := http.NewRequest(req.Method, req.URL, nil)
+	httpReq, err := newHTTPRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	// apply headers
-	for _, h := range req.Request.Headers {
-		if !h.Enable {
-			continue
+	// send request
+	// - measure time
+	// - handle response
+	// - handle error
+	// - handle cookies
+	// - handle redirects
+	// - handle status code
+
+	client, err := s.httpClient(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	// cached clients are shared, so the jar of the environment is set on a copy
+	if jar := s.cookieJar(sc.env); jar != nil {
+		c := *client
+		c.Jar = jar
+		client = &c
+	}
+
+	// the request is sent through the tunnel of its pre request, if it has one
+	localAddr, addr, release, err := s.openTunnel(ctx, pre, req.URL, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if release != nil {
+		defer release()
+		client = tunnelClient(client, addr, localAddr)
+		defer client.CloseIdleConnections()
+	}
+
+	// send request
+	start := time.Now()
+	trace := newTimingTrace(start)
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), trace.clientTrace()))
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// handle response
+	response := &Response{
+		StatusCode:  res.StatusCode,
+		Headers:     map[string]string{},
+		Cookies:     res.Cookies(),
+		IsJSON:      false,
+		ContentType: res.Header.Get("Content-Type"),
+	}
+
+	// handle headers
+	for k, v := range res.Header {
+		response.Headers[k] = strings.Join(v, ", ")
+	}
+
+	// read body
+	var body []byte
+	if kind := streamKind(res, settings.ShouldStreamChunked()); kind != "" && handler != nil {
+		response.Streamed = true
+		if handler.OnStart != nil {
+			handler.OnStart(response)
 		}
 
-		httpReq.Header.Add(h.Key, h.Value)
+		body, response.BodyFile, response.Size, err = readStream(res.Body, kind, settings.GetMaxResponseSize(), func(event StreamEvent) {
+			response.TimePassed = time.Since(start)
+			if err := s.handlePostRequest(req.Request.PostRequest, eventResponse(response, event), sc); err != nil {
+				fmt.Println("failed to handle post request of the event", err)
+			}
+
+			if handler.OnEvent != nil {
+				handler.OnEvent(event)
+			}
+		})
+
+		// the stream stopped by canceling the request is a complete response
+		if err != nil && ctx.Err() == nil {
+			if response.BodyFile != "" {
+				os.Remove(response.BodyFile)
+			}
+			return nil, err
+		}
+		response.Truncated = response.BodyFile != ""
+	} else {
+		body, response.BodyFile, response.Size, err = readBody(res.Body, settings.GetMaxResponseSize())
+		if err != nil {
+			return nil, err
+		}
+		response.Truncated = response.BodyFile != ""
 	}
 
-	// apply path params as single brace
-	for _, p := range req.Request.PathParams {
-		if !p.Enable {
-			continue
+	// measure time
+	end := time.Now()
+	response.Body = body
+	response.TimePassed = end.Sub(start)
+	response.Timings = trace.timings(end)
+
+	response.Binary = isBinary(response.ContentType, body)
+	if response.Binary || response.Truncated {
+		return response, nil
+	}
+
+	if IsJSON(string(body)) {
+		response.IsJSON = true
+		if js, err := PrettyJSON(body); err != nil {
+			return nil, err
+		} else {
+			response.JSON = js
 		}
+	}
 
-		httpReq.URL.Path = strings.ReplaceAll(httpReq.URL.Path, "{"+p.Key+"}", p.Value)
+	return response, nil
+}
+
+// newHTTPRequest builds the http request of the spec, its variables must be already replaced.
+func newHTTPRequest(ctx context.Context, req *domain.HTTPRequestSpec) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, nil)
+	if err != nil {
+		return nil, err
 	}
 
+	applyHeaders(httpReq, req.Request)
+
 	// TODO queries are already assembled to the url, should we do it here instead?
 	// apply query params
 	// query := httpReq.URL.Query()
@@ -307,79 +651,79 @@ func (s *Service) sendRequest(req *domain.HTTPRequestSpec, e *domain.Environment
 		}
 	}
 
-	// apply authentication
-	if req.Request.Auth != (domain.Auth{}) {
-		if req.Request.Auth.Type == domain.AuthTypeToken {
-			if req.Request.Auth.TokenAuth != nil && req.Request.Auth.TokenAuth.Token != "" {
-				httpReq.Header.Add("Authorization", "Bearer "+req.Request.Auth.TokenAuth.Token)
-			}
-		}
+	applyAuth(httpReq, req.Request.Auth)
+	return httpReq, nil
+}
 
-		if req.Request.Auth.Type == domain.AuthTypeBasic {
-			if req.Request.Auth.BasicAuth != nil && req.Request.Auth.BasicAuth.Username != "" && req.Request.Auth.BasicAuth.Password != "" {
-				httpReq.SetBasicAuth(req.Request.Auth.BasicAuth.Username, req.Request.Auth.BasicAuth.Password)
-			}
+// applyHeaders adds the enabled headers to the request and replaces the path params in its path.
+func applyHeaders(httpReq *http.Request, req *domain.HTTPRequest) {
+	// apply headers
+	for _, h := range req.Headers {
+		if !h.Enable {
+			continue
 		}
 
-		if req.Request.Auth.Type == domain.AuthTypeAPIKey {
-			if req.Request.Auth.APIKeyAuth != nil && req.Request.Auth.APIKeyAuth.Key != "" && req.Request.Auth.APIKeyAuth.Value != "" {
-				httpReq.Header.Add(req.Request.Auth.APIKeyAuth.Key, req.Request.Auth.APIKeyAuth.Value)
-			}
-		}
+		httpReq.Header.Add(h.Key, h.Value)
 	}
 
-	// send request
-	// - measure time
-	// - handle response
-	// - handle error
-	// - handle cookies
-	// - handle redirects
-	// - handle status code
+	// apply path params as single brace
+	for _, p := range req.PathParams {
+		if !p.Enable {
+			continue
+		}
 
-	// send request
-	start := time.Now()
-	res, err := http.DefaultClient.Do(httpReq)
-	if err != nil {
-		return nil, err
+		httpReq.URL.Path = strings.ReplaceAll(httpReq.URL.Path, "{"+p.Key+"}", p.Value)
 	}
+}
 
-	// read body
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+func applyAuth(httpReq *http.Request, auth domain.Auth) {
+	if auth == (domain.Auth{}) {
+		return
 	}
 
-	// measure time
-	elapsed := time.Since(start)
-
-	// handle response
-	response := &Response{
-		StatusCode: res.StatusCode,
-		Headers:    map[string]string{},
-		Cookies:    res.Cookies(),
-		Body:       body,
-		TimePassed: elapsed,
-		IsJSON:     false,
+	if auth.Type == domain.AuthTypeToken {
+		if auth.TokenAuth != nil && auth.TokenAuth.Token != "" {
+			httpReq.Header.Add("Authorization", "Bearer "+auth.TokenAuth.Token)
+		}
 	}
 
-	if IsJSON(string(body)) {
-		response.IsJSON = true
-		if js, err := PrettyJSON(body); err != nil {
-			return nil, err
-		} else {
-			response.JSON = js
+	if auth.Type == domain.AuthTypeBasic {
+		if auth.BasicAuth != nil && auth.BasicAuth.Username != "" && auth.BasicAuth.Password != "" {
+			httpReq.SetBasicAuth(auth.BasicAuth.Username, auth.BasicAuth.Password)
 		}
 	}
 
-	// handle headers
-	for k, v := range res.Header {
-		response.Headers[k] = strings.Join(v, ", ")
+	if auth.Type == domain.AuthTypeAPIKey {
+		if auth.APIKeyAuth != nil && auth.APIKeyAuth.Key != "" && auth.APIKeyAuth.Value != "" {
+			httpReq.Header.Add(auth.APIKeyAuth.Key, auth.APIKeyAuth.Value)
+		}
 	}
+}
 
-	return response, nil
+// applyVariables replaces the scoped variables, the variables of the environment and the extra variables in the request,
+// the environment overrides the scoped variables and the extra variables override both.
+func applyVariables(req *domain.HTTPRequestSpec, scoped map[string]string, env *domain.EnvSpec, extra map[string]string) *domain.HTTPRequestSpec {
+	return replaceVariables(req, mergeVariables(scoped, env, extra))
+}
+
+// mergeVariables returns the variables of all the scopes with the precedence of applyVariables.
+func mergeVariables(scoped map[string]string, env *domain.EnvSpec, extra map[string]string) map[string]string {
+	variables := make(map[string]string, len(scoped))
+	for k, v := range scoped {
+		variables[k] = v
+	}
+	for k, v := range CollectVariables(env) {
+		variables[k] = v
+	}
+	for k, v := range extra {
+		variables[k] = v
+	}
+	return variables
 }
 
-func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HTTPRequestSpec {
+// CollectVariables returns the internal variables and the enabled variables of the environment.
+// the values of env are updated with the internal variables, pass a clone to keep the environment as is.
+func CollectVariables(env *domain.EnvSpec) map[string]string {
 	// apply internal variables to environment
 	// apply environment to request
 	variables := map[string]string{
@@ -409,6 +753,22 @@ func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HT
 		}
 	}
 
+	return variables
+}
+
+// ReplaceText replaces the variables in double curly braces in the given text.
+func ReplaceText(text string, variables map[string]string) string {
+	for k, v := range variables {
+		text = strings.ReplaceAll(text, "{{"+k+"}}", v)
+	}
+	return text
+}
+
+func replaceVariables(req *domain.HTTPRequestSpec, variables map[string]string) *domain.HTTPRequestSpec {
+	if req.Request == nil {
+		req.Request = &domain.HTTPRequest{}
+	}
+
 	// apply variables to request
 	for k, v := range variables {
 		for i, kv := range req.Request.Headers {
@@ -483,6 +843,11 @@ func applyVariables(req *domain.HTTPRequestSpec, env *domain.EnvSpec) *domain.HT
 			}
 		}
 
+		for i, a := range req.Request.Tests {
+			req.Request.Tests[i].Target = strings.ReplaceAll(a.Target, "{{"+k+"}}", v)
+			req.Request.Tests[i].Value = strings.ReplaceAll(a.Value, "{{"+k+"}}", v)
+		}
+
 	}
 	return req
 }
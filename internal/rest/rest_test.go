@@ -1,10 +1,24 @@
 package rest
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/graphql"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 )
 
 func Test_applyVariables(t *testing.T) {
@@ -20,7 +34,7 @@ func Test_applyVariables(t *testing.T) {
 
 	sampleReq := &domain.HTTPRequestSpec{}
 
-	applyVariables(sampleReq, sampleEnv)
+	applyVariables(sampleReq, nil, sampleEnv, nil)
 
 	if sampleEnv.Values[0].Value == "{{randomUUID4}}" {
 		t.Errorf("expected randomUUID4 but got %s", sampleEnv.Values[0].Value)
@@ -31,3 +45,976 @@ func Test_applyVariables(t *testing.T) {
 		t.Errorf("expected valid uuid but got %s", sampleEnv.Values[0].Value)
 	}
 }
+
+func TestService_SendRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	req := domain.NewRequest("slow")
+	req.Spec.HTTP.URL = srv.URL
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+	s.SetHTTPClientSettings(domain.HTTPClientSettings{Timeout: 50 * time.Millisecond})
+
+	_, err := s.SendRequest(context.Background(), req.MetaData.ID, "")
+	var canceledErr *CanceledError
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("expected CanceledError but got %v", err)
+	}
+
+	if !canceledErr.TimedOut() {
+		t.Errorf("expected request to time out but it was canceled")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req.Spec.HTTP.Settings = &domain.HTTPClientSettings{Timeout: time.Second}
+	_, err = s.SendRequest(ctx, req.MetaData.ID, "")
+	if !errors.As(err, &canceledErr) {
+		t.Fatalf("expected CanceledError but got %v", err)
+	}
+
+	if canceledErr.TimedOut() {
+		t.Errorf("expected request to be canceled but it timed out")
+	}
+}
+
+func TestService_SendRequestRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/target" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Redirect(w, r, "/target", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	req := domain.NewRequest("redirect")
+	req.Spec.HTTP.URL = srv.URL + "/source"
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+
+	res, err := s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected redirect to be followed but got status %d", res.StatusCode)
+	}
+
+	follow := false
+	req.Spec.HTTP.Settings = &domain.HTTPClientSettings{FollowRedirects: &follow}
+	res, err = s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusFound {
+		t.Errorf("expected redirect not to be followed but got status %d", res.StatusCode)
+	}
+}
+
+func TestService_SendRequestProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// requests sent through a proxy carry the absolute url of the target
+		proxied = append(proxied, r.URL.String())
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	req := domain.NewRequest("proxied")
+	req.Spec.HTTP.URL = "http://api.chapar.test/users"
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	env := domain.NewEnvironment("staging")
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	s := New(requests, environments, state.NewCookies(nil))
+	s.SetHTTPClientSettings(domain.HTTPClientSettings{
+		Proxy: &domain.ProxySettings{
+			Enabled:  true,
+			Type:     domain.ProxyTypeHTTP,
+			Host:     proxy.Listener.Addr().String(),
+			Username: "user",
+			Password: "secret",
+		},
+	})
+
+	res, err := s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 but got %d", res.StatusCode)
+	}
+
+	if len(proxied) != 1 || proxied[0] != req.Spec.HTTP.URL {
+		t.Fatalf("expected request to go through the proxy but got %v", proxied)
+	}
+
+	// the environment proxy takes precedence over the workspace one
+	env.Spec.Proxy = &domain.ProxySettings{
+		Enabled: true,
+		Host:    proxy.Listener.Addr().String(),
+		Bypass:  []string{".chapar.test"},
+	}
+
+	if _, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err == nil {
+		t.Errorf("expected bypassed request to fail to resolve the host")
+	}
+
+	if len(proxied) != 1 {
+		t.Errorf("expected bypassed request not to go through the proxy but got %v", proxied)
+	}
+}
+
+func Test_shouldBypassProxy(t *testing.T) {
+	bypass := []string{"localhost", ".internal.io", "*.corp", "10.0.0.0/8", "::1"}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{host: "localhost", want: true},
+		{host: "api.internal.io", want: true},
+		{host: "internal.io", want: true},
+		{host: "svc.corp", want: true},
+		{host: "10.1.2.3", want: true},
+		{host: "::1", want: true},
+		{host: "example.com", want: false},
+		{host: "notinternal.io", want: false},
+		{host: "192.168.1.1", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := shouldBypassProxy(tt.host, bypass); got != tt.want {
+			t.Errorf("shouldBypassProxy(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+
+	if !shouldBypassProxy("example.com", []string{"*"}) {
+		t.Errorf("expected * to bypass every host")
+	}
+}
+
+func TestService_SendRequestCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+			return
+		}
+
+		if c, err := r.Cookie("session"); err != nil || c.Value != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	login := domain.NewRequest("login")
+	login.Spec.HTTP.URL = srv.URL + "/login"
+	profile := domain.NewRequest("profile")
+	profile.Spec.HTTP.URL = srv.URL + "/profile"
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(login)
+	requests.AddRequest(profile)
+
+	env := domain.NewEnvironment("staging")
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	cookies := state.NewCookies(nil)
+	s := New(requests, environments, cookies)
+
+	if _, err := s.SendRequest(context.Background(), login.MetaData.ID, env.MetaData.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	res, err := s.SendRequest(context.Background(), profile.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected session cookie to be sent but got status %d", res.StatusCode)
+	}
+
+	stored, err := cookies.GetCookies(domain.CookieJarName(env.MetaData.ID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stored) != 1 || stored[0].Name != "session" || !stored[0].HttpOnly || !stored[0].HostOnly {
+		t.Errorf("expected session cookie to be stored with its attributes but got %+v", stored)
+	}
+
+	// cookies are kept per environment
+	res, err = s.SendRequest(context.Background(), profile.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected cookies of other environments not to be sent but got status %d", res.StatusCode)
+	}
+}
+
+func TestService_SendGraphQLRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Query         string         `json:"query"`
+			Variables     map[string]any `json:"variables"`
+			OperationName string         `json:"operationName"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if payload.OperationName == graphql.IntrospectionOperationName {
+			_, _ = w.Write([]byte(`{"data": {"__schema": {"queryType": {"name": "Query"}, "types": [{"kind": "OBJECT", "name": "Query"}]}}}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"operation": payload.OperationName, "id": payload.Variables["id"]},
+		})
+	}))
+	defer srv.Close()
+
+	req := domain.NewGraphQLRequest("user")
+	req.Spec.GraphQL.URL = srv.URL
+	req.Spec.GraphQL.Query = "query GetUser($id: ID!) { user(id: $id) { name } }"
+	req.Spec.GraphQL.OperationName = "GetUser"
+	req.Spec.GraphQL.Variables = `{"id": "{{userID}}"}`
+	req.Spec.GraphQL.Auth = domain.Auth{Type: domain.AuthTypeToken, TokenAuth: &domain.TokenAuth{Token: "secret"}}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	env := domain.NewEnvironment("staging")
+	env.Spec.Values = []domain.KeyValue{{ID: "1", Key: "userID", Value: "42", Enable: true}}
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	s := New(requests, environments, state.NewCookies(nil))
+
+	res, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 but got %d", res.StatusCode)
+	}
+
+	if string(res.Body) != `{"data":{"id":"42","operation":"GetUser"}}`+"\n" {
+		t.Errorf("unexpected response body %s", res.Body)
+	}
+
+	schema, err := s.FetchGraphQLSchema(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if schema.Type("Query") == nil {
+		t.Errorf("expected schema to have the Query type")
+	}
+
+	// variables are replaced before the json is checked, so they can be used for values which are not strings
+	env.Spec.Values = append(env.Spec.Values, domain.KeyValue{ID: "2", Key: "numericID", Value: "7", Enable: true})
+	req.Spec.GraphQL.Variables = `{"id": {{numericID}}}`
+	res, err = s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(res.Body) != `{"data":{"id":7,"operation":"GetUser"}}`+"\n" {
+		t.Errorf("unexpected response body %s", res.Body)
+	}
+
+	// values in the query are encoded with it, so quotes do not break the body
+	env.Spec.Values = append(env.Spec.Values, domain.KeyValue{ID: "3", Key: "field", Value: `name @include(if: "yes")`, Enable: true})
+	req.Spec.GraphQL.Query = "query GetUser($id: ID!) { user(id: $id) { {{field}} } }"
+	if res, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err != nil || res.StatusCode != http.StatusOK {
+		t.Errorf("expected the query with quotes to be sent, got %v", err)
+	}
+
+	for _, variables := range []string{`{"id": `, `[1, 2]`, `null`} {
+		req.Spec.GraphQL.Variables = variables
+		if _, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err == nil || !strings.Contains(err.Error(), "not a valid json object") {
+			t.Errorf("expected variables %s to fail, got %v", variables, err)
+		}
+	}
+}
+
+func TestService_ConnectWebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Client") != "chapar" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// echo every message back
+		for {
+			frameType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(frameType, data); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	req := domain.NewWebSocketRequest("echo")
+	req.Spec.WebSocket.URL = srv.URL
+	req.Spec.WebSocket.Request.Headers = []domain.KeyValue{{ID: "1", Key: "X-Client", Value: "{{client}}", Enable: true}}
+	req.Spec.WebSocket.Request.Auth = domain.Auth{Type: domain.AuthTypeToken, TokenAuth: &domain.TokenAuth{Token: "{{token}}"}}
+
+	// the token is a collection variable and the greeting a global one, like for http requests
+	col := domain.NewCollection("chat")
+	col.Spec.Variables = []domain.KeyValue{{ID: "1", Key: "token", Value: "secret", Enable: true}}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+	requests.AddCollection(col)
+	requests.AddRequestToCollection(col, req)
+	req.CollectionID = col.MetaData.ID
+
+	env := domain.NewEnvironment("staging")
+	env.Spec.Values = []domain.KeyValue{
+		{ID: "1", Key: "client", Value: "chapar", Enable: true},
+		{ID: "2", Key: "userID", Value: "42", Enable: true},
+	}
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+	if err := environments.SetGlobalValues([]domain.KeyValue{{Key: "greeting", Value: "hello"}}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := New(requests, environments, state.NewCookies(nil))
+
+	if _, err := s.ConnectWebSocket(context.Background(), req.MetaData.ID, ""); err == nil {
+		t.Fatal("expected the handshake to be rejected without the environment")
+	}
+
+	conn, err := s.ConnectWebSocket(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	received := make(chan WebSocketMessage, 3)
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- conn.Listen(func(msg WebSocketMessage) {
+			received <- msg
+		})
+	}()
+
+	tests := []struct {
+		msgType  string
+		data     string
+		expected string
+	}{
+		{domain.WebSocketMessageTypeText, "{{greeting}} {{userID}}", "hello 42"},
+		{domain.WebSocketMessageTypeJSON, `{"id": "{{userID}}"}`, `{"id": "42"}`},
+		{domain.WebSocketMessageTypeBinary, "AAEC", "\x00\x01\x02"},
+	}
+
+	for _, tt := range tests {
+		sent, err := conn.Send(tt.msgType, tt.data)
+		if err != nil {
+			t.Fatalf("failed to send %s message: %v", tt.msgType, err)
+		}
+
+		if sent.Direction != WebSocketDirectionOut || string(sent.Data) != tt.expected {
+			t.Errorf("unexpected sent message %+v", sent)
+		}
+
+		select {
+		case msg := <-received:
+			if msg.Direction != WebSocketDirectionIn || msg.Type != tt.msgType || string(msg.Data) != tt.expected {
+				t.Errorf("unexpected received message %+v", msg)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no echo received for %s message", tt.msgType)
+		}
+	}
+
+	if _, err := conn.Send(domain.WebSocketMessageTypeJSON, "{"); err == nil {
+		t.Error("expected invalid json to be rejected")
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("failed to close connection: %v", err)
+	}
+
+	select {
+	case err := <-listenErr:
+		if err != nil {
+			t.Errorf("expected listen to stop without error but got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("listen did not return after close")
+	}
+}
+
+func TestService_SendStreamingRequest(t *testing.T) {
+	// the second event is only sent once the first one is received, so the test fails if events are not reported live
+	firstReceived := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		_, _ = w.Write([]byte("data: {\"token\": \"first\"}\n\n"))
+		flusher.Flush()
+
+		select {
+		case <-firstReceived:
+		case <-time.After(2 * time.Second):
+			return
+		}
+
+		_, _ = w.Write([]byte("event: update\ndata: {\"token\": \"second\"}\n\n"))
+		flusher.Flush()
+
+		if r.URL.Query().Get("endless") != "" {
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	repo, err := repository.NewFilesystemFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.FilePath = filepath.Join(t.TempDir(), "staging.yaml")
+	environments := state.NewEnvironments(repo)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("events")
+	req.Spec.HTTP.URL = srv.URL
+	req.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypeSetEnv,
+		PostRequestSet: domain.PostRequestSet{
+			Target:     "token",
+			StatusCode: "200",
+			From:       domain.PostRequestSetFromResponseBody,
+			FromKey:    "$.token",
+		},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, environments, state.NewCookies(nil))
+
+	var started bool
+	var events []StreamEvent
+	handler := &StreamHandler{
+		OnStart: func(response *Response) {
+			started = response.StatusCode == http.StatusOK
+		},
+		OnEvent: func(event StreamEvent) {
+			events = append(events, event)
+			if len(events) == 1 {
+				if v := envValue(env, "token"); v != "first" {
+					t.Errorf("expected the post request to run for the first event, token is %q", v)
+				}
+				close(firstReceived)
+			}
+		},
+	}
+
+	res, err := s.SendStreamingRequest(context.Background(), req.MetaData.ID, env.MetaData.ID, handler)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !started || !res.Streamed || len(events) != 2 || events[1].Event != "update" {
+		t.Fatalf("unexpected stream, started %t, streamed %t, events %+v", started, res.Streamed, events)
+	}
+
+	if v := envValue(env, "token"); v != "second" {
+		t.Errorf("expected the post request to run for the last event, token is %q", v)
+	}
+
+	// stopping the stream keeps the events received so far
+	firstReceived = make(chan struct{})
+	events = nil
+	req.Spec.HTTP.URL = srv.URL + "?endless=1"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler.OnEvent = func(event StreamEvent) {
+		events = append(events, event)
+		switch len(events) {
+		case 1:
+			close(firstReceived)
+		case 2:
+			cancel()
+		}
+	}
+
+	res, err = s.SendStreamingRequest(ctx, req.MetaData.ID, env.MetaData.ID, handler)
+	if err != nil {
+		t.Fatalf("expected the stopped stream to be returned without error, got %v", err)
+	}
+
+	if !strings.Contains(string(res.Body), "second") {
+		t.Errorf("expected the received events in the body, got %q", res.Body)
+	}
+}
+
+func TestService_SendStreamingRequestChunkedJSON(t *testing.T) {
+	// bodies larger than the buffer of the server are chunked
+	body := "{\n  \"token\": \"abc\",\n  \"padding\": \"" + strings.Repeat("x", 8<<10) + "\"\n}\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	repo, err := repository.NewFilesystemFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.FilePath = filepath.Join(t.TempDir(), "staging.yaml")
+	environments := state.NewEnvironments(repo)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("login")
+	req.Spec.HTTP.URL = srv.URL
+	req.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypeSetEnv,
+		PostRequestSet: domain.PostRequestSet{
+			Target:     "token",
+			StatusCode: "200",
+			From:       domain.PostRequestSetFromResponseBody,
+			FromKey:    "$.token",
+		},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+	s := New(requests, environments, state.NewCookies(nil))
+
+	events := 0
+	res, err := s.SendStreamingRequest(context.Background(), req.MetaData.ID, env.MetaData.ID, &StreamHandler{
+		OnEvent: func(event StreamEvent) { events++ },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Streamed || events != 0 || !res.IsJSON {
+		t.Errorf("expected a json response which is not streamed, got streamed %t, %d events, json %t", res.Streamed, events, res.IsJSON)
+	}
+
+	if v := envValue(env, "token"); v != "abc" {
+		t.Errorf("expected the post request to run for the whole body, token is %q", v)
+	}
+}
+
+func TestService_SendStreamingRequestChunkedText(t *testing.T) {
+	// the second line is only sent once the first one is received, like a log tail
+	firstReceived := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		flusher := w.(http.Flusher)
+
+		_, _ = w.Write([]byte("starting\n"))
+		flusher.Flush()
+
+		select {
+		case <-firstReceived:
+		case <-time.After(2 * time.Second):
+			return
+		}
+
+		_, _ = w.Write([]byte("ready\n"))
+	}))
+	defer srv.Close()
+
+	streamChunked := true
+	req := domain.NewRequest("logs")
+	req.Spec.HTTP.URL = srv.URL
+	req.Spec.HTTP.Settings = &domain.HTTPClientSettings{StreamChunked: &streamChunked}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+
+	var lines []string
+	res, err := s.SendStreamingRequest(context.Background(), req.MetaData.ID, "", &StreamHandler{
+		OnEvent: func(event StreamEvent) {
+			lines = append(lines, event.Data)
+			if len(lines) == 1 {
+				close(firstReceived)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !res.Streamed || strings.Join(lines, ",") != "starting,ready" {
+		t.Errorf("expected the lines to be streamed, got streamed %t and %v", res.Streamed, lines)
+	}
+}
+
+func envValue(env *domain.Environment, key string) string {
+	for _, kv := range env.Spec.Values {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return ""
+}
+
+func TestService_SendRequestTimings(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer srv.Close()
+
+	req := domain.NewRequest("timings")
+	req.Spec.HTTP.URL = srv.URL
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+	skip := true
+	s.SetHTTPClientSettings(domain.HTTPClientSettings{InsecureSkipVerify: &skip})
+
+	res, err := s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	timings := res.Timings
+	if timings.ConnectionReused || timings.TCPConnect == 0 || timings.TLSHandshake == 0 {
+		t.Errorf("expected a new connection with a tls handshake but got %+v", timings)
+	}
+
+	if timings.TimeToFirstByte < 20*time.Millisecond {
+		t.Errorf("expected the time to first byte to include the server time but got %s", timings.TimeToFirstByte)
+	}
+
+	if sum := timings.DNSLookup + timings.TCPConnect + timings.TLSHandshake + timings.TimeToFirstByte + timings.Download; sum > timings.Total {
+		t.Errorf("expected the phases to fit in the total %s but they take %s", timings.Total, sum)
+	}
+
+	// the client is cached, so the second request reuses the connection
+	res, err = s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !res.Timings.ConnectionReused || res.Timings.TCPConnect != 0 || res.Timings.TLSHandshake != 0 {
+		t.Errorf("expected the connection to be reused but got %+v", res.Timings)
+	}
+}
+
+func TestService_SendRequestLargeBody(t *testing.T) {
+	body := strings.Repeat(`{"a": 1}`, 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/image" {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	req := domain.NewRequest("large")
+	req.Spec.HTTP.URL = srv.URL
+	req.Spec.HTTP.Settings = &domain.HTTPClientSettings{MaxResponseSize: 100}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+
+	res, err := s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(res.BodyFile)
+
+	if !res.Truncated || res.Size != int64(len(body)) || res.IsJSON || res.Binary {
+		t.Errorf("expected a truncated text response of %d bytes, got truncated %t, size %d, json %t, binary %t", len(body), res.Truncated, res.Size, res.IsJSON, res.Binary)
+	}
+
+	req.Spec.HTTP.URL = srv.URL + "/image"
+	res, err = s.SendRequest(context.Background(), req.MetaData.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Truncated || !res.Binary || res.ContentType != "image/png" {
+		t.Errorf("expected a binary image response, got truncated %t, binary %t, content type %q", res.Truncated, res.Binary, res.ContentType)
+	}
+}
+
+func TestService_SendRequestAssertions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user": {"name": "chapar"}}`))
+	}))
+	defer srv.Close()
+
+	env := domain.NewEnvironment("staging")
+	env.SetKey("userName", "chapar")
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("assertions")
+	req.Spec.HTTP.URL = srv.URL
+	req.Spec.HTTP.Request.Tests = []domain.Assertion{
+		{Enable: true, Type: domain.AssertionTypeStatus, Operator: domain.AssertionOperatorInRange, Value: "200-299"},
+		{Enable: true, Type: domain.AssertionTypeJSONPath, Target: "$.user.name", Operator: domain.AssertionOperatorEquals, Value: "{{userName}}"},
+		{Enable: true, Type: domain.AssertionTypeHeader, Target: "X-Request-ID", Operator: domain.AssertionOperatorExists},
+		{Enable: false, Type: domain.AssertionTypeBodyContains, Operator: domain.AssertionOperatorContains, Value: "error"},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, environments, state.NewCookies(nil))
+	res, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(res.TestResults) != 3 {
+		t.Fatalf("expected the three enabled assertions to be evaluated, got %+v", res.TestResults)
+	}
+
+	for i, passed := range []bool{true, true, false} {
+		if res.TestResults[i].Passed != passed {
+			t.Errorf("expected %s to pass %v, got %+v", res.TestResults[i].Assertion, passed, res.TestResults[i])
+		}
+	}
+
+	if req.Spec.HTTP.Request.Tests[1].Value != "{{userName}}" {
+		t.Errorf("expected the stored assertion to keep its variable, got %q", req.Spec.HTTP.Request.Tests[1].Value)
+	}
+}
+
+func TestService_LoadTestClient(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Query().Get("token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token": "changed"}`))
+	}))
+	defer srv.Close()
+
+	env := domain.NewEnvironment("staging")
+	env.SetKey("token", "secret")
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("load")
+	req.Spec.HTTP.URL = srv.URL + "?token={{token}}"
+	req.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypeSetEnv,
+		PostRequestSet: domain.PostRequestSet{
+			Target:     "token",
+			StatusCode: "200",
+			From:       domain.PostRequestSetFromResponseBody,
+			FromKey:    "$.token",
+		},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, environments, state.NewCookies(nil))
+	client, err := s.NewLoadTestClient(req.MetaData.ID, env.MetaData.ID, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		code, err := client.Send(context.Background())
+		if err != nil || code != http.StatusOK {
+			t.Fatalf("expected the request to succeed, got %d, %v", code, err)
+		}
+	}
+
+	// the post request is not applied, so the environment keeps its value
+	if calls.Load() != 3 || environments.GetEnvironment(env.MetaData.ID).Spec.Values[0].Value != "secret" {
+		t.Errorf("expected three requests with the environment unchanged")
+	}
+
+	srv.Close()
+	if _, err := client.Send(context.Background()); err == nil || strings.Contains(err.Error(), srv.URL) {
+		t.Errorf("expected an error without the url, got %v", err)
+	}
+}
+
+func TestService_SendRequestPostRequestRules(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/profile" {
+			_, _ = w.Write([]byte(r.URL.Query().Get("user") + " " + r.URL.Query().Get("token")))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		_, _ = w.Write([]byte(`{"id": 42, "token": "abc", "admin": true, "user": {"name": "jane"}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	repo, err := repository.NewFilesystemFromDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rule := func(target, statusCode, fromKey, scope string) domain.PostRequestSet {
+		return domain.PostRequestSet{
+			Target:     target,
+			StatusCode: statusCode,
+			From:       domain.PostRequestSetFromResponseBody,
+			FromKey:    fromKey,
+			Scope:      scope,
+		}
+	}
+
+	login := domain.NewRequest("login")
+	login.Spec.HTTP.URL = srv.URL + "/login"
+	login.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypeSetEnv,
+		Rules: []domain.PostRequestSet{
+			rule("token", "200", "$.token", domain.PostRequestScopeEnvironment),
+			rule("userId", "2xx", "$.id", domain.PostRequestScopeCollection),
+			rule("user", "", "$.user", domain.PostRequestScopeCollection),
+			rule("admin", "2XX", "$.admin", domain.PostRequestScopeGlobal),
+			rule("failed", "4xx", "$.token", domain.PostRequestScopeGlobal),
+			{Target: "requestId", From: domain.PostRequestSetFromResponseHeader, FromKey: "X-Request-Id", Scope: domain.PostRequestScopeGlobal},
+		},
+	}
+
+	profile := domain.NewRequest("profile")
+	profile.Spec.HTTP.URL = srv.URL + "/profile?user={{userId}}&token={{token}}"
+
+	col := domain.NewCollection("users")
+	col.FilePath = filepath.Join(dir, "collections", col.MetaData.Name)
+
+	requests := state.NewRequests(repo)
+	requests.AddRequest(login)
+	requests.AddRequest(profile)
+	requests.AddCollection(col)
+	requests.AddRequestToCollection(col, login)
+	requests.AddRequestToCollection(col, profile)
+	if err := requests.UpdateCollection(col, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.FilePath = filepath.Join(dir, "staging.yaml")
+	environments := state.NewEnvironments(repo)
+	environments.AddEnvironment(env, state.SourceController)
+
+	s := New(requests, environments, state.NewCookies(nil))
+	if _, err := s.SendRequest(context.Background(), login.MetaData.ID, env.MetaData.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := envValue(env, "token"); v != "abc" {
+		t.Errorf("expected the token in the environment, got %q", v)
+	}
+
+	collectionValues := map[string]string{}
+	for _, kv := range col.Spec.Variables {
+		collectionValues[kv.Key] = kv.Value
+	}
+	if collectionValues["userId"] != "42" || collectionValues["user"] != `{"name":"jane"}` {
+		t.Errorf("expected the numbers and objects to be encoded, got %v", collectionValues)
+	}
+
+	globals, err := environments.GetGlobals()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	globalValues := map[string]string{}
+	for _, kv := range globals.Spec.Values {
+		globalValues[kv.Key] = kv.Value
+	}
+	if len(globalValues) != 2 || globalValues["admin"] != "true" || globalValues["requestId"] != "req-1" {
+		t.Errorf("expected the global values of the matching rules, got %v", globalValues)
+	}
+
+	// the collection variables and the environment are applied to the other requests of the collection
+	res, err := s.SendRequest(context.Background(), profile.MetaData.ID, env.MetaData.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(res.Body) != "42 abc" {
+		t.Errorf("expected the variables to be replaced, got %q", res.Body)
+	}
+
+	// without an active environment the value is stored in the global scope
+	if _, err := s.SendRequest(context.Background(), login.MetaData.ID, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the globals are persisted in the workspace
+	saved, err := repo.LoadGlobals()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := saved.Spec.Values; len(v) != 3 || v[2].Key != "token" || v[2].Value != "abc" {
+		t.Errorf("expected the token to be stored in the globals, got %+v", v)
+	}
+}
@@ -0,0 +1,175 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/logger"
+	"github.com/chapar-rest/chapar/internal/scripting"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+// runPreRequestScript runs the pre request script of the request, the changes of python scripts are applied to req
+// and the variables it sets are stored and returned with the others, so the request can use them right away.
+func (s *Service) runPreRequestScript(ctx context.Context, req *domain.HTTPRequestSpec, variables map[string]string, sc scope) (map[string]string, error) {
+	pre := req.Request.PreRequest
+	in := scripting.Input{
+		Request:   scriptRequest(req),
+		Variables: variables,
+	}
+
+	out, err := runScript(ctx, "pre request script", pre.Type == domain.PrePostTypeShell, pre.Script, pre.Timeout, in)
+	if err != nil {
+		return nil, err
+	}
+
+	applyScriptRequest(req, in.Request, out.Request)
+
+	if err := s.storeScriptVariables(in, out, sc); err != nil {
+		return nil, err
+	}
+	return out.Variables, nil
+}
+
+// runPostRequestScript runs the post request script of the request with its response and stores the variables it sets.
+func (s *Service) runPostRequestScript(ctx context.Context, req *domain.HTTPRequestSpec, response *Response, sc scope) error {
+	in := scripting.Input{
+		Request: scriptRequest(req),
+		Response: &scripting.Response{
+			StatusCode: response.StatusCode,
+			Headers:    response.Headers,
+		},
+		Variables: sc.resolve(),
+	}
+
+	if !response.Binary {
+		in.Response.Body = string(response.Body)
+	}
+
+	post := req.Request.PostRequest
+	out, err := runScript(ctx, "post request script", post.Type == domain.PostRequestTypeShellScript, post.Script, post.Timeout, in)
+	if err != nil {
+		return err
+	}
+	return s.storeScriptVariables(in, out, sc)
+}
+
+// runScript runs the python or shell script with its timeout, or the default timeout of scripts when it is zero.
+func runScript(ctx context.Context, name string, shell bool, script string, timeout time.Duration, in scripting.Input) (*scripting.Output, error) {
+	if timeout <= 0 {
+		timeout = scripting.DefaultTimeout
+	}
+
+	scriptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	run := scripting.RunPython
+	if shell {
+		run = scripting.RunShell
+	}
+
+	out, err := run(scriptCtx, name, script, in)
+	// the timeout of the request is reported by the caller
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %s", name, timeout)
+	}
+	return out, err
+}
+
+// storeScriptVariables stores the variables the script added or changed in the active environment,
+// or in the global variables when no environment is active.
+func (s *Service) storeScriptVariables(in scripting.Input, out *scripting.Output, sc scope) error {
+	changed := out.ChangedVariables(in)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	logger.Infof("variables set by the script: %s", strings.Join(changed, ", "))
+
+	if sc.env != nil {
+		for _, k := range changed {
+			sc.env.SetKey(k, out.Variables[k])
+		}
+		return s.environments.UpdateEnvironment(sc.env, state.SourceRestService, s.stateOnly)
+	}
+
+	values := make([]domain.KeyValue, 0, len(changed))
+	for _, k := range changed {
+		values = append(values, domain.KeyValue{Key: k, Value: out.Variables[k]})
+	}
+	return s.environments.SetGlobalValues(values, s.stateOnly)
+}
+
+// scriptRequest returns the request as scripts receive it, the body is the raw body of json, xml and text requests.
+func scriptRequest(req *domain.HTTPRequestSpec) scripting.Request {
+	out := scripting.Request{
+		Method:  req.Method,
+		URL:     req.URL,
+		Headers: make(map[string]string),
+	}
+
+	if req.Request == nil {
+		return out
+	}
+
+	for _, h := range req.Request.Headers {
+		if h.Enable {
+			out.Headers[h.Key] = h.Value
+		}
+	}
+
+	switch req.Request.Body.Type {
+	case domain.BodyTypeJSON, domain.BodyTypeXML, domain.BodyTypeText:
+		out.Body = req.Request.Body.Data
+	}
+	return out
+}
+
+// applyScriptRequest applies the changes a script made to the request, the fields it did not change are left as they are.
+func applyScriptRequest(req *domain.HTTPRequestSpec, before, after scripting.Request) {
+	if after.Method != "" && after.Method != before.Method {
+		req.Method = strings.ToUpper(after.Method)
+	}
+
+	if after.URL != before.URL {
+		req.URL = after.URL
+	}
+
+	if !maps.Equal(before.Headers, after.Headers) {
+		req.Request.Headers = mapToKeyValues(after.Headers)
+	}
+
+	if after.Body != before.Body {
+		req.Request.Body.Data = after.Body
+		// form and binary bodies are replaced with the raw body the script set
+		switch req.Request.Body.Type {
+		case domain.BodyTypeJSON, domain.BodyTypeXML, domain.BodyTypeText:
+		default:
+			req.Request.Body.Type = domain.BodyTypeText
+			if IsJSON(after.Body) {
+				req.Request.Body.Type = domain.BodyTypeJSON
+			}
+		}
+	}
+}
+
+// mapToKeyValues returns the enabled key values of the map, sorted by key.
+func mapToKeyValues(m map[string]string) []domain.KeyValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]domain.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, domain.KeyValue{Key: k, Value: m[k], Enable: true})
+	}
+	return out
+}
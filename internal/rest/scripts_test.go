@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestService_SendRequestPythonScripts(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+
+	var received *http.Request
+	var receivedBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received, receivedBody = r, string(body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token": "new-token", "expires": 3600}`))
+	}))
+	defer srv.Close()
+
+	repo, err := repository.NewFilesystemFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.FilePath = filepath.Join(t.TempDir(), "staging.yaml")
+	env.Spec.Values = []domain.KeyValue{{Key: "user", Value: "jane", Enable: true}}
+	environments := state.NewEnvironments(repo)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("login")
+	req.Spec.HTTP.Method = http.MethodGet
+	req.Spec.HTTP.URL = srv.URL + "/login"
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{{Key: "X-User", Value: "{{user}}", Enable: true}}
+	req.Spec.HTTP.Request.PreRequest = domain.PreRequest{
+		Type: domain.PrePostTypePython,
+		Script: `
+import hashlib
+request["method"] = "post"
+request["url"] += "?signature=" + hashlib.sha256(request["headers"]["X-User"].encode()).hexdigest()[:8]
+request["headers"]["X-Nonce"] = "{{nonce}}"
+request["body"] = '{"user": "' + variables["user"] + '"}'
+set_variable("nonce", "n-1")
+`,
+	}
+	req.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypePythonScript,
+		Script: `
+if response["statusCode"] == 200:
+    set_variable("token", response["json"]["token"])
+    set_variable("expires", response["json"]["expires"])
+`,
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, environments, state.NewCookies(nil))
+	if _, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Method != http.MethodPost || !strings.HasPrefix(received.URL.RawQuery, "signature=") {
+		t.Errorf("expected the method and url of the script, got %s %s", received.Method, received.URL)
+	}
+
+	if received.Header.Get("X-User") != "jane" || received.Header.Get("X-Nonce") != "n-1" {
+		t.Errorf("expected the headers of the script with the variables it set, got %v", received.Header)
+	}
+
+	if receivedBody != `{"user": "jane"}` {
+		t.Errorf("expected the body of the script, got %q", receivedBody)
+	}
+
+	if envValue(env, "nonce") != "n-1" || envValue(env, "token") != "new-token" || envValue(env, "expires") != "3600" {
+		t.Errorf("expected the variables of the scripts in the environment, got %v", env.Spec.Values)
+	}
+
+	// failing pre request scripts stop the request
+	req.Spec.HTTP.Request.PreRequest.Script = "raise RuntimeError('no credentials')"
+	if _, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err == nil || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("expected the error of the script, got %v", err)
+	}
+}
+
+func TestService_SendRequestShellScripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test scripts are written for sh")
+	}
+
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("X-Session", "s-1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	repo, err := repository.NewFilesystemFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.FilePath = filepath.Join(t.TempDir(), "staging.yaml")
+	env.Spec.Values = []domain.KeyValue{
+		{Key: "user", Value: "jane", Enable: true},
+		{Key: "token", Value: "stale", Enable: true},
+	}
+	environments := state.NewEnvironments(repo)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("profile")
+	req.Spec.HTTP.URL = srv.URL + "/profile"
+	req.Spec.HTTP.Request.Headers = []domain.KeyValue{{Key: "Authorization", Value: "Bearer {{token}}", Enable: true}}
+	req.Spec.HTTP.Request.PreRequest = domain.PreRequest{
+		Type:   domain.PrePostTypeShell,
+		Script: `echo "set token=fresh-$CHAPAR_VAR_user"`,
+	}
+	req.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type:   domain.PostRequestTypeShellScript,
+		Script: `echo "set session=$CHAPAR_RESPONSE_HEADER_X_SESSION-$CHAPAR_RESPONSE_STATUS"`,
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, environments, state.NewCookies(nil))
+	if _, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if authorization != "Bearer fresh-jane" {
+		t.Errorf("expected the token of the script to be sent, got %q", authorization)
+	}
+
+	if envValue(env, "token") != "fresh-jane" || envValue(env, "session") != "s-1-201" {
+		t.Errorf("expected the variables of the scripts in the environment, got %v", env.Spec.Values)
+	}
+
+	// scripts are stopped after their timeout
+	req.Spec.HTTP.Request.PreRequest.Script = "sleep 5"
+	req.Spec.HTTP.Request.PreRequest.Timeout = 100 * time.Millisecond
+	if _, err := s.SendRequest(context.Background(), req.MetaData.ID, env.MetaData.ID); err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Errorf("expected the script to time out, got %v", err)
+	}
+}
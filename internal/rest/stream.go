@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	streamKindSSE   = "sse"
+	streamKindLines = "lines"
+)
+
+// StreamEvent is an event of a text/event-stream response, or a line of other streamed responses.
+type StreamEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Time  time.Time
+}
+
+// StreamHandler receives streamed responses while they arrive.
+type StreamHandler struct {
+	// OnStart is called with the status, headers and cookies of the response before the body is read
+	OnStart func(response *Response)
+	OnEvent func(event StreamEvent)
+}
+
+// streamKind returns how the body of the response is split into events, it is empty when the response is not streamed.
+// chunked text responses without a length are streamed line by line only when streamChunked is set,
+// otherwise they are read whole as servers chunk any large body.
+func streamKind(res *http.Response, streamChunked bool) string {
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "text/event-stream":
+		return streamKindSSE
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines", "application/json-seq":
+		return streamKindLines
+	}
+
+	if streamChunked && res.ContentLength < 0 && slices.Contains(res.TransferEncoding, "chunked") {
+		if mediaType == "" || strings.HasPrefix(mediaType, "text/") || strings.Contains(mediaType, "json") || strings.Contains(mediaType, "xml") {
+			return streamKindLines
+		}
+	}
+	return ""
+}
+
+// readStream reads the body calling onEvent for every event as soon as it is complete, the body is returned like readBody does.
+func readStream(body io.Reader, kind string, maxSize int64, onEvent func(event StreamEvent)) ([]byte, string, int64, error) {
+	raw := &bodyBuffer{maxSize: maxSize}
+	reader := bufio.NewReader(io.TeeReader(body, raw))
+
+	var sse sseParser
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" && (err == nil || kind == streamKindLines) {
+			line = strings.TrimRight(line, "\r\n")
+
+			switch kind {
+			case streamKindSSE:
+				if event, ok := sse.parseLine(line); ok {
+					onEvent(event)
+				}
+			default:
+				if strings.TrimSpace(line) != "" {
+					onEvent(StreamEvent{Data: line, Time: time.Now()})
+				}
+			}
+		}
+
+		if err != nil {
+			// the body read so far is returned with the error, like when the stream is stopped
+			data, file, size, finishErr := raw.finish(nil)
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return data, file, size, errors.Join(err, finishErr)
+		}
+	}
+}
+
+// sseParser parses the lines of an event stream as described in https://html.spec.whatwg.org/multipage/server-sent-events.html
+type sseParser struct {
+	id    string
+	event string
+	data  []string
+}
+
+// parseLine returns the event dispatched by the line, an event is dispatched by an empty line.
+func (p *sseParser) parseLine(line string) (StreamEvent, bool) {
+	if line == "" {
+		defer func() {
+			p.event = ""
+			p.data = nil
+		}()
+
+		if len(p.data) == 0 {
+			return StreamEvent{}, false
+		}
+
+		return StreamEvent{
+			ID:    p.id,
+			Event: p.event,
+			Data:  strings.Join(p.data, "\n"),
+			Time:  time.Now(),
+		}, true
+	}
+
+	// lines starting with a colon are comments
+	if strings.HasPrefix(line, ":") {
+		return StreamEvent{}, false
+	}
+
+	field, value, _ := strings.Cut(line, ":")
+	value = strings.TrimPrefix(value, " ")
+
+	switch field {
+	case "data":
+		p.data = append(p.data, value)
+	case "event":
+		p.event = value
+	case "id":
+		p.id = value
+	}
+
+	return StreamEvent{}, false
+}
+
+// eventResponse returns the response of a single event, so the post request can be applied to it.
+func eventResponse(response *Response, event StreamEvent) *Response {
+	r := &Response{
+		StatusCode: response.StatusCode,
+		Headers:    response.Headers,
+		Cookies:    response.Cookies,
+		Body:       []byte(event.Data),
+		TimePassed: response.TimePassed,
+	}
+
+	if IsJSON(event.Data) {
+		if js, err := PrettyJSON(r.Body); err == nil {
+			r.IsJSON = true
+			r.JSON = js
+		}
+	}
+
+	return r
+}
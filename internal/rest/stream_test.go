@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_readStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		body     string
+		expected []StreamEvent
+	}{
+		{
+			name: "server-sent events",
+			kind: streamKindSSE,
+			body: ": keep alive\n\nid: 1\nevent: greeting\ndata: hello\ndata: world\n\r\ndata: {\"n\": 2}\r\n\r\nretry: 100\n\ndata: incomplete",
+			expected: []StreamEvent{
+				{ID: "1", Event: "greeting", Data: "hello\nworld"},
+				{ID: "1", Data: `{"n": 2}`},
+			},
+		},
+		{
+			name: "json lines",
+			kind: streamKindLines,
+			body: "{\"n\": 1}\n\n{\"n\": 2}\r\n{\"n\": 3}",
+			expected: []StreamEvent{
+				{Data: `{"n": 1}`},
+				{Data: `{"n": 2}`},
+				{Data: `{"n": 3}`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []StreamEvent
+			body, file, size, err := readStream(strings.NewReader(tt.body), tt.kind, 1<<20, func(event StreamEvent) {
+				if event.Time.IsZero() {
+					t.Error("expected the event time to be set")
+				}
+				events = append(events, StreamEvent{ID: event.ID, Event: event.Event, Data: event.Data})
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(body) != tt.body || file != "" || size != int64(len(tt.body)) {
+				t.Errorf("expected the whole body to be returned, got %q, file %q, size %d", body, file, size)
+			}
+
+			if !reflect.DeepEqual(events, tt.expected) {
+				t.Errorf("expected events %+v but got %+v", tt.expected, events)
+			}
+		})
+	}
+}
+
+func Test_readStreamLarge(t *testing.T) {
+	body := strings.Repeat("{\"n\": 1}\n", 100)
+
+	events := 0
+	preview, file, size, err := readStream(strings.NewReader(body), streamKindLines, 100, func(event StreamEvent) {
+		events++
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(file)
+
+	if events != 100 {
+		t.Errorf("expected all the events, got %d", events)
+	}
+
+	if len(preview) != 100 || size != int64(len(body)) {
+		t.Errorf("expected a preview of the max size and the size of the body, got %d bytes and size %d", len(preview), size)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil || string(data) != body {
+		t.Errorf("expected the whole body in the file, got %d bytes, %v", len(data), err)
+	}
+}
+
+func Test_streamKind(t *testing.T) {
+	tests := []struct {
+		contentType   string
+		chunked       bool
+		streamChunked bool
+		want          string
+	}{
+		{"text/event-stream; charset=utf-8", false, false, streamKindSSE},
+		{"application/x-ndjson", true, false, streamKindLines},
+		// servers chunk any large body, so chunked responses are not streamed by themselves
+		{"application/json", true, false, ""},
+		{"text/plain", true, false, ""},
+		{"", true, false, ""},
+		// unless the client opted in
+		{"text/plain; charset=utf-8", true, true, streamKindLines},
+		{"application/json", true, true, streamKindLines},
+		{"", true, true, streamKindLines},
+		{"image/png", true, true, ""},
+		{"text/plain", false, true, ""},
+	}
+
+	for _, tt := range tests {
+		res := &http.Response{Header: http.Header{"Content-Type": {tt.contentType}}, ContentLength: 10}
+		if tt.chunked {
+			res.TransferEncoding = []string{"chunked"}
+			res.ContentLength = -1
+		}
+
+		if got := streamKind(res, tt.streamChunked); got != tt.want {
+			t.Errorf("streamKind(%q, chunked %t, stream chunked %t) = %q, want %q", tt.contentType, tt.chunked, tt.streamChunked, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// timingTrace records the time of each phase of a request, when redirects are followed the last request is recorded.
+type timingTrace struct {
+	mx *sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+func newTimingTrace(start time.Time) *timingTrace {
+	return &timingTrace{mx: &sync.Mutex{}, start: start}
+}
+
+func (t *timingTrace) set(field *time.Time) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	*field = time.Now()
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// a new request of a redirect starts over
+			t.mx.Lock()
+			defer t.mx.Unlock()
+			t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+			t.connectStart, t.connectDone = time.Time{}, time.Time{}
+			t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+			t.wroteRequest, t.firstByte = time.Time{}, time.Time{}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mx.Lock()
+			defer t.mx.Unlock()
+			t.reused = info.Reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			// dialing several addresses in parallel reports several starts, the first one counts
+			t.mx.Lock()
+			defer t.mx.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone:          func(string, string, error) { t.set(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+	}
+}
+
+// timings returns the duration of each phase, end is when the body was read completely.
+func (t *timingTrace) timings(end time.Time) domain.HTTPTimings {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	between := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return 0
+		}
+		return to.Sub(from)
+	}
+
+	return domain.HTTPTimings{
+		DNSLookup:        between(t.dnsStart, t.dnsDone),
+		TCPConnect:       between(t.connectStart, t.connectDone),
+		TLSHandshake:     between(t.tlsStart, t.tlsDone),
+		TimeToFirstByte:  between(t.wroteRequest, t.firstByte),
+		Download:         between(t.firstByte, end),
+		Total:            between(t.start, end),
+		ConnectionReused: t.reused,
+	}
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+// openTunnel opens the tunnel of the pre request and returns its local address with the address of the request url
+// it replaces, release must be called once the request is done. release is nil when the request has no tunnel.
+func (s *Service) openTunnel(ctx context.Context, pre domain.PreRequest, rawURL string, variables map[string]string) (string, string, func(), error) {
+	switch {
+	case pre.Type == domain.PrePostTypeSSHTunnel && pre.SShTunnel != nil:
+	case pre.Type == domain.PrePostTypeK8sTunnel && pre.KubernetesTunnel != nil:
+	default:
+		return "", "", nil, nil
+	}
+
+	addr, err := urlAddr(rawURL)
+	if err != nil {
+		return "", "", nil, err
+	}
+	host, port, _ := net.SplitHostPort(addr)
+
+	if pre.Type == domain.PrePostTypeK8sTunnel {
+		cfg := *pre.KubernetesTunnel
+		for _, v := range []*string{&cfg.Target, &cfg.Context, &cfg.Namespace} {
+			*v = ReplaceText(*v, variables)
+		}
+
+		// the target port defaults to the port of the request url
+		if cfg.TargetPort == 0 {
+			cfg.TargetPort, _ = strconv.Atoi(port)
+		}
+
+		localAddr, release, err := s.tunnels.AcquireKubectl(ctx, cfg)
+		if err != nil {
+			return "", "", nil, err
+		}
+		return localAddr, addr, release, nil
+	}
+
+	// the flags would be ignored silently, so the request fails instead
+	if len(pre.SShTunnel.Flags) > 0 {
+		return "", "", nil, errors.New("ssh tunnel flags are not supported, remove them from the request")
+	}
+
+	cfg := *pre.SShTunnel
+	for _, v := range []*string{&cfg.Host, &cfg.User, &cfg.Password, &cfg.KeyPath, &cfg.KnownHostsPath, &cfg.TargetHost} {
+		*v = ReplaceText(*v, variables)
+	}
+
+	if cfg.TargetHost != "" {
+		host = cfg.TargetHost
+	}
+	if cfg.TargetPort != 0 {
+		port = strconv.Itoa(cfg.TargetPort)
+	}
+
+	localAddr, release, err := s.tunnels.AcquireSSH(ctx, cfg, net.JoinHostPort(host, port))
+	if err != nil {
+		return "", "", nil, err
+	}
+	return localAddr, addr, release, nil
+}
+
+// urlAddr returns the host and port the url connects to, the port defaults to the one of its scheme.
+func urlAddr(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("url %s has no host", rawURL)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// tunnelClient returns a copy of the client whose connections to addr go to the local address of a tunnel instead,
+// the url of the request is kept so its host header and tls verification are not changed.
+func tunnelClient(client *http.Client, addr, localAddr string) *http.Client {
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := base.Clone()
+	// the tunnel replaces the proxy
+	transport.Proxy = nil
+
+	dialer := &net.Dialer{}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if address == addr {
+			address = localAddr
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	c := *client
+	c.Transport = transport
+	return &c
+}
@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+	"github.com/chapar-rest/chapar/internal/tunnel/tunneltest"
+)
+
+func Test_urlAddr(t *testing.T) {
+	tests := map[string]string{
+		"http://api.internal/users":       "api.internal:80",
+		"https://api.internal/users":      "api.internal:443",
+		"http://api.internal:8080/users":  "api.internal:8080",
+		"https://[::1]:8443/users?page=1": "[::1]:8443",
+	}
+
+	for in, want := range tests {
+		if got, err := urlAddr(in); err != nil || got != want {
+			t.Errorf("urlAddr(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := urlAddr("/users"); err == nil {
+		t.Errorf("expected an error for urls without a host")
+	}
+}
+
+func Test_tunnelClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer srv.Close()
+
+	// the host does not resolve, so the request only succeeds through the tunnel
+	client := tunnelClient(&http.Client{Transport: http.DefaultTransport}, "api.internal.invalid:80", strings.TrimPrefix(srv.URL, "http://"))
+	defer client.CloseIdleConnections()
+
+	res, err := client.Get("http://api.internal.invalid/users")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	if string(body) != "api.internal.invalid" {
+		t.Errorf("expected the host of the request to be kept, got %q", body)
+	}
+}
+
+func TestService_SendRequestSSHTunnelError(t *testing.T) {
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addr := closed.Addr().(*net.TCPAddr)
+	_ = closed.Close()
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := domain.NewRequest("users")
+	req.Spec.HTTP.URL = "http://api.internal.invalid/users"
+	req.Spec.HTTP.Request.PreRequest = domain.PreRequest{
+		Type: domain.PrePostTypeSSHTunnel,
+		SShTunnel: &domain.SShTunnel{
+			Host:           "{{bastion}}",
+			Port:           addr.Port,
+			User:           "jane",
+			Password:       "secret",
+			KnownHostsPath: knownHosts,
+		},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+	_, err = s.SendRequestWithVariables(context.Background(), req.MetaData.ID, "", map[string]string{"bastion": "127.0.0.1"})
+	if err == nil || !strings.Contains(err.Error(), "ssh server "+addr.String()) {
+		t.Errorf("expected the error of the tunnel, got %v", err)
+	}
+
+	// flags are not supported, the request fails instead of ignoring them
+	req.Spec.HTTP.Request.PreRequest.SShTunnel.Flags = []string{"-o", "ServerAliveInterval=30"}
+	_, err = s.SendRequestWithVariables(context.Background(), req.MetaData.ID, "", map[string]string{"bastion": "127.0.0.1"})
+	if err == nil || !strings.Contains(err.Error(), "ssh tunnel flags are not supported") {
+		t.Errorf("expected the flags to be rejected, got %v", err)
+	}
+}
+
+func TestService_SendRequestKubernetesTunnel(t *testing.T) {
+	// the server stands in for the local port of the forward
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("from " + r.Host))
+	}))
+	defer srv.Close()
+
+	port := srv.Listener.Addr().(*net.TCPAddr).Port
+	argsFile := tunneltest.StubKubectl(t, port)
+
+	req := domain.NewRequest("users")
+	req.Spec.HTTP.URL = "http://api.payments.svc:8080/users"
+	req.Spec.HTTP.Request.PreRequest = domain.PreRequest{
+		Type: domain.PrePostTypeK8sTunnel,
+		KubernetesTunnel: &domain.KubernetesTunnel{
+			Target:      "{{service}}",
+			TargetType:  domain.KubernetesTargetService,
+			Namespace:   "payments",
+			IdleTimeout: 300 * time.Millisecond,
+		},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	s := New(requests, state.NewEnvironments(nil), state.NewCookies(nil))
+	defer s.Tunnels().CloseAll()
+	variables := map[string]string{"service": "api"}
+
+	kubectlRuns := func() []string {
+		data, _ := os.ReadFile(argsFile)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err := s.SendRequestWithVariables(context.Background(), req.MetaData.ID, "", variables)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(res.Body) != "from api.payments.svc:8080" {
+			t.Errorf("expected the request to go through the forward, got %q", res.Body)
+		}
+	}
+
+	if runs := kubectlRuns(); len(runs) != 1 || runs[0] != "--namespace payments port-forward --address 127.0.0.1 service/api :8080" {
+		t.Errorf("expected the forward to be reused, got %v", runs)
+	}
+
+	// the forward is closed once it is idle for its timeout
+	time.Sleep(600 * time.Millisecond)
+	if _, err := s.SendRequestWithVariables(context.Background(), req.MetaData.ID, "", variables); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if runs := kubectlRuns(); len(runs) != 2 {
+		t.Errorf("expected kubectl to be started again, got %v", runs)
+	}
+
+	variables["service"] = "missing"
+	if _, err := s.SendRequestWithVariables(context.Background(), req.MetaData.ID, "", variables); err == nil || !strings.Contains(err.Error(), `services "missing" not found`) {
+		t.Errorf("expected the error of kubectl, got %v", err)
+	}
+}
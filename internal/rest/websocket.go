@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	WebSocketDirectionIn  = "in"
+	WebSocketDirectionOut = "out"
+)
+
+// WebSocketMessage is a message sent or received on a websocket connection.
+type WebSocketMessage struct {
+	Direction string
+	// Type is one of the domain.WebSocketMessageType values
+	Type string
+	Data []byte
+	Time time.Time
+}
+
+// WebSocketConn is an open websocket connection of a request.
+type WebSocketConn struct {
+	conn *websocket.Conn
+	// variables are resolved once on connect and applied to every sent message
+	variables map[string]string
+
+	writeMx *sync.Mutex
+	closed  bool
+}
+
+// ConnectWebSocket opens the websocket connection of the request with the given id using the given environment.
+// ctx only covers the handshake, the connection stays open until it is closed by either side.
+func (s *Service) ConnectWebSocket(ctx context.Context, requestID, activeEnvironmentID string) (*WebSocketConn, error) {
+	req := s.requests.GetRequest(requestID)
+	if req == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	if req.Spec.WebSocket == nil {
+		return nil, fmt.Errorf("request %s is not a websocket request", req.MetaData.Name)
+	}
+
+	activeEnvironment, err := s.getEnvironment(activeEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := s.newScope(req.CollectionID, activeEnvironment, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := sc.resolve()
+	spec := replaceVariables(req.Spec.WebSocket.ToHTTPRequestSpec(), variables)
+	settings := s.clientSettings(spec.Settings, activeEnvironment)
+
+	if settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, settings.Timeout)
+		defer cancel()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, websocketURL(spec.URL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	applyHeaders(httpReq, spec.Request)
+	applyAuth(httpReq, spec.Request.Auth)
+
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := proxyFunc(settings.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+		Jar:             s.cookieJar(activeEnvironment),
+	}
+
+	conn, res, err := dialer.DialContext(ctx, httpReq.URL.String(), httpReq.Header)
+	if err != nil {
+		if res != nil {
+			err = fmt.Errorf("%w, server responded with %s", err, res.Status)
+		}
+		return nil, wrapContextError(ctx, settings.Timeout, err)
+	}
+
+	return &WebSocketConn{
+		conn:      conn,
+		variables: variables,
+		writeMx:   &sync.Mutex{},
+	}, nil
+}
+
+// websocketURL turns http urls into websocket ones, so the url of the matching rest endpoint can be used as is.
+func websocketURL(u string) string {
+	switch {
+	case strings.HasPrefix(u, "http://"):
+		return "ws://" + strings.TrimPrefix(u, "http://")
+	case strings.HasPrefix(u, "https://"):
+		return "wss://" + strings.TrimPrefix(u, "https://")
+	}
+	return u
+}
+
+// Send sends a message of the given type after applying the environment variables, binary data is base64 encoded.
+func (c *WebSocketConn) Send(messageType, data string) (WebSocketMessage, error) {
+	data = ReplaceText(data, c.variables)
+
+	frameType := websocket.TextMessage
+	payload := []byte(data)
+
+	switch messageType {
+	case domain.WebSocketMessageTypeJSON:
+		if !json.Valid(payload) {
+			return WebSocketMessage{}, errors.New("message is not a valid json")
+		}
+	case domain.WebSocketMessageTypeBinary:
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return WebSocketMessage{}, fmt.Errorf("binary message must be base64 encoded: %w", err)
+		}
+		frameType = websocket.BinaryMessage
+		payload = decoded
+	}
+
+	c.writeMx.Lock()
+	defer c.writeMx.Unlock()
+
+	if err := c.conn.WriteMessage(frameType, payload); err != nil {
+		return WebSocketMessage{}, err
+	}
+
+	return WebSocketMessage{
+		Direction: WebSocketDirectionOut,
+		Type:      messageType,
+		Data:      payload,
+		Time:      time.Now(),
+	}, nil
+}
+
+// Listen calls onMessage for every received message until the connection is closed.
+// it returns nil when the connection is closed normally by either side.
+func (c *WebSocketConn) Listen(onMessage func(msg WebSocketMessage)) error {
+	for {
+		frameType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.writeMx.Lock()
+			closed := c.closed
+			c.writeMx.Unlock()
+
+			if closed || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return err
+		}
+
+		msgType := domain.WebSocketMessageTypeText
+		if frameType == websocket.BinaryMessage {
+			msgType = domain.WebSocketMessageTypeBinary
+		} else if json.Valid(data) {
+			msgType = domain.WebSocketMessageTypeJSON
+		}
+
+		onMessage(WebSocketMessage{
+			Direction: WebSocketDirectionIn,
+			Type:      msgType,
+			Data:      data,
+			Time:      time.Now(),
+		})
+	}
+}
+
+// Close sends a close message to the server and closes the connection.
+func (c *WebSocketConn) Close() error {
+	c.writeMx.Lock()
+	defer c.writeMx.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second)); err != nil {
+		fmt.Println("failed to send websocket close message", err)
+	}
+
+	return c.conn.Close()
+}
@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadData reads the rows of a data file, every row is the variables of an iteration.
+// json files hold an array of objects, other files are read as csv whose first line holds the variable names.
+func LoadData(path string) ([]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		rows, err = parseJSONData(data)
+	} else {
+		rows, err = parseCSVData(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file %s: %w", filepath.Base(path), err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("data file %s has no rows", filepath.Base(path))
+	}
+	return rows, nil
+}
+
+func parseCSVData(data []byte) ([]map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	// the byte order mark some editors write would be part of the first name
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if name != "" && i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseJSONData(data []byte) ([]map[string]string, error) {
+	var objects []map[string]any
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, fmt.Errorf("expected an array of objects: %w", err)
+	}
+
+	rows := make([]map[string]string, 0, len(objects))
+	for _, obj := range objects {
+		row := make(map[string]string, len(obj))
+		for k, v := range obj {
+			row[k] = dataValue(v)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// dataValue returns strings as they are and other values as json, so numbers and objects can be used in bodies.
+func dataValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadData(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected []map[string]string
+		wantErr  bool
+	}{
+		{
+			name:    "csv",
+			file:    "users.csv",
+			content: "\ufeffname, age\nalice, 30\n\"bob, jr\",\n",
+			expected: []map[string]string{
+				{"name": "alice", "age": "30"},
+				{"name": "bob, jr", "age": ""},
+			},
+		},
+		{
+			name:    "json",
+			file:    "users.json",
+			content: `[{"name": "alice", "age": 30, "admin": true, "tags": ["a"]}, {"name": null}]`,
+			expected: []map[string]string{
+				{"name": "alice", "age": "30", "admin": "true", "tags": `["a"]`},
+				{"name": ""},
+			},
+		},
+		{
+			name:    "json object",
+			file:    "users.json",
+			content: `{"name": "alice"}`,
+			wantErr: true,
+		},
+		{
+			name:    "no rows",
+			file:    "users.csv",
+			content: "name,age\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := LoadData(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v but got %v", tt.wantErr, err)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("expected %+v but got %+v", tt.expected, rows)
+			}
+		})
+	}
+}
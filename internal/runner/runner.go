@@ -0,0 +1,260 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Options configures a run of a collection.
+type Options struct {
+	EnvironmentID string
+	// Iterations is the number of times the requests are run, zero runs them once.
+	// it is ignored when a data file is given, as every row of the file is an iteration
+	Iterations int
+	// DataFile is a csv or json file whose rows are the variables of the iterations
+	DataFile string
+	// Variables override the variables of the environment and the data file in every iteration
+	Variables     map[string]string
+	Delay         time.Duration
+	StopOnFailure bool
+}
+
+// Result is the outcome of a request in an iteration of a run.
+type Result struct {
+	Iteration int
+	// Variables are the variables of the iteration from the data file
+	Variables   map[string]string
+	RequestID   string
+	Name        string
+	Method      string
+	URL         string
+	Status      string
+	StatusCode  int
+	Duration    time.Duration
+	TestResults []domain.AssertionResult
+	// Message explains why the request failed or was skipped
+	Message string
+}
+
+// Report is the outcome of a run, Stopped is set when the run ended early because of a failure or cancellation.
+type Report struct {
+	// CollectionID is empty when a single request is run
+	CollectionID   string
+	CollectionName string
+	Iterations     int
+	Started        time.Time
+	Duration       time.Duration
+	Results        []Result
+	Stopped        bool
+}
+
+// Count returns the number of results with the given status.
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Runner runs requests one after another. Post request actions update the environment,
+// so the values they set are used by the following requests.
+type Runner struct {
+	requests    *state.Requests
+	restService *rest.Service
+}
+
+func New(requests *state.Requests, restService *rest.Service) *Runner {
+	return &Runner{
+		requests:    requests,
+		restService: restService,
+	}
+}
+
+// Run runs the requests of the collection in order, onResult is called after each request, it can be nil.
+// canceling ctx stops the run and returns the report of the requests run so far.
+func (r *Runner) Run(ctx context.Context, collectionID string, opts Options, onResult func(Result)) (*Report, error) {
+	collection := r.requests.GetCollection(collectionID)
+	if collection == nil {
+		return nil, fmt.Errorf("collection with id %s not found", collectionID)
+	}
+
+	// the requests are read once, so changes during the run do not affect it
+	ids := make([]string, 0, len(collection.Spec.Requests))
+	for _, req := range collection.Spec.Requests {
+		ids = append(ids, req.MetaData.ID)
+	}
+
+	report := &Report{
+		CollectionID:   collection.MetaData.ID,
+		CollectionName: collection.MetaData.Name,
+	}
+	return r.run(ctx, report, ids, opts, onResult)
+}
+
+// RunRequest runs a single request, like Run does for the requests of a collection.
+func (r *Runner) RunRequest(ctx context.Context, requestID string, opts Options, onResult func(Result)) (*Report, error) {
+	if r.requests.GetRequest(requestID) == nil {
+		return nil, fmt.Errorf("request with id %s not found", requestID)
+	}
+
+	return r.run(ctx, &Report{}, []string{requestID}, opts, onResult)
+}
+
+func (r *Runner) run(ctx context.Context, report *Report, requestIDs []string, opts Options, onResult func(Result)) (*Report, error) {
+	var rows []map[string]string
+	if opts.DataFile != "" {
+		var err error
+		if rows, err = LoadData(opts.DataFile); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Iterations = max(opts.Iterations, 1)
+	if len(rows) > 0 {
+		report.Iterations = len(rows)
+	}
+	report.Started = time.Now()
+
+	sent := 0
+	for i := 1; i <= report.Iterations && !report.Stopped; i++ {
+		var row map[string]string
+		if len(rows) > 0 {
+			row = rows[i-1]
+		}
+		variables := mergeVariables(row, opts.Variables)
+
+		for _, id := range requestIDs {
+			if sent > 0 && opts.Delay > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(opts.Delay):
+				}
+			}
+
+			if ctx.Err() != nil {
+				report.Stopped = true
+				break
+			}
+
+			result := r.runRequest(ctx, id, opts.EnvironmentID, variables)
+			result.Iteration = i
+			result.Variables = row
+			if result.Status != StatusSkipped {
+				sent++
+			}
+
+			report.Results = append(report.Results, result)
+			if onResult != nil {
+				onResult(result)
+			}
+
+			if result.Status == StatusFailed && opts.StopOnFailure {
+				report.Stopped = true
+				break
+			}
+		}
+	}
+
+	report.Duration = time.Since(report.Started)
+	return report, nil
+}
+
+// mergeVariables returns the variables of the row with the overrides applied, the row is not changed.
+func mergeVariables(row, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return row
+	}
+
+	out := make(map[string]string, len(row)+len(overrides))
+	for k, v := range row {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// runRequest sends the request, http and GraphQL requests are sent and others are skipped.
+func (r *Runner) runRequest(ctx context.Context, requestID, environmentID string, variables map[string]string) Result {
+	// the latest state of the request is used, as the collection may hold a stale copy
+	req := r.requests.GetRequest(requestID)
+	if req == nil {
+		return Result{RequestID: requestID, Status: StatusSkipped, Message: "request not found"}
+	}
+
+	result := Result{
+		RequestID: requestID,
+		Name:      req.MetaData.Name,
+	}
+
+	switch {
+	case req.Spec.HTTP != nil:
+		result.Method = req.Spec.HTTP.Method
+		result.URL = req.Spec.HTTP.URL
+	case req.Spec.GraphQL != nil:
+		result.Method = domain.RequestMethodPOST
+		result.URL = req.Spec.GraphQL.URL
+	default:
+		result.Status = StatusSkipped
+		result.Message = fmt.Sprintf("%s requests are not supported by the runner", req.MetaData.Type)
+		return result
+	}
+
+	res, err := r.restService.SendRequestWithVariables(ctx, requestID, environmentID, variables)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Message = err.Error()
+		return result
+	}
+
+	// the whole body of large responses is not needed once the tests ran
+	if res.BodyFile != "" {
+		os.Remove(res.BodyFile)
+	}
+
+	result.StatusCode = res.StatusCode
+	result.Duration = res.TimePassed
+	result.TestResults = res.TestResults
+	result.Status, result.Message = evaluate(res)
+	return result
+}
+
+// evaluate returns the status of the response, it fails when an assertion fails,
+// or, for requests without assertions, when the status code is an error.
+func evaluate(res *rest.Response) (string, string) {
+	if len(res.TestResults) == 0 {
+		if res.StatusCode >= http.StatusBadRequest {
+			return StatusFailed, fmt.Sprintf("status is %d %s", res.StatusCode, http.StatusText(res.StatusCode))
+		}
+		return StatusPassed, ""
+	}
+
+	failed := 0
+	for _, t := range res.TestResults {
+		if !t.Passed {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return StatusFailed, fmt.Sprintf("%d of %d tests failed", failed, len(res.TestResults))
+	}
+	return StatusPassed, ""
+}
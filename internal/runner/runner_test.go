@@ -0,0 +1,219 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+func TestRunner_Run(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			_, _ = w.Write([]byte(`{"token": "secret"}`))
+		case "/me":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"name": "chapar"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	repo, err := repository.NewFilesystemFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env := domain.NewEnvironment("staging")
+	env.FilePath = filepath.Join(t.TempDir(), "staging.yaml")
+	environments := state.NewEnvironments(repo)
+	environments.AddEnvironment(env, state.SourceController)
+
+	login := domain.NewRequest("login")
+	login.Spec.HTTP.URL = srv.URL + "/login"
+	login.Spec.HTTP.Request.PostRequest = domain.PostRequest{
+		Type: domain.PostRequestTypeSetEnv,
+		PostRequestSet: domain.PostRequestSet{
+			Target:     "token",
+			StatusCode: "200",
+			From:       domain.PostRequestSetFromResponseBody,
+			FromKey:    "$.token",
+		},
+	}
+
+	me := domain.NewRequest("me")
+	me.Spec.HTTP.URL = srv.URL + "/me"
+	me.Spec.HTTP.Request.Headers = []domain.KeyValue{{Key: "Authorization", Value: "Bearer {{token}}", Enable: true}}
+	me.Spec.HTTP.Request.Tests = []domain.Assertion{
+		{Enable: true, Type: domain.AssertionTypeJSONPath, Target: "$.name", Operator: domain.AssertionOperatorEquals, Value: "chapar"},
+	}
+
+	socket := domain.NewWebSocketRequest("socket")
+
+	missing := domain.NewRequest("missing")
+	missing.Spec.HTTP.URL = srv.URL + "/missing"
+
+	collection := domain.NewCollection("auth")
+	requests := state.NewRequests(nil)
+	for _, req := range []*domain.Request{login, me, socket, missing} {
+		requests.AddRequest(req)
+		collection.AddRequest(req)
+	}
+	requests.AddCollection(collection)
+
+	r := New(requests, rest.New(requests, environments, state.NewCookies(nil)))
+
+	var reported []Result
+	report, err := r.Run(context.Background(), collection.MetaData.ID, Options{EnvironmentID: env.MetaData.ID, Iterations: 2}, func(res Result) {
+		reported = append(reported, res)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Results) != 8 || len(reported) != 8 || report.Stopped {
+		t.Fatalf("expected two iterations of four requests, got %+v", report.Results)
+	}
+
+	for i, status := range []string{StatusPassed, StatusPassed, StatusSkipped, StatusFailed} {
+		if res := report.Results[i]; res.Status != status || res.Iteration != 1 {
+			t.Errorf("expected %s to be %s in the first iteration, got %+v", res.Name, status, res)
+		}
+	}
+
+	if report.Results[4].Iteration != 2 || report.Count(StatusPassed) != 4 || report.Count(StatusFailed) != 2 {
+		t.Errorf("unexpected report, %d passed and %d failed", report.Count(StatusPassed), report.Count(StatusFailed))
+	}
+
+	// the run stops at the first failure
+	report, err = r.Run(context.Background(), collection.MetaData.ID, Options{EnvironmentID: env.MetaData.ID, Iterations: 2, StopOnFailure: true}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !report.Stopped || len(report.Results) != 4 {
+		t.Errorf("expected the run to stop at the missing request, got %+v", report.Results)
+	}
+
+	// canceling the run during the delay stops it
+	ctx, cancel := context.WithCancel(context.Background())
+	report, err = r.Run(ctx, collection.MetaData.ID, Options{Delay: time.Minute}, func(Result) {
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !report.Stopped || len(report.Results) != 1 {
+		t.Errorf("expected the run to stop after the first request, got %+v", report.Results)
+	}
+}
+
+func TestRunner_RunRequestData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"user": "` + r.URL.Query().Get("user") + `"}`))
+	}))
+	defer srv.Close()
+
+	env := domain.NewEnvironment("staging")
+	env.SetKey("user", "from-env")
+	environments := state.NewEnvironments(nil)
+	environments.AddEnvironment(env, state.SourceController)
+
+	req := domain.NewRequest("user")
+	req.Spec.HTTP.URL = srv.URL + "?user={{user}}"
+	req.Spec.HTTP.Request.Tests = []domain.Assertion{
+		{Enable: true, Type: domain.AssertionTypeJSONPath, Target: "$.user", Operator: domain.AssertionOperatorEquals, Value: "{{expected}}"},
+	}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	dataFile := filepath.Join(t.TempDir(), "users.csv")
+	if err := os.WriteFile(dataFile, []byte("user,expected\nalice,alice\nbob,carol\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := New(requests, rest.New(requests, environments, state.NewCookies(nil)))
+	report, err := r.RunRequest(context.Background(), req.MetaData.ID, Options{EnvironmentID: env.MetaData.ID, Iterations: 5, DataFile: dataFile}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Iterations != 2 || len(report.Results) != 2 {
+		t.Fatalf("expected an iteration per row, got %+v", report.Results)
+	}
+
+	first, second := report.Results[0], report.Results[1]
+	if first.Status != StatusPassed || first.Iteration != 1 || first.Variables["user"] != "alice" {
+		t.Errorf("expected the first row to pass, got %+v", first)
+	}
+
+	if second.Status != StatusFailed || second.Iteration != 2 || second.TestResults[0].Message != `value is "bob"` {
+		t.Errorf("expected the second row to fail with the value of its row, got %+v", second)
+	}
+
+	// variables override the rows of the data file
+	report, err = r.RunRequest(context.Background(), req.MetaData.ID, Options{EnvironmentID: env.MetaData.ID, DataFile: dataFile, Variables: map[string]string{"expected": "bob"}}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Results[0].Status != StatusFailed || report.Results[1].Status != StatusPassed || report.Results[1].Variables["expected"] != "carol" {
+		t.Errorf("expected the variables to override the rows, got %+v", report.Results)
+	}
+
+	if _, err := r.RunRequest(context.Background(), req.MetaData.ID, Options{DataFile: filepath.Join(t.TempDir(), "missing.csv")}, nil); err == nil {
+		t.Errorf("expected an error for a missing data file")
+	}
+}
+
+func TestRunner_RunLargeResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 1000)))
+	}))
+	defer srv.Close()
+
+	// responses above the max response size are written to temp files
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	req := domain.NewRequest("large")
+	req.Spec.HTTP.URL = srv.URL
+	req.Spec.HTTP.Settings = &domain.HTTPClientSettings{MaxResponseSize: 100}
+
+	requests := state.NewRequests(nil)
+	requests.AddRequest(req)
+
+	r := New(requests, rest.New(requests, state.NewEnvironments(nil), state.NewCookies(nil)))
+	report, err := r.RunRequest(context.Background(), req.MetaData.ID, Options{Iterations: 3}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Count(StatusPassed) != 3 {
+		t.Fatalf("expected three passed iterations, got %+v", report.Results)
+	}
+
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected the response files to be removed, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,90 @@
+package scripting
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/logger"
+)
+
+// waitDelay is how long the output of a stopped command is read before it is given up.
+const waitDelay = 500 * time.Millisecond
+
+// runCommand runs the command logging its output to the console with the given name, onLine is called with every line
+// of stdout and the lines it returns true for are not logged. the error has the last line of stderr, which is the
+// error message for most interpreters.
+func runCommand(cmd *exec.Cmd, name string, onLine func(line string) bool) error {
+	// the output is read through pipes of our own, so children of the script which keep them open
+	// can not block the command once it is stopped
+	stdout, stdoutWriter := io.Pipe()
+	stderr, stderrWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+	cmd.WaitDelay = waitDelay
+
+	var wg sync.WaitGroup
+	var lastError string
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		readLines(stdout, func(line string) {
+			if onLine != nil && onLine(line) {
+				return
+			}
+			if strings.TrimSpace(line) != "" {
+				logger.Infof("%s: %s", name, line)
+			}
+		})
+	}()
+
+	go func() {
+		defer wg.Done()
+		readLines(stderr, func(line string) {
+			if strings.TrimSpace(line) == "" {
+				return
+			}
+			lastError = strings.TrimSpace(line)
+			logger.Errorf("%s: %s", name, line)
+		})
+	}()
+
+	err := cmd.Run()
+	_ = stdoutWriter.Close()
+	_ = stderrWriter.Close()
+	wg.Wait()
+
+	// the script succeeded, but left a process in the background which still holds its output
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && lastError != "" {
+		return fmt.Errorf("%s failed: %s", name, lastError)
+	}
+	return fmt.Errorf("%s failed: %w", name, err)
+}
+
+// readLines calls f with every line of r, lines can be longer than the buffer of a scanner.
+func readLines(r io.Reader, f func(line string)) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			f(strings.TrimRight(line, "\r\n"))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package scripting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// outputMarker is the prefix of the line the prelude writes the output of the script in.
+const outputMarker = "::chapar-output::"
+
+// pythonPrelude reads the input from stdin, runs the script and writes the request and the variables
+// to stdout once it exits, tracebacks point to the lines of the script itself.
+const pythonPrelude = `import atexit
+import json
+import sys
+
+_input = json.loads(sys.stdin.read() or "{}")
+request = _input.get("request") or {}
+response = _input.get("response")
+variables = _input.get("variables") or {}
+del _input
+
+if response is not None:
+    try:
+        response["json"] = json.loads(response.get("body") or "")
+    except ValueError:
+        response["json"] = None
+
+
+def get_variable(key, default=None):
+    return variables.get(key, default)
+
+
+def set_variable(key, value):
+    variables[key] = value
+
+
+def _chapar_output():
+    sys.stdout.flush()
+    sys.stdout.write("\n` + outputMarker + `" + json.dumps({"request": request, "variables": variables}, default=str) + "\n")
+    sys.stdout.flush()
+
+
+atexit.register(_chapar_output)
+
+with open(sys.argv[1], encoding="utf-8") as _script:
+    _code = compile(_script.read(), sys.argv[2], "exec")
+del _script
+
+exec(_code)
+`
+
+// RunPython runs the python script with the local python interpreter, name is used in the console logs and errors.
+// the script gets the request, response and variables as globals, see the readme for the contract.
+func RunPython(ctx context.Context, name, script string, in Input) (*Output, error) {
+	interpreter, err := pythonInterpreter()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "chapar-script-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	preludePath := filepath.Join(dir, "chapar_prelude.py")
+	if err := os.WriteFile(preludePath, []byte(pythonPrelude), 0600); err != nil {
+		return nil, err
+	}
+
+	scriptPath := filepath.Join(dir, "script.py")
+	if err := os.WriteFile(scriptPath, []byte(script), 0600); err != nil {
+		return nil, err
+	}
+
+	input, err := json.Marshal(normalizeInput(in))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, interpreter, preludePath, scriptPath, name)
+	cmd.Stdin = strings.NewReader(string(input))
+	cmd.Env = append(os.Environ(), "PYTHONIOENCODING=utf-8", "PYTHONUNBUFFERED=1")
+
+	var raw string
+	err = runCommand(cmd, name, func(line string) bool {
+		if strings.HasPrefix(line, outputMarker) {
+			raw = strings.TrimPrefix(line, outputMarker)
+			return true
+		}
+		return false
+	})
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if raw == "" {
+		return nil, fmt.Errorf("%s did not return its output", name)
+	}
+
+	var out rawOutput
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return nil, fmt.Errorf("%s returned an invalid request or variables: %w", name, err)
+	}
+	return out.output(), nil
+}
+
+// pythonInterpreter returns the path of python3, or python where python 3 is installed as python like on windows.
+func pythonInterpreter() (string, error) {
+	for _, name := range []string{"python3", "python"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("python is not installed, python3 or python must be in PATH to run python scripts")
+}
+
+// normalizeInput replaces the nil maps of the input with empty ones, so the script always gets objects.
+func normalizeInput(in Input) Input {
+	if in.Request.Headers == nil {
+		in.Request.Headers = map[string]string{}
+	}
+
+	if in.Variables == nil {
+		in.Variables = map[string]string{}
+	}
+
+	if in.Response != nil && in.Response.Headers == nil {
+		res := *in.Response
+		res.Headers = map[string]string{}
+		in.Response = &res
+	}
+	return in
+}
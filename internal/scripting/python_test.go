@@ -0,0 +1,91 @@
+package scripting
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/logger"
+)
+
+func skipWithoutPython(t *testing.T) {
+	t.Helper()
+	if _, err := pythonInterpreter(); err != nil {
+		t.Skip(err)
+	}
+}
+
+func TestRunPython(t *testing.T) {
+	skipWithoutPython(t)
+
+	var mx sync.Mutex
+	var logs []domain.Log
+	logger.Subscribe(func(l domain.Log) {
+		mx.Lock()
+		defer mx.Unlock()
+		logs = append(logs, l)
+	})
+
+	script := `
+print("token is", variables["token"])
+request["headers"]["Authorization"] = "Bearer " + variables["token"]
+request["url"] = request["url"] + "?signed=1"
+set_variable("count", response["json"]["count"] + 1)
+set_variable("user", response["json"]["user"])
+`
+	in := Input{
+		Request: Request{Method: "GET", URL: "http://localhost/items"},
+		Response: &Response{
+			StatusCode: 200,
+			Body:       `{"count": 1, "user": {"name": "jane"}}`,
+		},
+		Variables: map[string]string{"token": "abc", "host": "localhost"},
+	}
+
+	out, err := RunPython(context.Background(), "test script", script, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.Request.URL != "http://localhost/items?signed=1" || out.Request.Headers["Authorization"] != "Bearer abc" {
+		t.Errorf("expected the changes to the request, got %+v", out.Request)
+	}
+
+	if out.Variables["count"] != "2" || out.Variables["user"] != `{"name":"jane"}` {
+		t.Errorf("expected the values to be encoded, got %v", out.Variables)
+	}
+
+	if changed := out.ChangedVariables(in); strings.Join(changed, ",") != "count,user" {
+		t.Errorf("expected count and user to be changed, got %v", changed)
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+	found := false
+	for _, l := range logs {
+		if l.Level == logger.LevelInfo && l.Message == "test script: token is abc" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the output of the script in the logs, got %v", logs)
+	}
+}
+
+func TestRunPython_Errors(t *testing.T) {
+	skipWithoutPython(t)
+
+	_, err := RunPython(context.Background(), "test script", "\n\nraise ValueError('bad token')", Input{})
+	if err == nil || !strings.Contains(err.Error(), "ValueError: bad token") {
+		t.Errorf("expected the error of the script, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := RunPython(ctx, "test script", "import time\ntime.sleep(10)", Input{}); err != context.DeadlineExceeded {
+		t.Errorf("expected the script to be stopped, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package scripting
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultTimeout is the timeout of scripts which do not set one.
+const DefaultTimeout = 30 * time.Second
+
+// Request is the request a script receives, its variables are already replaced.
+type Request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// Response is the response a post request script receives.
+type Response struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+// Input is what a script receives, Response is nil for pre request scripts.
+type Input struct {
+	Request   Request           `json:"request"`
+	Response  *Response         `json:"response"`
+	Variables map[string]string `json:"variables"`
+}
+
+// Output is the request and the variables as the script left them.
+type Output struct {
+	Request   Request
+	Variables map[string]string
+}
+
+// ChangedVariables returns the variables of the output which are new or have a different value than in the input, sorted by key.
+func (o *Output) ChangedVariables(in Input) []string {
+	out := make([]string, 0)
+	for k, v := range o.Variables {
+		if old, ok := in.Variables[k]; !ok || old != v {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// rawOutput is the output the script writes, variables can be set to any value and are converted to strings.
+type rawOutput struct {
+	Request   Request        `json:"request"`
+	Variables map[string]any `json:"variables"`
+}
+
+func (r rawOutput) output() *Output {
+	out := &Output{
+		Request:   r.Request,
+		Variables: make(map[string]string, len(r.Variables)),
+	}
+
+	for k, v := range r.Variables {
+		out.Variables[k] = formatValue(v)
+	}
+	return out
+}
+
+// formatValue keeps strings as they are and encodes other values, like numbers and objects, as compact JSON.
+func formatValue(v any) string {
+	if v == nil {
+		return ""
+	}
+
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
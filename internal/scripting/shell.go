@@ -0,0 +1,158 @@
+package scripting
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// setPrefix is the prefix of the lines of stdout which set variables, like `set token=abc`.
+	setPrefix = "set "
+	// variablePrefix is the prefix of the environment variables of the variables, so they do not replace
+	// the environment of the script like PATH.
+	variablePrefix = "CHAPAR_VAR_"
+)
+
+// RunShell runs the shell script with sh, or cmd on windows, name is used in the console logs and errors.
+// the variables are passed as CHAPAR_VAR_ environment variables and the request and response as CHAPAR_ variables,
+// the bodies are written to files next to the script as they can be larger than environment variables may be.
+// the lines of stdout like `set KEY=VALUE` set variables and are not logged.
+func RunShell(ctx context.Context, name, script string, in Input) (*Output, error) {
+	dir, err := os.MkdirTemp("", "chapar-script-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	cmd, err := shellCommand(ctx, dir, script)
+	if err != nil {
+		return nil, err
+	}
+	env, err := shellEnv(dir, in)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Env = append(os.Environ(), env...)
+
+	out := &Output{
+		Request:   in.Request,
+		Variables: make(map[string]string, len(in.Variables)),
+	}
+	for k, v := range in.Variables {
+		out.Variables[k] = v
+	}
+
+	err = runCommand(cmd, name, func(line string) bool {
+		key, value, ok := parseSetLine(line)
+		if ok {
+			out.Variables[key] = value
+		}
+		return ok
+	})
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// shellCommand writes the script to dir and returns the command which runs it.
+func shellCommand(ctx context.Context, dir, script string) (*exec.Cmd, error) {
+	if runtime.GOOS == "windows" {
+		path := filepath.Join(dir, "script.cmd")
+		// commands are not echoed, so only the output of the script is logged
+		if err := os.WriteFile(path, []byte("@echo off\r\n"+script), 0600); err != nil {
+			return nil, err
+		}
+		return exec.CommandContext(ctx, "cmd", "/C", path), nil
+	}
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, "script.sh")
+	if err := os.WriteFile(path, []byte(script), 0600); err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, sh, path), nil
+}
+
+// parseSetLine returns the variable a `set KEY=VALUE` line sets, the value is kept as is.
+func parseSetLine(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, setPrefix) {
+		return "", "", false
+	}
+
+	key, value, ok := strings.Cut(strings.TrimPrefix(trimmed, setPrefix), "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// shellEnv returns the environment variables of the input and writes the bodies to dir, variables whose names can not be
+// environment variable names are left out.
+func shellEnv(dir string, in Input) ([]string, error) {
+	env := make([]string, 0, len(in.Variables)+len(in.Request.Headers)+4)
+	for k, v := range in.Variables {
+		if k == "" || strings.ContainsAny(k, "=\x00") {
+			continue
+		}
+		env = append(env, variablePrefix+k+"="+v)
+	}
+
+	requestBody := filepath.Join(dir, "request_body")
+	if err := os.WriteFile(requestBody, []byte(in.Request.Body), 0600); err != nil {
+		return nil, err
+	}
+
+	env = append(env,
+		"CHAPAR_REQUEST_METHOD="+in.Request.Method,
+		"CHAPAR_REQUEST_URL="+in.Request.URL,
+		"CHAPAR_REQUEST_BODY_FILE="+requestBody,
+	)
+	for k, v := range in.Request.Headers {
+		env = append(env, "CHAPAR_REQUEST_HEADER_"+envName(k)+"="+v)
+	}
+
+	if in.Response != nil {
+		responseBody := filepath.Join(dir, "response_body")
+		if err := os.WriteFile(responseBody, []byte(in.Response.Body), 0600); err != nil {
+			return nil, err
+		}
+
+		env = append(env,
+			"CHAPAR_RESPONSE_STATUS="+strconv.Itoa(in.Response.StatusCode),
+			"CHAPAR_RESPONSE_BODY_FILE="+responseBody,
+		)
+		for k, v := range in.Response.Headers {
+			env = append(env, "CHAPAR_RESPONSE_HEADER_"+envName(k)+"="+v)
+		}
+	}
+	return env, nil
+}
+
+// envName returns the name in upper case with the characters which are not letters or digits replaced by underscores,
+// so Content-Type becomes CONTENT_TYPE.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
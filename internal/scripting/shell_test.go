@@ -0,0 +1,82 @@
+package scripting
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test script is written for sh")
+	}
+
+	script := `
+echo "fetching token for $CHAPAR_VAR_user"
+echo "set token=$CHAPAR_VAR_user-$CHAPAR_REQUEST_METHOD-$CHAPAR_REQUEST_HEADER_X_REQUEST_ID"
+echo "  set status = $CHAPAR_RESPONSE_STATUS"
+echo "set size=$(wc -c < "$CHAPAR_RESPONSE_BODY_FILE" | tr -d ' ')"
+echo "set path=$CHAPAR_VAR_PATH"
+echo "set empty="
+echo "set =ignored"
+`
+	in := Input{
+		Request: Request{
+			Method:  "POST",
+			URL:     "http://localhost/login",
+			Headers: map[string]string{"X-Request-Id": "r1"},
+		},
+		// the body is larger than a single environment variable may be on linux
+		Response:  &Response{StatusCode: 201, Body: strings.Repeat("a", 200<<10)},
+		Variables: map[string]string{"user": "jane", "my.var": "dotted", "PATH": "/nowhere"},
+	}
+
+	out, err := RunShell(context.Background(), "test script", script, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// variables do not replace the environment of the script, so wc and tr are found
+	if out.Variables["token"] != "jane-POST-r1" || out.Variables["status"] != " 201" ||
+		out.Variables["size"] != "204800" || out.Variables["path"] != "/nowhere" {
+		t.Errorf("expected the variables set by the script, got %v", out.Variables)
+	}
+
+	if changed := out.ChangedVariables(in); strings.Join(changed, ",") != "empty,path,size,status,token" {
+		t.Errorf("expected empty, path, size, status and token to be changed, got %v", changed)
+	}
+}
+
+func TestRunShell_Errors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test script is written for sh")
+	}
+
+	_, err := RunShell(context.Background(), "test script", "echo 'vault: permission denied' >&2\nexit 2", Input{})
+	if err == nil || !strings.Contains(err.Error(), "vault: permission denied") {
+		t.Errorf("expected the error of the script, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := RunShell(ctx, "test script", "sleep 10", Input{}); err != context.DeadlineExceeded {
+		t.Errorf("expected the script to be stopped, got %v", err)
+	}
+}
+
+func Test_envName(t *testing.T) {
+	tests := map[string]string{
+		"Content-Type":  "CONTENT_TYPE",
+		"x-request-id":  "X_REQUEST_ID",
+		"Authorization": "AUTHORIZATION",
+		"h.é":           "H__",
+	}
+
+	for in, want := range tests {
+		if got := envName(in); got != want {
+			t.Errorf("envName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
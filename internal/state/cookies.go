@@ -0,0 +1,202 @@
+package state
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/safemap"
+)
+
+type CookieJarChangeListener func(jar *domain.CookieJar, source Source, action Action)
+
+// Cookies keeps the cookie jars of the active workspace, one per environment.
+// Jars are loaded lazily and written back to the repository on every change.
+type Cookies struct {
+	cookieJarChangeListeners []CookieJarChangeListener
+
+	mx   *sync.Mutex
+	jars *safemap.Map[*domain.CookieJar]
+
+	repository repository.Repository
+}
+
+func NewCookies(repository repository.Repository) *Cookies {
+	return &Cookies{
+		mx:         &sync.Mutex{},
+		jars:       safemap.New[*domain.CookieJar](),
+		repository: repository,
+	}
+}
+
+func (m *Cookies) AddCookieJarChangeListener(listener CookieJarChangeListener) {
+	m.cookieJarChangeListeners = append(m.cookieJarChangeListeners, listener)
+}
+
+func (m *Cookies) notifyCookieJarChange(jar *domain.CookieJar, source Source, action Action) {
+	for _, listener := range m.cookieJarChangeListeners {
+		listener(jar, source, action)
+	}
+}
+
+// getJar returns the jar with the given name loading it from the repository if needed, m.mx must be held.
+func (m *Cookies) getJar(name string) (*domain.CookieJar, error) {
+	if jar, ok := m.jars.Get(name); ok {
+		return jar, nil
+	}
+
+	jar := domain.NewCookieJar(name)
+	if m.repository != nil {
+		var err error
+		if jar, err = m.repository.LoadCookieJar(name); err != nil {
+			return nil, err
+		}
+	}
+
+	m.jars.Set(name, jar)
+	return jar, nil
+}
+
+// updateJar applies update to the jar with the given name and persists it, the listeners are notified with a copy
+// of the jar once m.mx is released so they can read the cookies back. the jar is left as is when update fails.
+func (m *Cookies) updateJar(name string, source Source, update func(jar *domain.CookieJar) error) error {
+	m.mx.Lock()
+	jar, err := m.getJar(name)
+	if err != nil {
+		m.mx.Unlock()
+		return err
+	}
+
+	if err := update(jar); err != nil {
+		m.mx.Unlock()
+		return err
+	}
+
+	if m.repository != nil {
+		err = m.repository.UpdateCookieJar(jar)
+	}
+	clone := jar.Clone()
+	m.mx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	m.notifyCookieJarChange(clone, source, ActionUpdate)
+	return nil
+}
+
+// GetCookies returns a copy of the cookies of the jar, expired cookies are left out.
+func (m *Cookies) GetCookies(name string) ([]domain.Cookie, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	jar, err := m.getJar(name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	out := make([]domain.Cookie, 0, len(jar.Spec.Cookies))
+	for _, c := range jar.Spec.Cookies {
+		if !c.Expired(now) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// CookiesForURL returns the cookies of the jar which should be sent with a request to u.
+func (m *Cookies) CookiesForURL(name string, u *url.URL) ([]*http.Cookie, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	jar, err := m.getJar(name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	out := make([]*http.Cookie, 0)
+	for _, c := range jar.Spec.Cookies {
+		if c.Matches(u, now) {
+			out = append(out, c.ToHTTP())
+		}
+	}
+	return out, nil
+}
+
+// SetCookiesFromResponse stores the cookies received from u.
+func (m *Cookies) SetCookiesFromResponse(name string, u *url.URL, cookies []*http.Cookie, source Source) error {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	return m.updateJar(name, source, func(jar *domain.CookieJar) error {
+		now := time.Now()
+		for _, c := range cookies {
+			cookie, err := domain.NewCookieFromHTTP(u, c, now)
+			if err != nil {
+				fmt.Println("failed to store", err)
+				continue
+			}
+			jar.Spec.Set(cookie, now)
+		}
+		jar.Spec.RemoveExpired(now)
+		return nil
+	})
+}
+
+// UpdateCookie replaces the old cookie with the updated one, it adds the cookie when old does not exist.
+func (m *Cookies) UpdateCookie(name string, old, updated domain.Cookie, source Source) error {
+	return m.updateJar(name, source, func(jar *domain.CookieJar) error {
+		m.removeCookie(jar, old)
+		jar.Spec.Set(updated, time.Now())
+		return nil
+	})
+}
+
+func (m *Cookies) RemoveCookie(name string, cookie domain.Cookie, source Source) error {
+	return m.updateJar(name, source, func(jar *domain.CookieJar) error {
+		if !m.removeCookie(jar, cookie) {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (m *Cookies) removeCookie(jar *domain.CookieJar, cookie domain.Cookie) bool {
+	for i, c := range jar.Spec.Cookies {
+		if domain.SameCookie(c, cookie) {
+			jar.Spec.Cookies = append(jar.Spec.Cookies[:i], jar.Spec.Cookies[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ClearDomain removes the cookies of the given domain, all cookies are removed when domain is empty.
+func (m *Cookies) ClearDomain(name, domainName string, source Source) error {
+	return m.updateJar(name, source, func(jar *domain.CookieJar) error {
+		cookies := make([]domain.Cookie, 0)
+		for _, c := range jar.Spec.Cookies {
+			if domainName != "" && c.Domain != domainName {
+				cookies = append(cookies, c)
+			}
+		}
+		jar.Spec.Cookies = cookies
+		return nil
+	})
+}
+
+// ClearCache drops the loaded jars, it should be called when the active workspace changes.
+func (m *Cookies) ClearCache() {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.jars = safemap.New[*domain.CookieJar]()
+}
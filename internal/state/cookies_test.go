@@ -0,0 +1,52 @@
+package state
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+)
+
+func TestCookies_ListenerReadsBack(t *testing.T) {
+	m := NewCookies(nil)
+
+	var got []domain.Cookie
+	m.AddCookieJarChangeListener(func(jar *domain.CookieJar, source Source, action Action) {
+		// listeners like the cookies page read the jar back from the store
+		cookies, err := m.GetCookies(jar.MetaData.Name)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		got = cookies
+	})
+
+	u, _ := url.Parse("https://api.example.com/login")
+	done := make(chan error, 1)
+	go func() {
+		done <- m.SetCookiesFromResponse(domain.DefaultCookieJarName, u, []*http.Cookie{{Name: "session", Value: "abc"}}, SourceRestService)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out storing the cookies, the listener is called while the store is locked")
+	}
+
+	if len(got) != 1 || got[0].Name != "session" || got[0].Value != "abc" {
+		t.Errorf("expected the listener to read the stored cookie, got %+v", got)
+	}
+
+	// a failed update leaves the jar as is and does not notify
+	got = nil
+	if err := m.RemoveCookie(domain.DefaultCookieJarName, domain.Cookie{Name: "missing"}, SourceController); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected no notification, got %+v", got)
+	}
+}
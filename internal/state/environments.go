@@ -1,6 +1,8 @@
 package state
 
 import (
+	"sync"
+
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/internal/repository"
 	"github.com/chapar-rest/chapar/internal/safemap"
@@ -9,15 +11,21 @@ import (
 type (
 	EnvironmentChangeListener       func(environment *domain.Environment, source Source, action Action)
 	ActiveEnvironmentChangeListener func(*domain.Environment)
+	GlobalsChangeListener           func(globals *domain.Globals, source Source)
 )
 
 type Environments struct {
 	environmentChangeListeners       []EnvironmentChangeListener
 	activeEnvironmentChangeListeners []ActiveEnvironmentChangeListener
+	globalsChangeListeners           []GlobalsChangeListener
 	environments                     *safemap.Map[*domain.Environment]
 
 	activeEnvironment *domain.Environment
 
+	// globals are loaded on first use, globalsMx guards them as post requests update them while requests are sent
+	globalsMx *sync.Mutex
+	globals   *domain.Globals
+
 	repository repository.Repository
 }
 
@@ -25,6 +33,7 @@ func NewEnvironments(repository repository.Repository) *Environments {
 	return &Environments{
 		repository:   repository,
 		environments: safemap.New[*domain.Environment](),
+		globalsMx:    &sync.Mutex{},
 	}
 }
 
@@ -36,6 +45,18 @@ func (m *Environments) AddActiveEnvironmentChangeListener(listener ActiveEnviron
 	m.activeEnvironmentChangeListeners = append(m.activeEnvironmentChangeListeners, listener)
 }
 
+// AddGlobalsChangeListener adds a listener which is called with a copy of the globals when their values change,
+// post requests change them while requests are sent so it may be called from other goroutines.
+func (m *Environments) AddGlobalsChangeListener(listener GlobalsChangeListener) {
+	m.globalsChangeListeners = append(m.globalsChangeListeners, listener)
+}
+
+func (m *Environments) notifyGlobalsChange(globals *domain.Globals, source Source) {
+	for _, listener := range m.globalsChangeListeners {
+		listener(globals, source)
+	}
+}
+
 func (m *Environments) notifyEnvironmentChange(environment *domain.Environment, source Source, action Action) {
 	for _, listener := range m.environmentChangeListeners {
 		listener(environment, source, action)
@@ -145,6 +166,7 @@ func (m *Environments) LoadEnvironmentsFromDisk() ([]*domain.Environment, error)
 	}
 
 	m.environments.Clear()
+	m.clearGlobals()
 
 	for _, env := range envs {
 		m.environments.Set(env.MetaData.ID, env)
@@ -152,3 +174,78 @@ func (m *Environments) LoadEnvironmentsFromDisk() ([]*domain.Environment, error)
 
 	return envs, nil
 }
+
+// getGlobals returns the global variables loading them from the repository if needed, m.globalsMx must be held.
+func (m *Environments) getGlobals() (*domain.Globals, error) {
+	if m.globals != nil {
+		return m.globals, nil
+	}
+
+	globals := domain.NewGlobals()
+	if m.repository != nil {
+		var err error
+		if globals, err = m.repository.LoadGlobals(); err != nil {
+			return nil, err
+		}
+	}
+
+	m.globals = globals
+	return globals, nil
+}
+
+// GetGlobals returns a copy of the global variables of the workspace.
+func (m *Environments) GetGlobals() (*domain.Globals, error) {
+	m.globalsMx.Lock()
+	defer m.globalsMx.Unlock()
+
+	globals, err := m.getGlobals()
+	if err != nil {
+		return nil, err
+	}
+	return globals.Clone(), nil
+}
+
+// SetGlobalValues sets the values of the global variables, adding the ones which do not exist,
+// they are persisted unless stateOnly is true.
+func (m *Environments) SetGlobalValues(values []domain.KeyValue, stateOnly bool) error {
+	return m.updateGlobals(SourceRestService, stateOnly, func(globals *domain.Globals) {
+		for _, v := range values {
+			globals.SetKey(v.Key, v.Value)
+		}
+	})
+}
+
+// SetGlobals replaces the values of the global variables, an empty list clears them.
+// they are persisted unless stateOnly is true.
+func (m *Environments) SetGlobals(values []domain.KeyValue, source Source, stateOnly bool) error {
+	return m.updateGlobals(source, stateOnly, func(globals *domain.Globals) {
+		globals.Spec.Values = make([]domain.KeyValue, len(values))
+		copy(globals.Spec.Values, values)
+	})
+}
+
+func (m *Environments) updateGlobals(source Source, stateOnly bool, update func(globals *domain.Globals)) error {
+	m.globalsMx.Lock()
+	globals, err := m.getGlobals()
+	if err != nil {
+		m.globalsMx.Unlock()
+		return err
+	}
+
+	update(globals)
+	if m.repository != nil && !stateOnly {
+		err = m.repository.UpdateGlobals(globals)
+	}
+	clone := globals.Clone()
+	m.globalsMx.Unlock()
+
+	m.notifyGlobalsChange(clone, source)
+	return err
+}
+
+func (m *Environments) clearGlobals() {
+	m.globalsMx.Lock()
+	defer m.globalsMx.Unlock()
+
+	m.globals = nil
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/safemap"
+)
+
+// ResponseHistory keeps the response histories of the requests of the active workspace.
+// Histories are loaded lazily, each response is written to the repository when it is added.
+type ResponseHistory struct {
+	mx        *sync.Mutex
+	histories *safemap.Map[*domain.ResponseHistory]
+
+	// size is the number of responses kept per request
+	size int
+
+	repository repository.Repository
+}
+
+func NewResponseHistory(repository repository.Repository) *ResponseHistory {
+	return &ResponseHistory{
+		mx:         &sync.Mutex{},
+		histories:  safemap.New[*domain.ResponseHistory](),
+		size:       domain.DefaultHistorySize,
+		repository: repository,
+	}
+}
+
+// getHistory returns the history of the request loading it from the repository if needed, m.mx must be held.
+func (m *ResponseHistory) getHistory(requestID string) (*domain.ResponseHistory, error) {
+	if history, ok := m.histories.Get(requestID); ok {
+		return history, nil
+	}
+
+	history := domain.NewResponseHistory(requestID)
+	if m.repository != nil {
+		var err error
+		if history, err = m.repository.LoadResponseHistory(requestID); err != nil {
+			return nil, err
+		}
+	}
+
+	m.histories.Set(requestID, history)
+	return history, nil
+}
+
+// GetResponses returns the responses of the request from the newest to the oldest.
+func (m *ResponseHistory) GetResponses(requestID string) ([]domain.HTTPResponse, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	history, err := m.getHistory(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Clone().Spec.Responses, nil
+}
+
+// AddResponse adds the response to the history of the request, the oldest responses are dropped once the history is full.
+func (m *ResponseHistory) AddResponse(requestID string, response domain.HTTPResponse) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	history, err := m.getHistory(requestID)
+	if err != nil {
+		return err
+	}
+
+	updated := history.Clone()
+	dropped := updated.Add(response, m.size)
+
+	if m.repository != nil {
+		if err := m.repository.AddResponseHistoryEntry(requestID, response); err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(dropped))
+		for _, r := range dropped {
+			ids = append(ids, r.ID)
+		}
+
+		if err := m.repository.DeleteResponseHistoryEntries(requestID, ids); err != nil {
+			return err
+		}
+	}
+
+	m.histories.Set(requestID, updated)
+	return nil
+}
+
+// ClearResponses removes the history of the request.
+func (m *ResponseHistory) ClearResponses(requestID string) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if m.repository != nil {
+		if err := m.repository.DeleteResponseHistory(requestID); err != nil {
+			return err
+		}
+	}
+
+	m.histories.Delete(requestID)
+	return nil
+}
+
+// ClearCache drops the loaded histories, it should be called when the active workspace changes.
+func (m *ResponseHistory) ClearCache() {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.histories = safemap.New[*domain.ResponseHistory]()
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+)
+
+func TestResponseHistory_AddResponse(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := repository.NewFilesystemFromDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := NewResponseHistory(repo)
+	m.size = 3
+
+	now := time.Now()
+	ids := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		response := domain.NewHTTPResponse(domain.HTTPResponseDetail{StatusCode: 200, Response: "ok"}, "", now.Add(time.Duration(i)*time.Second))
+		ids = append(ids, response.ID)
+		if err := m.AddResponse("req", response); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// every response has its own file and the dropped ones are removed
+	entries, err := os.ReadDir(filepath.Join(dir, "history", "req"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 history files, got %d", len(entries))
+	}
+
+	// the history is loaded from the files from the newest to the oldest
+	responses, err := NewResponseHistory(repo).GetResponses("req")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(responses) != 3 || responses[0].ID != ids[4] || responses[1].ID != ids[3] || responses[2].ID != ids[2] {
+		t.Errorf("expected the 3 newest responses, got %+v", responses)
+	}
+
+	if err := m.ClearResponses("req"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "history", "req")); !os.IsNotExist(err) {
+		t.Errorf("expected the history to be removed, got %v", err)
+	}
+}
+
+func TestResponseHistory_TruncatedBody(t *testing.T) {
+	// the size limit falls in the middle of a two bytes rune
+	body := "a" + strings.Repeat("é", domain.MaxHistoryBodySize/2)
+	response := domain.NewHTTPResponse(domain.HTTPResponseDetail{StatusCode: 200, Response: body}, "", time.Now())
+
+	if !response.BodyTruncated || len(response.Body) != domain.MaxHistoryBodySize-1 {
+		t.Errorf("expected the body to be cut before the rune, got %d bytes", len(response.Body))
+	}
+
+	if !utf8.ValidString(response.Body) {
+		t.Errorf("expected the cut body to be valid utf-8")
+	}
+}
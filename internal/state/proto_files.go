@@ -0,0 +1,92 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+)
+
+type ProtoFilesChangeListener func(list *domain.ProtoFileList, source Source, action Action)
+
+// ProtoFiles keeps the proto files of the active workspace, the list is loaded lazily
+// and written back to the repository on every change.
+type ProtoFiles struct {
+	protoFilesChangeListeners []ProtoFilesChangeListener
+
+	mx   *sync.Mutex
+	list *domain.ProtoFileList
+
+	repository repository.Repository
+}
+
+func NewProtoFiles(repository repository.Repository) *ProtoFiles {
+	return &ProtoFiles{
+		mx:         &sync.Mutex{},
+		repository: repository,
+	}
+}
+
+func (m *ProtoFiles) AddProtoFilesChangeListener(listener ProtoFilesChangeListener) {
+	m.protoFilesChangeListeners = append(m.protoFilesChangeListeners, listener)
+}
+
+func (m *ProtoFiles) notifyProtoFilesChange(list *domain.ProtoFileList, source Source, action Action) {
+	for _, listener := range m.protoFilesChangeListeners {
+		listener(list, source, action)
+	}
+}
+
+// getList returns the list loading it from the repository if needed, m.mx must be held.
+func (m *ProtoFiles) getList() (*domain.ProtoFileList, error) {
+	if m.list != nil {
+		return m.list, nil
+	}
+
+	list := domain.NewProtoFileList("")
+	if m.repository != nil {
+		var err error
+		if list, err = m.repository.LoadProtoFiles(); err != nil {
+			return nil, err
+		}
+	}
+
+	m.list = list
+	return list, nil
+}
+
+// GetProtoFiles returns a copy of the proto files of the workspace.
+func (m *ProtoFiles) GetProtoFiles() (*domain.ProtoFileList, error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	list, err := m.getList()
+	if err != nil {
+		return nil, err
+	}
+	return list.Clone(), nil
+}
+
+// UpdateProtoFiles replaces the proto files of the workspace.
+func (m *ProtoFiles) UpdateProtoFiles(list *domain.ProtoFileList, source Source) error {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if m.repository != nil {
+		if err := m.repository.UpdateProtoFiles(list); err != nil {
+			return err
+		}
+	}
+
+	m.list = list.Clone()
+	m.notifyProtoFilesChange(list, source, ActionUpdate)
+	return nil
+}
+
+// ClearCache drops the loaded list, it should be called when the active workspace changes.
+func (m *ProtoFiles) ClearCache() {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.list = nil
+}
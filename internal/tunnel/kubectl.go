@@ -0,0 +1,222 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/logger"
+)
+
+const (
+	// readyTimeout is how long kubectl has to start forwarding
+	readyTimeout = 30 * time.Second
+	readyPoll    = 100 * time.Millisecond
+)
+
+// forwardingLine matches the line kubectl prints once it listens, like "Forwarding from 127.0.0.1:8080 -> 80".
+var forwardingLine = regexp.MustCompile(`Forwarding from 127\.0\.0\.1:(\d+) ->`)
+
+// Kubectl is a kubectl port-forward process, connections to its local address are forwarded to the pod or service.
+type Kubectl struct {
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	addr   string
+
+	done chan struct{}
+	// lastError is the last line kubectl printed to stderr
+	lastError string
+	err       error
+}
+
+// OpenKubectl runs kubectl port-forward for the target of the config and returns once its local port accepts
+// connections, ctx only bounds the wait as the process runs until the tunnel is closed.
+func OpenKubectl(ctx context.Context, cfg domain.KubernetesTunnel, targetPort int) (*Kubectl, error) {
+	if cfg.Target == "" {
+		return nil, errors.New("kubernetes target is not set")
+	}
+
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return nil, errors.New("kubectl is not installed, it must be in PATH to forward ports")
+	}
+
+	processCtx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(processCtx, kubectl, kubectlArgs(cfg, targetPort)...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start kubectl: %w", err)
+	}
+
+	t := &Kubectl{
+		cmd:    cmd,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	ports := make(chan string, 1)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		scanLines(stdout, func(line string) {
+			if m := forwardingLine.FindStringSubmatch(line); m != nil {
+				select {
+				case ports <- m[1]:
+				default:
+				}
+			}
+		})
+	}()
+
+	go func() {
+		defer wg.Done()
+		scanLines(stderr, func(line string) {
+			if strings.TrimSpace(line) == "" {
+				return
+			}
+			t.lastError = strings.TrimSpace(line)
+			logger.Errorf("kubectl port-forward %s: %s", cfg.Target, line)
+		})
+	}()
+
+	go func() {
+		// the output is read before waiting, so the last error is known once done is closed
+		wg.Wait()
+		t.err = cmd.Wait()
+		close(t.done)
+	}()
+
+	if err := t.waitReady(ctx, ports); err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// waitReady waits for kubectl to report its local port and for the port to accept connections.
+func (t *Kubectl) waitReady(ctx context.Context, ports <-chan string) error {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	select {
+	case port := <-ports:
+		t.addr = net.JoinHostPort("127.0.0.1", port)
+	case <-t.done:
+		return t.exitError()
+	case <-ctx.Done():
+		return fmt.Errorf("kubectl port-forward did not start: %w", ctx.Err())
+	}
+
+	for {
+		conn, err := net.DialTimeout("tcp", t.addr, readyPoll)
+		if err == nil {
+			_ = conn.Close()
+			return nil
+		}
+
+		select {
+		case <-t.done:
+			return t.exitError()
+		case <-ctx.Done():
+			return fmt.Errorf("local port %s of kubectl port-forward is not ready: %w", t.addr, ctx.Err())
+		case <-time.After(readyPoll):
+		}
+	}
+}
+
+func (t *Kubectl) exitError() error {
+	if t.lastError != "" {
+		return fmt.Errorf("kubectl port-forward failed: %s", t.lastError)
+	}
+	return fmt.Errorf("kubectl port-forward exited: %v", t.err)
+}
+
+// LocalAddr returns the local address connections are forwarded from, like 127.0.0.1:8080.
+func (t *Kubectl) LocalAddr() string {
+	return t.addr
+}
+
+// Done is closed once the kubectl process exits.
+func (t *Kubectl) Done() <-chan struct{} {
+	return t.done
+}
+
+// Check returns the error of kubectl once it exited, like when the pod it forwards to was deleted.
+func (t *Kubectl) Check(ctx context.Context) error {
+	select {
+	case <-t.done:
+		return t.exitError()
+	default:
+		return nil
+	}
+}
+
+// Close stops the kubectl process.
+func (t *Kubectl) Close() error {
+	t.cancel()
+	<-t.done
+	return nil
+}
+
+func kubectlArgs(cfg domain.KubernetesTunnel, targetPort int) []string {
+	args := make([]string, 0, 10)
+	if cfg.Context != "" {
+		args = append(args, "--context", cfg.Context)
+	}
+
+	if cfg.Namespace != "" {
+		args = append(args, "--namespace", cfg.Namespace)
+	}
+
+	local := ""
+	if cfg.LocalPort != 0 {
+		local = strconv.Itoa(cfg.LocalPort)
+	}
+
+	return append(args, "port-forward", "--address", "127.0.0.1", kubectlTarget(cfg), local+":"+strconv.Itoa(targetPort))
+}
+
+// kubectlTarget returns the resource to forward to, like service/api, targets with a type like svc/api are kept as they are.
+func kubectlTarget(cfg domain.KubernetesTunnel) string {
+	if strings.Contains(cfg.Target, "/") {
+		return cfg.Target
+	}
+
+	if cfg.TargetType == domain.KubernetesTargetService {
+		return "service/" + cfg.Target
+	}
+	return "pod/" + cfg.Target
+}
+
+func scanLines(r io.Reader, f func(line string)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		f(scanner.Text())
+	}
+	// drain the rest, so kubectl is not blocked on a long line
+	_, _ = io.Copy(io.Discard, r)
+}
@@ -0,0 +1,117 @@
+package tunnel
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/tunnel/tunneltest"
+)
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestOpenKubectl(t *testing.T) {
+	port := freePort(t)
+	argsFile := tunneltest.StubKubectl(t, port)
+
+	// the port is only ready after a while, like a forward which is still connecting
+	listeners := make(chan net.Listener, 1)
+	time.AfterFunc(300*time.Millisecond, func() {
+		l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		listeners <- l
+	})
+	defer func() {
+		if l := <-listeners; l != nil {
+			_ = l.Close()
+		}
+	}()
+
+	cfg := domain.KubernetesTunnel{
+		Target:     "api",
+		TargetType: domain.KubernetesTargetService,
+		Context:    "staging",
+		Namespace:  "payments",
+	}
+
+	start := time.Now()
+	tunnel, err := OpenKubectl(context.Background(), cfg, 8080)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if time.Since(start) < 300*time.Millisecond {
+		t.Errorf("expected to wait for the port to be ready")
+	}
+
+	if tunnel.LocalAddr() != "127.0.0.1:"+strconv.Itoa(port) {
+		t.Errorf("expected the port reported by kubectl, got %s", tunnel.LocalAddr())
+	}
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "--context staging --namespace payments port-forward --address 127.0.0.1 service/api :8080"; strings.TrimSpace(string(args)) != want {
+		t.Errorf("expected kubectl %s, got %s", want, args)
+	}
+
+	if err := tunnel.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-tunnel.Done():
+	default:
+		t.Errorf("expected kubectl to be stopped")
+	}
+}
+
+func TestOpenKubectl_Errors(t *testing.T) {
+	tunneltest.StubKubectl(t, freePort(t))
+
+	_, err := OpenKubectl(context.Background(), domain.KubernetesTunnel{Target: "missing"}, 80)
+	if err == nil || !strings.Contains(err.Error(), `pods "missing" not found`) {
+		t.Errorf("expected the error of kubectl, got %v", err)
+	}
+
+	// the port never accepts connections
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if _, err := OpenKubectl(ctx, domain.KubernetesTunnel{Target: "api"}, 80); err == nil || !strings.Contains(err.Error(), "is not ready") {
+		t.Errorf("expected the forward not to be ready, got %v", err)
+	}
+}
+
+func Test_kubectlTarget(t *testing.T) {
+	tests := []struct {
+		cfg  domain.KubernetesTunnel
+		want string
+	}{
+		{domain.KubernetesTunnel{Target: "api-0"}, "pod/api-0"},
+		{domain.KubernetesTunnel{Target: "api-0", TargetType: domain.KubernetesTargetPod}, "pod/api-0"},
+		{domain.KubernetesTunnel{Target: "api", TargetType: domain.KubernetesTargetService}, "service/api"},
+		{domain.KubernetesTunnel{Target: "deploy/api", TargetType: domain.KubernetesTargetService}, "deploy/api"},
+	}
+
+	for _, tt := range tests {
+		if got := kubectlTarget(tt.cfg); got != tt.want {
+			t.Errorf("kubectlTarget(%+v) = %s, want %s", tt.cfg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/logger"
+)
+
+const (
+	// DefaultIdleTimeout is how long tunnels are kept open after their last request.
+	DefaultIdleTimeout = time.Minute
+
+	defaultHealthInterval = 10 * time.Second
+	defaultReconnectDelay = time.Second
+	maxReconnectDelay     = 30 * time.Second
+)
+
+const (
+	StatusOpening      = "opening"
+	StatusOpen         = "open"
+	StatusReconnecting = "reconnecting"
+)
+
+// Tunnel is an open local port forward.
+type Tunnel interface {
+	// LocalAddr returns the local address connections are forwarded from.
+	LocalAddr() string
+	// Done is closed once the tunnel stopped, either closed or failed.
+	Done() <-chan struct{}
+	// Check returns an error when the tunnel does not forward connections anymore.
+	Check(ctx context.Context) error
+	Close() error
+}
+
+// Info describes a tunnel of the manager.
+type Info struct {
+	ID   int
+	Name string
+	// LocalAddr is empty while the tunnel is not open
+	LocalAddr string
+	Status    string
+	// Error is the last error of the tunnel, like the one it is reconnecting after
+	Error string
+	// Users is the number of requests sent through the tunnel at the moment
+	Users int
+}
+
+// Manager shares the tunnels between requests. identical tunnels are opened once and kept open until they are idle
+// for their timeout, they are checked periodically and reconnected when they fail.
+type Manager struct {
+	mx        sync.Mutex
+	entries   map[any]*entry
+	nextID    int
+	listeners []func()
+
+	healthInterval time.Duration
+	reconnectDelay time.Duration
+}
+
+type entry struct {
+	id   int
+	key  any
+	name string
+	idle time.Duration
+	open func(ctx context.Context) (Tunnel, error)
+
+	// ctx is canceled once the entry is removed from the manager
+	ctx    context.Context
+	cancel context.CancelFunc
+	// opening is held while the tunnel is opened, so it is not opened twice
+	opening sync.Mutex
+
+	// the fields below are guarded by the mutex of the manager
+	tunnel       Tunnel
+	users        int
+	timer        *time.Timer
+	err          error
+	reconnecting bool
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		entries:        make(map[any]*entry),
+		healthInterval: defaultHealthInterval,
+		reconnectDelay: defaultReconnectDelay,
+	}
+}
+
+// AddChangeListener adds a listener which is called when tunnels are opened, closed or change their status.
+func (m *Manager) AddChangeListener(f func()) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.listeners = append(m.listeners, f)
+}
+
+// sshKey identifies ssh tunnels by the fields they are opened with, the target is part of it as the same server
+// can forward to different targets.
+type sshKey struct {
+	host, user, password, keyPath, knownHostsPath string
+	port, localPort                               int
+	target                                        string
+}
+
+// AcquireSSH returns the local address of the ssh tunnel to the target, release must be called once the request is done.
+func (m *Manager) AcquireSSH(ctx context.Context, cfg domain.SShTunnel, target string) (string, func(), error) {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSSHPort
+	}
+
+	name := fmt.Sprintf("ssh %s@%s -> %s", cfg.User, net.JoinHostPort(cfg.Host, strconv.Itoa(port)), target)
+	return m.acquire(ctx, sshKey{
+		host:           cfg.Host,
+		user:           cfg.User,
+		password:       cfg.Password,
+		keyPath:        cfg.KeyPath,
+		knownHostsPath: cfg.KnownHostsPath,
+		port:           port,
+		localPort:      cfg.LocalPort,
+		target:         target,
+	}, name, 0, func(ctx context.Context) (Tunnel, error) {
+		return OpenSSH(ctx, cfg, target)
+	})
+}
+
+// AcquireKubectl returns the local address of the kubectl port forward, release must be called once the request is done.
+func (m *Manager) AcquireKubectl(ctx context.Context, cfg domain.KubernetesTunnel) (string, func(), error) {
+	name := fmt.Sprintf("kubectl %s:%d", kubectlTarget(cfg), cfg.TargetPort)
+	if cfg.Namespace != "" {
+		name += " in " + cfg.Namespace
+	}
+	if cfg.Context != "" {
+		name += " of " + cfg.Context
+	}
+
+	return m.acquire(ctx, cfg, name, cfg.IdleTimeout, func(ctx context.Context) (Tunnel, error) {
+		return OpenKubectl(ctx, cfg, cfg.TargetPort)
+	})
+}
+
+func (m *Manager) acquire(ctx context.Context, key any, name string, idle time.Duration, open func(ctx context.Context) (Tunnel, error)) (string, func(), error) {
+	if idle <= 0 {
+		idle = DefaultIdleTimeout
+	}
+
+	m.mx.Lock()
+	e, ok := m.entries[key]
+	if !ok {
+		m.nextID++
+		e = &entry{id: m.nextID, key: key, name: name, idle: idle, open: open}
+		e.ctx, e.cancel = context.WithCancel(context.Background())
+		m.entries[key] = e
+	}
+
+	e.users++
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	m.mx.Unlock()
+	m.changed()
+
+	var once sync.Once
+	release := func() { once.Do(func() { m.release(e) }) }
+
+	t, err := m.connect(ctx, e)
+	if err != nil {
+		release()
+		return "", nil, err
+	}
+	return t.LocalAddr(), release, nil
+}
+
+// connect returns the tunnel of the entry, it is opened when there is none or it stopped.
+// requests to the same tunnel wait for it to open instead of opening it again.
+func (m *Manager) connect(ctx context.Context, e *entry) (Tunnel, error) {
+	e.opening.Lock()
+	defer e.opening.Unlock()
+
+	m.mx.Lock()
+	t := e.tunnel
+	m.mx.Unlock()
+
+	if t != nil && !stopped(t) {
+		return t, nil
+	}
+	return m.open(ctx, e)
+}
+
+// open opens the tunnel of the entry and starts checking it, the opening lock of the entry must be held.
+func (m *Manager) open(ctx context.Context, e *entry) (Tunnel, error) {
+	t, err := e.open(ctx)
+
+	m.mx.Lock()
+	if err != nil {
+		e.err = err
+		m.mx.Unlock()
+		m.changed()
+		return nil, err
+	}
+
+	if e.ctx.Err() != nil {
+		// the tunnel was closed while it was opening
+		m.mx.Unlock()
+		_ = t.Close()
+		return nil, fmt.Errorf("tunnel %s was closed", e.name)
+	}
+
+	e.tunnel, e.err, e.reconnecting = t, nil, false
+	m.mx.Unlock()
+
+	logger.Infof("tunnel %s is open on %s", e.name, t.LocalAddr())
+	go m.monitor(e, t)
+	m.changed()
+	return t, nil
+}
+
+// monitor checks the tunnel until it fails and reconnects it, it stops once the entry is removed.
+func (m *Manager) monitor(e *entry, t Tunnel) {
+	ticker := time.NewTicker(m.healthInterval)
+	defer ticker.Stop()
+
+	var err error
+	for err == nil {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-t.Done():
+			if err = t.Check(e.ctx); err == nil {
+				err = errors.New("tunnel stopped")
+			}
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(e.ctx, dialTimeout)
+			err = t.Check(ctx)
+			cancel()
+		}
+	}
+	_ = t.Close()
+
+	m.mx.Lock()
+	if e.tunnel != t || e.ctx.Err() != nil {
+		// the tunnel was replaced or closed meanwhile
+		m.mx.Unlock()
+		return
+	}
+	e.tunnel, e.err, e.reconnecting = nil, err, true
+	m.mx.Unlock()
+
+	logger.Errorf("tunnel %s failed: %v, reconnecting", e.name, err)
+	m.changed()
+	m.reconnect(e)
+}
+
+// reconnect opens the tunnel of the entry again, the delay between the attempts doubles up to maxReconnectDelay.
+func (m *Manager) reconnect(e *entry) {
+	delay := m.reconnectDelay
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		e.opening.Lock()
+		m.mx.Lock()
+		t := e.tunnel
+		m.mx.Unlock()
+
+		// a request opened the tunnel meanwhile
+		if t != nil {
+			e.opening.Unlock()
+			return
+		}
+
+		_, err := m.open(e.ctx, e)
+		e.opening.Unlock()
+		if err == nil || e.ctx.Err() != nil {
+			return
+		}
+
+		logger.Errorf("failed to reconnect tunnel %s: %v", e.name, err)
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func (m *Manager) release(e *entry) {
+	m.mx.Lock()
+	e.users--
+	if e.users > 0 || m.entries[e.key] != e {
+		m.mx.Unlock()
+		m.changed()
+		return
+	}
+
+	// tunnels which failed to open are not kept
+	if e.tunnel == nil && !e.reconnecting {
+		m.removeLocked(e)
+		m.mx.Unlock()
+		m.changed()
+		return
+	}
+
+	e.timer = time.AfterFunc(e.idle, func() {
+		m.mx.Lock()
+		// the tunnel was used again or closed meanwhile
+		if e.users > 0 || m.entries[e.key] != e {
+			m.mx.Unlock()
+			return
+		}
+		t := m.removeLocked(e)
+		m.mx.Unlock()
+
+		closeTunnel(e, t)
+		m.changed()
+	})
+	m.mx.Unlock()
+	m.changed()
+}
+
+// removeLocked removes the entry and returns its tunnel to be closed, the mutex of the manager must be held.
+func (m *Manager) removeLocked(e *entry) Tunnel {
+	delete(m.entries, e.key)
+	e.cancel()
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+
+	t := e.tunnel
+	e.tunnel = nil
+	return t
+}
+
+// List returns the tunnels of the manager in the order they were opened.
+func (m *Manager) List() []Info {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	out := make([]Info, 0, len(m.entries))
+	for _, e := range m.entries {
+		info := Info{ID: e.id, Name: e.name, Status: StatusOpening, Users: e.users}
+		switch {
+		case e.tunnel != nil:
+			info.Status = StatusOpen
+			info.LocalAddr = e.tunnel.LocalAddr()
+		case e.reconnecting:
+			info.Status = StatusReconnecting
+		}
+
+		if e.err != nil {
+			info.Error = e.err.Error()
+		}
+		out = append(out, info)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Close closes the tunnel with the given id, requests which are sent through it fail.
+func (m *Manager) Close(id int) error {
+	m.mx.Lock()
+	var found *entry
+	for _, e := range m.entries {
+		if e.id == id {
+			found = e
+			break
+		}
+	}
+
+	if found == nil {
+		m.mx.Unlock()
+		return fmt.Errorf("tunnel %d not found", id)
+	}
+
+	t := m.removeLocked(found)
+	m.mx.Unlock()
+
+	err := closeTunnel(found, t)
+	m.changed()
+	return err
+}
+
+// CloseAll closes all the tunnels, like when the app exits or the workspace changes.
+func (m *Manager) CloseAll() {
+	m.mx.Lock()
+	entries := make(map[*entry]Tunnel, len(m.entries))
+	for _, e := range m.entries {
+		entries[e] = m.removeLocked(e)
+	}
+	m.mx.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for e, t := range entries {
+		wg.Add(1)
+		go func(e *entry, t Tunnel) {
+			defer wg.Done()
+			_ = closeTunnel(e, t)
+		}(e, t)
+	}
+	wg.Wait()
+	m.changed()
+}
+
+func (m *Manager) changed() {
+	m.mx.Lock()
+	listeners := m.listeners
+	m.mx.Unlock()
+
+	for _, f := range listeners {
+		f()
+	}
+}
+
+func closeTunnel(e *entry, t Tunnel) error {
+	if t == nil {
+		return nil
+	}
+
+	logger.Infof("tunnel %s is closed", e.name)
+	return t.Close()
+}
+
+func stopped(t Tunnel) bool {
+	select {
+	case <-t.Done():
+		return true
+	default:
+		return false
+	}
+}
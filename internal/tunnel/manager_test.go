@@ -0,0 +1,255 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeTunnel is a tunnel whose health is set by the test.
+type fakeTunnel struct {
+	addr string
+	done chan struct{}
+
+	mx        sync.Mutex
+	unhealthy bool
+	closed    bool
+}
+
+func newFakeTunnel(addr string) *fakeTunnel {
+	return &fakeTunnel{addr: addr, done: make(chan struct{})}
+}
+
+func (f *fakeTunnel) LocalAddr() string { return f.addr }
+
+func (f *fakeTunnel) Done() <-chan struct{} { return f.done }
+
+func (f *fakeTunnel) Check(ctx context.Context) error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	if f.unhealthy {
+		return errors.New("not answering")
+	}
+	return nil
+}
+
+func (f *fakeTunnel) Close() error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	if !f.closed {
+		f.closed = true
+		close(f.done)
+	}
+	return nil
+}
+
+func (f *fakeTunnel) setUnhealthy() {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.unhealthy = true
+}
+
+func (f *fakeTunnel) isClosed() bool {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	return f.closed
+}
+
+// fakeOpener opens fake tunnels on consecutive ports and records them.
+type fakeOpener struct {
+	mx      sync.Mutex
+	tunnels []*fakeTunnel
+	err     error
+}
+
+func (o *fakeOpener) open(ctx context.Context) (Tunnel, error) {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	t := newFakeTunnel("127.0.0.1:" + strconv.Itoa(9000+len(o.tunnels)))
+	o.tunnels = append(o.tunnels, t)
+	return t, nil
+}
+
+func (o *fakeOpener) opened() []*fakeTunnel {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+	return append([]*fakeTunnel(nil), o.tunnels...)
+}
+
+func (o *fakeOpener) setError(err error) {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+	o.err = err
+}
+
+func newTestManager() *Manager {
+	m := NewManager()
+	m.healthInterval = 20 * time.Millisecond
+	m.reconnectDelay = 20 * time.Millisecond
+	return m
+}
+
+// waitFor polls the condition for up to two seconds.
+func waitFor(t *testing.T, what string, f func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager_Reuse(t *testing.T) {
+	m := newTestManager()
+	defer m.CloseAll()
+
+	var changes atomic.Int32
+	m.AddChangeListener(func() { changes.Add(1) })
+
+	opener := &fakeOpener{}
+	addr1, release1, err := m.acquire(context.Background(), "api", "api", 100*time.Millisecond, opener.open)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	addr2, release2, err := m.acquire(context.Background(), "api", "api", 100*time.Millisecond, opener.open)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if addr1 != addr2 || len(opener.opened()) != 1 {
+		t.Errorf("expected the tunnel to be reused, got %s and %s", addr1, addr2)
+	}
+
+	list := m.List()
+	if len(list) != 1 || list[0].Status != StatusOpen || list[0].LocalAddr != addr1 || list[0].Users != 2 {
+		t.Errorf("expected one open tunnel with two users, got %+v", list)
+	}
+
+	if changes.Load() == 0 {
+		t.Errorf("expected the listeners to be called")
+	}
+
+	// the tunnel is kept open while it is used
+	release1()
+	release1()
+	time.Sleep(200 * time.Millisecond)
+	if opener.opened()[0].isClosed() {
+		t.Errorf("expected the tunnel to be open while it is used")
+	}
+
+	release2()
+	waitFor(t, "the idle tunnel to be closed", func() bool {
+		return opener.opened()[0].isClosed() && len(m.List()) == 0
+	})
+}
+
+func TestManager_Reconnect(t *testing.T) {
+	m := newTestManager()
+	defer m.CloseAll()
+
+	opener := &fakeOpener{}
+	_, release, err := m.acquire(context.Background(), "api", "api", time.Minute, opener.open)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+
+	// failing health checks close the tunnel and open it again
+	opener.setError(errors.New("connection refused"))
+	opener.opened()[0].setUnhealthy()
+
+	waitFor(t, "the tunnel to reconnect", func() bool {
+		list := m.List()
+		return len(list) == 1 && list[0].Status == StatusReconnecting && list[0].Error == "not answering"
+	})
+
+	if !opener.opened()[0].isClosed() {
+		t.Errorf("expected the failed tunnel to be closed")
+	}
+
+	opener.setError(nil)
+	waitFor(t, "the tunnel to be open again", func() bool {
+		list := m.List()
+		return len(list) == 1 && list[0].Status == StatusOpen && list[0].Error == ""
+	})
+
+	// stopped tunnels are reconnected too, like when kubectl exits
+	_ = opener.opened()[1].Close()
+	waitFor(t, "the stopped tunnel to be opened again", func() bool {
+		return len(opener.opened()) == 3 && m.List()[0].Status == StatusOpen
+	})
+
+	addr, release, err := m.acquire(context.Background(), "api", "api", time.Minute, opener.open)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	if addr != opener.opened()[2].LocalAddr() {
+		t.Errorf("expected the reconnected tunnel, got %s", addr)
+	}
+}
+
+func TestManager_Close(t *testing.T) {
+	m := newTestManager()
+
+	opener := &fakeOpener{}
+	_, release, err := m.acquire(context.Background(), "api", "api", time.Minute, opener.open)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, _, err := m.acquire(context.Background(), "db", "db", time.Minute, opener.open); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list := m.List()
+	if len(list) != 2 || list[0].Name != "api" || list[1].Name != "db" {
+		t.Fatalf("expected the tunnels in the order they were opened, got %+v", list)
+	}
+
+	if err := m.Close(list[0].ID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if !opener.opened()[0].isClosed() {
+		t.Errorf("expected the tunnel to be closed")
+	}
+
+	// releasing a closed tunnel does not affect the manager
+	release()
+	if list := m.List(); len(list) != 1 || list[0].Name != "db" {
+		t.Errorf("expected only the other tunnel to be left, got %+v", list)
+	}
+
+	if err := m.Close(list[0].ID); err == nil {
+		t.Errorf("expected an error for a closed tunnel")
+	}
+
+	// tunnels in use are closed too
+	m.CloseAll()
+	if !opener.opened()[1].isClosed() || len(m.List()) != 0 {
+		t.Errorf("expected all the tunnels to be closed")
+	}
+
+	// failing tunnels are not kept
+	opener.setError(errors.New("connection refused"))
+	if _, _, err := m.acquire(context.Background(), "api", "api", time.Minute, opener.open); err == nil {
+		t.Errorf("expected the error of the tunnel")
+	}
+
+	if list := m.List(); len(list) != 0 {
+		t.Errorf("expected no tunnels, got %+v", list)
+	}
+}
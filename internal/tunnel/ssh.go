@@ -0,0 +1,276 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultSSHPort = 22
+	dialTimeout    = 10 * time.Second
+)
+
+// SSH is a local port forward over ssh, connections to its local address are forwarded to the target through the server.
+type SSH struct {
+	client   *ssh.Client
+	listener net.Listener
+	target   string
+	done     chan struct{}
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// OpenSSH connects to the server of the config and listens on its local port, target is the address the server
+// connects to. the server must be in the known_hosts file and is authenticated with the key or the password.
+func OpenSSH(ctx context.Context, cfg domain.SShTunnel, target string) (*SSH, error) {
+	clientConfig, err := sshClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSSHPort
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh server %s: %w", addr, err)
+	}
+
+	// the handshake is not aware of the context, so the connection is closed when it is canceled
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, addr, clientConfig)
+	if !stop() {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to connect to ssh server %s: %w", addr, err)
+	}
+	client := ssh.NewClient(sshConn, channels, requests)
+
+	// make sure the server can reach the target, so the request does not fail with a closed connection
+	targetConn, err := client.Dial("tcp", target)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("ssh server %s failed to connect to %s: %w", addr, target, err)
+	}
+	_ = targetConn.Close()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(cfg.LocalPort)))
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to listen on local port %d: %w", cfg.LocalPort, err)
+	}
+
+	t := &SSH{
+		client:   client,
+		listener: listener,
+		target:   target,
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		// wait returns once the connection to the server is closed or lost
+		_ = client.Wait()
+		close(t.done)
+	}()
+
+	t.wg.Add(1)
+	go t.serve()
+	return t, nil
+}
+
+// LocalAddr returns the local address connections are forwarded from, like 127.0.0.1:8080.
+func (t *SSH) LocalAddr() string {
+	return t.listener.Addr().String()
+}
+
+// Done is closed once the connection to the server is closed.
+func (t *SSH) Done() <-chan struct{} {
+	return t.done
+}
+
+// Check sends a keepalive request to make sure the server still answers.
+func (t *SSH) Check(ctx context.Context) error {
+	errs := make(chan error, 1)
+	go func() {
+		// servers which do not know the request still answer it, so only the error matters
+		_, _, err := t.client.SendRequest("keepalive@openssh.com", true, nil)
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			return fmt.Errorf("ssh server did not answer: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("ssh server did not answer: %w", ctx.Err())
+	}
+}
+
+// Close stops listening and closes the connection to the server along with the forwarded connections.
+func (t *SSH) Close() error {
+	t.closeOnce.Do(func() {
+		t.closeErr = errors.Join(t.listener.Close(), t.client.Close())
+		t.wg.Wait()
+	})
+	return t.closeErr
+}
+
+func (t *SSH) serve() {
+	defer t.wg.Done()
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.forward(conn)
+		}()
+	}
+}
+
+func (t *SSH) forward(local net.Conn) {
+	defer local.Close()
+
+	remote, err := t.client.Dial("tcp", t.target)
+	if err != nil {
+		fmt.Println("failed to forward connection over ssh", err)
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+
+	// once one side is done the other one is closed by the deferred calls
+	<-done
+}
+
+func sshClientConfig(cfg domain.SShTunnel) (*ssh.ClientConfig, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("ssh host is not set")
+	}
+
+	if cfg.User == "" {
+		return nil, errors.New("ssh user is not set")
+	}
+
+	hostKeyCallback, err := knownHostsCallback(cfg.KnownHostsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth []ssh.AuthMethod
+	if cfg.KeyPath != "" {
+		signer, err := loadKey(cfg.KeyPath, cfg.Password)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+
+	if len(auth) == 0 {
+		return nil, errors.New("set the password or the private key of the ssh user")
+	}
+
+	return &ssh.ClientConfig{
+		User:            cfg.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}, nil
+}
+
+// loadKey reads the private key, the password is used as its passphrase when the key is encrypted.
+func loadKey(path, password string) (ssh.Signer, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ssh key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) && password != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(password))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh key %s: %w", path, err)
+	}
+	return signer, nil
+}
+
+// knownHostsCallback checks the host keys against the known_hosts file, servers which are not in it are rejected.
+func knownHostsCallback(path string) (ssh.HostKeyCallback, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts: %w", err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("host %s is not in %s, connect to it once with ssh to add it", hostname, path)
+			}
+			return fmt.Errorf("host key of %s does not match the one in %s", hostname, path)
+		}
+		return err
+	}, nil
+}
+
+func expandHome(path string) string {
+	if len(path) < 2 || path[:2] != "~/" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -0,0 +1,251 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSSHServer is an ssh server which accepts the password "secret" and the given key and forwards direct-tcpip channels.
+type testSSHServer struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+	wg       sync.WaitGroup
+}
+
+func newTestSSHServer(t *testing.T, userKey ssh.PublicKey) *testSSHServer {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hostKey, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "jane" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("wrong password")
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if userKey != nil && string(key.Marshal()) == string(userKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := &testSSHServer{listener: listener, hostKey: hostKey}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn, config)
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *testSSHServer) handle(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for ch := range channels {
+		if ch.ChannelType() != "direct-tcpip" {
+			_ = ch.Reject(ssh.UnknownChannelType, "not supported")
+			continue
+		}
+
+		// the payload is the target host and port followed by the originator
+		payload := ch.ExtraData()
+		hostLen := binary.BigEndian.Uint32(payload)
+		host := string(payload[4 : 4+hostLen])
+		port := binary.BigEndian.Uint32(payload[4+hostLen:])
+
+		target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+		if err != nil {
+			_ = ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, reqs, err := ch.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(reqs)
+
+		go func() {
+			defer channel.Close()
+			defer target.Close()
+			go func() { _, _ = io.Copy(target, channel) }()
+			_, _ = io.Copy(channel, target)
+		}()
+	}
+}
+
+func (s *testSSHServer) hostPort() (string, int) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// writeKnownHosts writes a known_hosts file with the host key of the server.
+func (s *testSSHServer) writeKnownHosts(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(s.listener.Addr().String())}, s.hostKey.PublicKey())
+	if err := os.WriteFile(path, []byte(line+"\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return path
+}
+
+func TestOpenSSH(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	userKey, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("through the tunnel"))
+	}))
+	defer srv.Close()
+	target := strings.TrimPrefix(srv.URL, "http://")
+
+	server := newTestSSHServer(t, userKey)
+	host, port := server.hostPort()
+	knownHosts := server.writeKnownHosts(t)
+
+	tests := []struct {
+		name string
+		cfg  domain.SShTunnel
+	}{
+		{"password", domain.SShTunnel{Host: host, Port: port, User: "jane", Password: "secret", KnownHostsPath: knownHosts}},
+		{"key", domain.SShTunnel{Host: host, Port: port, User: "jane", KeyPath: keyPath, KnownHostsPath: knownHosts}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tunnel, err := OpenSSH(context.Background(), tt.cfg, target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer tunnel.Close()
+
+			res, err := http.Get("http://" + tunnel.LocalAddr())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body, _ := io.ReadAll(res.Body)
+			_ = res.Body.Close()
+
+			if string(body) != "through the tunnel" {
+				t.Errorf("expected the response of the target, got %q", body)
+			}
+
+			if err := tunnel.Close(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if _, err := net.Dial("tcp", tunnel.LocalAddr()); err == nil {
+				t.Errorf("expected the local port to be closed")
+			}
+		})
+	}
+}
+
+func TestOpenSSH_Errors(t *testing.T) {
+	server := newTestSSHServer(t, nil)
+	host, port := server.hostPort()
+	knownHosts := server.writeKnownHosts(t)
+
+	unknownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(unknownHosts, nil, 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	_ = closed.Close()
+
+	tests := []struct {
+		name   string
+		cfg    domain.SShTunnel
+		target string
+		want   string
+	}{
+		{"unknown host", domain.SShTunnel{Host: host, Port: port, User: "jane", Password: "secret", KnownHostsPath: unknownHosts}, "127.0.0.1:80", "is not in"},
+		{"wrong password", domain.SShTunnel{Host: host, Port: port, User: "jane", Password: "wrong", KnownHostsPath: knownHosts}, "127.0.0.1:80", "unable to authenticate"},
+		{"unreachable target", domain.SShTunnel{Host: host, Port: port, User: "jane", Password: "secret", KnownHostsPath: knownHosts}, closedAddr, "failed to connect to " + closedAddr},
+		{"no auth", domain.SShTunnel{Host: host, Port: port, User: "jane", KnownHostsPath: knownHosts}, "127.0.0.1:80", "set the password or the private key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OpenSSH(context.Background(), tt.cfg, tt.target)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Package tunneltest provides helpers to test the tunnels without a cluster.
+package tunneltest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// StubKubectl puts a kubectl on PATH which appends its arguments to the returned file and reports forwarding
+// from the given port, it fails for targets named missing.
+func StubKubectl(t *testing.T, port int) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub kubectl is a shell script")
+	}
+
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := `#!/bin/sh
+echo "$@" >> "` + argsFile + `"
+case "$*" in
+  *service/missing*) echo 'error: services "missing" not found' >&2; exit 1 ;;
+  *missing*) echo 'error: pods "missing" not found' >&2; exit 1 ;;
+esac
+echo "Forwarding from 127.0.0.1:` + strconv.Itoa(port) + ` -> 80"
+echo "Forwarding from [::1]:` + strconv.Itoa(port) + ` -> 80"
+exec sleep 60
+`
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0700); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
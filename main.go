@@ -1,15 +1,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 
 	_ "net/http/pprof"
 
 	"gioui.org/app"
 	"gioui.org/unit"
+	"github.com/chapar-rest/chapar/internal/cli"
 	mainApp "github.com/chapar-rest/chapar/ui/app"
 )
 
@@ -18,6 +21,14 @@ var (
 )
 
 func main() {
+	// chapar run runs collections from the command line, without starting the ui
+	if len(os.Args) > 1 && os.Args[1] == "run" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := cli.Run(ctx, os.Args[2:], os.Stdout, os.Stderr)
+		stop()
+		os.Exit(code)
+	}
+
 	flag.Parse()
 
 	if *enablePprof {
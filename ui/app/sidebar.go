@@ -40,12 +40,14 @@ func NewSidebar(theme *chapartheme.Theme) *Sidebar {
 		Buttons: []*SideBarButton{
 			{Icon: widgets.SwapHoriz, Text: "Requests"},
 			{Icon: widgets.MenuIcon, Text: "Envs"},
+			{Icon: widgets.GlobalsIcon, Text: "Globals"},
 			{Icon: widgets.WorkspacesIcon, Text: "Workspaces"},
-			// {Icon: widgets.FileFolderIcon, Text: "Proto"},
-			// {Icon: widgets.TunnelIcon, Text: "Tunnels"},
-			// {Icon: widgets.ConsoleIcon, Text: "Console"},
+			{Icon: widgets.CookieIcon, Text: "Cookies"},
+			{Icon: widgets.FileFolderIcon, Text: "Proto"},
+			{Icon: widgets.ConsoleIcon, Text: "Console"},
+			{Icon: widgets.TunnelIcon, Text: "Tunnels"},
 			// {Icon: widgets.LogsIcon, Text: "Logs"},
-			// {Icon: widgets.SettingsIcon, Text: "Settings"},
+			{Icon: widgets.SettingsIcon, Text: "Settings"},
 		},
 		list: &widget.List{
 			List: layout.List{
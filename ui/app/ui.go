@@ -16,6 +16,7 @@ import (
 	"gioui.org/text"
 	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/grpc"
 	"github.com/chapar-rest/chapar/internal/notify"
 	"github.com/chapar-rest/chapar/internal/repository"
 	"github.com/chapar-rest/chapar/internal/rest"
@@ -24,8 +25,13 @@ import (
 	"github.com/chapar-rest/chapar/ui/explorer"
 	"github.com/chapar-rest/chapar/ui/fonts"
 	"github.com/chapar-rest/chapar/ui/pages/console"
+	"github.com/chapar-rest/chapar/ui/pages/cookies"
 	"github.com/chapar-rest/chapar/ui/pages/environments"
+	"github.com/chapar-rest/chapar/ui/pages/globals"
+	"github.com/chapar-rest/chapar/ui/pages/protofiles"
 	"github.com/chapar-rest/chapar/ui/pages/requests"
+	"github.com/chapar-rest/chapar/ui/pages/settings"
+	"github.com/chapar-rest/chapar/ui/pages/tunnels"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
@@ -40,16 +46,32 @@ type UI struct {
 	notification *widgets.Notification
 
 	environmentsView *environments.View
+	globalsView      *globals.View
 	requestsView     *requests.View
 	workspacesView   *workspaces.View
+	cookiesView      *cookies.View
+	protoFilesView   *protofiles.View
+	tunnelsView      *tunnels.View
+	settingsView     *settings.View
 
 	environmentsController *environments.Controller
+	globalsController      *globals.Controller
 	requestsController     *requests.Controller
 	workspacesController   *workspaces.Controller
+	cookiesController      *cookies.Controller
+	protoFilesController   *protofiles.Controller
+	tunnelsController      *tunnels.Controller
+	settingsController     *settings.Controller
 
 	environmentsState *state.Environments
 	requestsState     *state.Requests
 	workspacesState   *state.Workspaces
+	cookiesState      *state.Cookies
+	protoFilesState   *state.ProtoFiles
+	historyState      *state.ResponseHistory
+
+	restService *rest.Service
+	grpcService *grpc.Service
 
 	repo repository.Repository
 
@@ -84,7 +106,12 @@ func New(w *app.Window) (*UI, error) {
 	u.environmentsState = state.NewEnvironments(repo)
 	u.requestsState = state.NewRequests(repo)
 
-	restService := rest.New(u.requestsState, u.environmentsState)
+	u.cookiesState = state.NewCookies(repo)
+	u.protoFilesState = state.NewProtoFiles(repo)
+	u.historyState = state.NewResponseHistory(repo)
+
+	u.restService = rest.New(u.requestsState, u.environmentsState, u.cookiesState)
+	u.grpcService = grpc.New(u.requestsState, u.environmentsState, u.protoFilesState)
 	explorerController := explorer.NewExplorer(w)
 
 	theme := material.NewTheme()
@@ -122,8 +149,23 @@ func New(w *app.Window) (*UI, error) {
 		u.environmentsState.SetActiveEnvironment(env)
 	}
 
+	u.globalsView = globals.NewView(w)
+	u.globalsController = globals.NewController(u.globalsView, u.environmentsState)
+
+	u.cookiesView = cookies.NewView()
+	u.cookiesController = cookies.NewController(u.cookiesView, u.cookiesState, u.environmentsState)
+
+	u.protoFilesView = protofiles.NewView()
+	u.protoFilesController = protofiles.NewController(u.protoFilesView, u.protoFilesState, explorerController)
+
+	u.tunnelsView = tunnels.NewView(w)
+	u.tunnelsController = tunnels.NewController(u.tunnelsView, u.restService.Tunnels())
+
+	u.settingsView = settings.NewView(u.Theme)
+	u.settingsController = settings.NewController(u.settingsView, repo, u.restService)
+
 	u.requestsView = requests.NewView(w, u.Theme)
-	u.requestsController = requests.NewController(u.requestsView, repo, u.requestsState, u.environmentsState, explorerController, restService)
+	u.requestsController = requests.NewController(u.requestsView, repo, u.requestsState, u.environmentsState, u.historyState, explorerController, u.restService, u.grpcService)
 
 	u.header.OnSelectedWorkspaceChanged = func(ws *domain.Workspace) {
 		fmt.Println("workspace changed: ", ws.MetaData.Name)
@@ -132,6 +174,8 @@ func New(w *app.Window) (*UI, error) {
 			return
 		}
 		u.workspacesState.SetActiveWorkspace(ws)
+		// tunnels are opened with the settings of the workspace, so they are not kept for the next one
+		u.restService.Tunnels().CloseAll()
 
 		if err := u.load(); err != nil {
 			fmt.Println("failed to load data: ", err)
@@ -180,6 +224,15 @@ func (u *UI) load() error {
 	}
 
 	u.header.SetTheme(preferences.Spec.DarkMode)
+	u.restService.SetHTTPClientSettings(preferences.Spec.HTTPClient)
+	if err := u.settingsController.LoadData(); err != nil {
+		return err
+	}
+
+	// cookies belong to the workspace, so the jars of the previous one should not be reused
+	u.cookiesState.ClearCache()
+	u.protoFilesState.ClearCache()
+	u.historyState.ClearCache()
 
 	if err := u.environmentsController.LoadData(); err != nil {
 		return err
@@ -197,6 +250,18 @@ func (u *UI) load() error {
 		u.header.SetSelectedWorkspace(u.workspacesState.GetActiveWorkspace())
 	}
 
+	if err := u.globalsController.LoadData(); err != nil {
+		return err
+	}
+
+	if err := u.cookiesController.LoadData(); err != nil {
+		return err
+	}
+
+	if err := u.protoFilesController.LoadData(); err != nil {
+		return err
+	}
+
 	return u.requestsController.LoadData()
 }
 
@@ -215,6 +280,9 @@ func (u *UI) Run() error {
 			e.Frame(gtx.Ops)
 		// this is sent when the application is closed.
 		case app.DestroyEvent:
+			// tunnels are not left running, like the kubectl processes
+			u.restService.Tunnels().CloseAll()
+			u.requestsController.RemoveResponseFiles()
 			return e.Err
 		}
 	}
@@ -254,9 +322,19 @@ func (u *UI) Layout(gtx layout.Context, windowWidth int) layout.Dimensions {
 							case 1:
 								return u.environmentsView.Layout(gtx, u.Theme)
 							case 2:
+								return u.globalsView.Layout(gtx, u.Theme)
+							case 3:
 								return u.workspacesView.Layout(gtx, u.Theme)
-								// case 4:
-								//	return u.consolePage.Layout(gtx, u.Theme)
+							case 4:
+								return u.cookiesView.Layout(gtx, u.Theme)
+							case 5:
+								return u.protoFilesView.Layout(gtx, u.Theme)
+							case 6:
+								return u.consolePage.Layout(gtx, u.Theme)
+							case 7:
+								return u.tunnelsView.Layout(gtx, u.Theme)
+							case 8:
+								return u.settingsView.Layout(gtx, u.Theme)
 							}
 							return layout.Dimensions{}
 						}),
@@ -15,6 +15,7 @@ var (
 	LightYellow = color.NRGBA{R: 0xff, G: 0xe0, B: 0x73, A: 0xff}
 	LightBlue   = color.NRGBA{R: 0x45, G: 0x89, B: 0xf5, A: 0xff}
 	LightPurple = color.NRGBA{R: 0x9c, G: 0x27, B: 0xb0, A: 0xff}
+	LightPink   = color.NRGBA{R: 0xe1, G: 0x00, B: 0x98, A: 0xff}
 )
 
 type Theme struct {
@@ -146,6 +147,12 @@ func GetRequestPrefixColor(method string) color.NRGBA {
 		return color.NRGBA{R: 0x00, G: 0x80, B: 0x80, A: 0xff}
 	case "HEAD":
 		return color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	case "GQL":
+		return LightPink
+	case "WS":
+		return LightYellow
+	case "GRPC":
+		return LightBlue
 	default:
 		return color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
 	}
@@ -1,6 +1,7 @@
 package explorer
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -101,3 +102,38 @@ func (e *Explorer) ChoseFiles(onResult func(r []Result), extensions ...string) {
 
 	}(onResult)
 }
+
+// SaveFile asks where to save a file with the suggested name and calls write with it, the result holds the path of the file if possible.
+// onResult is not called when the user cancels the dialog.
+func (e *Explorer) SaveFile(name string, write func(w io.Writer) error, onResult func(r Result)) {
+	go func() {
+		defer e.w.Invalidate()
+
+		file, err := e.expl.CreateFile(name)
+		if errors.Is(err, explorer.ErrUserDecline) {
+			return
+		}
+		if err != nil {
+			onResult(Result{Error: fmt.Errorf("failed creating file: %w", err)})
+			return
+		}
+
+		filePath := ""
+		if f, ok := file.(*os.File); ok {
+			filePath = f.Name()
+		}
+
+		if err := write(file); err != nil {
+			file.Close()
+			onResult(Result{Error: fmt.Errorf("failed writing file: %w", err), FilePath: filePath})
+			return
+		}
+
+		if err := file.Close(); err != nil {
+			onResult(Result{Error: fmt.Errorf("failed closing file: %w", err), FilePath: filePath})
+			return
+		}
+
+		onResult(Result{FilePath: filePath})
+	}()
+}
@@ -2,6 +2,7 @@ package console
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"gioui.org/layout"
@@ -9,10 +10,15 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/logger"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 )
 
+// maxLogs is the number of logs the console keeps, the oldest ones are dropped.
+const maxLogs = 1000
+
 type Console struct {
+	mx   sync.Mutex
 	logs []domain.Log
 
 	selectables []*widget.Selectable
@@ -31,17 +37,17 @@ func New() *Console {
 		clearButton: &widget.Clickable{},
 	}
 
-	// bus.Subscribe(state.LogSubmitted, c.handleIncomingLog)
+	logger.Subscribe(c.handleIncomingLog)
 	return c
 }
 
-func (c *Console) handleIncomingLog(log any) {
-	if log == nil {
-		return
-	}
+func (c *Console) handleIncomingLog(log domain.Log) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
 
-	if l, ok := log.(domain.Log); ok {
-		c.logs = append(c.logs, l)
+	c.logs = append(c.logs, log)
+	if len(c.logs) > maxLogs {
+		c.logs = c.logs[len(c.logs)-maxLogs:]
 	}
 }
 
@@ -80,7 +86,9 @@ func (c *Console) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Di
 				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceStart}.Layout(gtx,
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 						if c.clearButton.Clicked(gtx) {
+							c.mx.Lock()
 							c.logs = make([]domain.Log, 0)
+							c.mx.Unlock()
 						}
 						return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 							return material.Button(theme.Material(), c.clearButton, "Clear").Layout(gtx)
@@ -95,8 +103,12 @@ func (c *Console) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Di
 					CornerRadius: unit.Dp(4),
 				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 					return layout.UniformInset(unit.Dp(5)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-						return material.List(theme.Material(), c.list).Layout(gtx, len(c.logs), func(gtx layout.Context, i int) layout.Dimensions {
-							return c.logLayout(gtx, theme, &c.logs[i])
+						c.mx.Lock()
+						logs := c.logs
+						c.mx.Unlock()
+
+						return material.List(theme.Material(), c.list).Layout(gtx, len(logs), func(gtx layout.Context, i int) layout.Dimensions {
+							return c.logLayout(gtx, theme, &logs[i])
 						})
 					})
 				})
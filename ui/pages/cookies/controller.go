@@ -0,0 +1,102 @@
+package cookies
+
+import (
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+type Controller struct {
+	view *View
+
+	state    *state.Cookies
+	envState *state.Environments
+}
+
+func NewController(view *View, cookiesState *state.Cookies, envState *state.Environments) *Controller {
+	c := &Controller{
+		view:     view,
+		state:    cookiesState,
+		envState: envState,
+	}
+
+	view.SetOnUpdate(c.onUpdate)
+	view.SetOnDelete(c.onDelete)
+	view.SetOnClearDomain(c.onClearDomain)
+
+	envState.AddActiveEnvironmentChangeListener(func(env *domain.Environment) {
+		if err := c.LoadData(); err != nil {
+			fmt.Println("failed to load cookies", err)
+		}
+	})
+
+	cookiesState.AddCookieJarChangeListener(func(jar *domain.CookieJar, source state.Source, action state.Action) {
+		if source == state.SourceController || jar.MetaData.Name != c.jarName() {
+			return
+		}
+
+		if err := c.LoadData(); err != nil {
+			fmt.Println("failed to load cookies", err)
+		}
+	})
+
+	return c
+}
+
+// jarName returns the name of the jar of the active environment.
+func (c *Controller) jarName() string {
+	if env := c.envState.GetActiveEnvironment(); env != nil {
+		return domain.CookieJarName(env.MetaData.ID)
+	}
+	return domain.DefaultCookieJarName
+}
+
+func (c *Controller) LoadData() error {
+	title := ""
+	if env := c.envState.GetActiveEnvironment(); env != nil {
+		title = env.MetaData.Name
+	}
+
+	cookies, err := c.state.GetCookies(c.jarName())
+	if err != nil {
+		return err
+	}
+
+	c.view.SetJarTitle(title)
+	c.view.SetCookies(cookies)
+	return nil
+}
+
+func (c *Controller) onUpdate(old, updated domain.Cookie) {
+	if err := c.state.UpdateCookie(c.jarName(), old, updated, state.SourceController); err != nil {
+		fmt.Println("failed to update cookie", err)
+		return
+	}
+
+	c.reload()
+}
+
+func (c *Controller) onDelete(cookie domain.Cookie) {
+	if err := c.state.RemoveCookie(c.jarName(), cookie, state.SourceController); err != nil {
+		fmt.Println("failed to remove cookie", err)
+		return
+	}
+
+	c.reload()
+}
+
+func (c *Controller) onClearDomain(domainName string) {
+	if err := c.state.ClearDomain(c.jarName(), domainName, state.SourceController); err != nil {
+		fmt.Println("failed to clear cookies", err)
+		return
+	}
+
+	c.reload()
+}
+
+func (c *Controller) reload() {
+	if err := c.LoadData(); err != nil {
+		fmt.Println("failed to load cookies", err)
+	}
+}
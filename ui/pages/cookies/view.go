@@ -0,0 +1,275 @@
+package cookies
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const expiresLayout = "2006-01-02 15:04:05"
+
+type View struct {
+	clearAllButton widget.Clickable
+	searchBox      *widgets.TextField
+
+	mx         *sync.Mutex
+	filterText string
+	jarTitle   string
+
+	rows []*row
+	list *widget.List
+
+	onUpdate      func(old, updated domain.Cookie)
+	onDelete      func(cookie domain.Cookie)
+	onClearDomain func(domain string)
+}
+
+// row is either the header of a domain or one of its cookies.
+type row struct {
+	domain string
+	cookie *domain.Cookie
+
+	clearButton  widget.Clickable
+	deleteButton widget.Clickable
+
+	Value *widgets.EditableLabel
+}
+
+func NewView() *View {
+	search := widgets.NewTextField("", "Search...")
+	search.SetIcon(widgets.SearchIcon, widgets.IconPositionEnd)
+	v := &View{
+		mx:        &sync.Mutex{},
+		searchBox: search,
+		list: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+
+	v.searchBox.SetOnTextChange(func(text string) {
+		v.mx.Lock()
+		defer v.mx.Unlock()
+		v.filterText = text
+	})
+
+	return v
+}
+
+func (v *View) SetOnUpdate(f func(old, updated domain.Cookie)) {
+	v.onUpdate = f
+}
+
+func (v *View) SetOnDelete(f func(cookie domain.Cookie)) {
+	v.onDelete = f
+}
+
+// SetOnClearDomain sets the callback to clear the cookies of a domain, the domain is empty to clear all of them.
+func (v *View) SetOnClearDomain(f func(domain string)) {
+	v.onClearDomain = f
+}
+
+// SetJarTitle sets the name of the environment the cookies belong to.
+func (v *View) SetJarTitle(title string) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	v.jarTitle = title
+}
+
+func (v *View) SetCookies(cookies []domain.Cookie) {
+	sort.Slice(cookies, func(i, j int) bool {
+		if cookies[i].Domain != cookies[j].Domain {
+			return cookies[i].Domain < cookies[j].Domain
+		}
+		return cookies[i].Name < cookies[j].Name
+	})
+
+	rows := make([]*row, 0, len(cookies))
+	for i := range cookies {
+		c := cookies[i]
+		if len(rows) == 0 || rows[len(rows)-1].domain != c.Domain {
+			rows = append(rows, &row{domain: c.Domain})
+		}
+
+		value := widgets.NewEditableLabel(c.Value)
+		value.SetOnChanged(func(text string) {
+			if v.onUpdate != nil {
+				updated := c
+				updated.Value = text
+				v.onUpdate(c, updated)
+			}
+		})
+
+		rows = append(rows, &row{domain: c.Domain, cookie: &c, Value: value})
+	}
+
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	v.rows = rows
+}
+
+func (v *View) filteredRows() []*row {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+
+	if v.filterText == "" {
+		return v.rows
+	}
+
+	out := make([]*row, 0)
+	for _, r := range v.rows {
+		if strings.Contains(r.domain, v.filterText) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (v *View) domainLayout(gtx layout.Context, theme *chapartheme.Theme, r *row) layout.Dimensions {
+	if r.clearButton.Clicked(gtx) && v.onClearDomain != nil {
+		v.onClearDomain(r.domain)
+	}
+
+	return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				lb := material.Label(theme.Material(), unit.Sp(15), r.domain)
+				lb.Font.Weight = font.Bold
+				return lb.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				btn := widgets.Button(theme.Material(), &r.clearButton, widgets.DeleteIcon, widgets.IconPositionStart, "Clear")
+				btn.Color = theme.ButtonTextColor
+				return btn.Layout(gtx, theme)
+			}),
+		)
+	})
+}
+
+func (v *View) cookieLayout(gtx layout.Context, theme *chapartheme.Theme, r *row) layout.Dimensions {
+	c := r.cookie
+
+	expires := "Session"
+	if !c.Expires.IsZero() {
+		expires = c.Expires.Local().Format(expiresLayout)
+	}
+
+	var flags []string
+	if c.Secure {
+		flags = append(flags, "Secure")
+	}
+	if c.HttpOnly {
+		flags = append(flags, "HttpOnly")
+	}
+	if c.SameSite != "" {
+		flags = append(flags, "SameSite="+c.SameSite)
+	}
+
+	column := func(width unit.Dp, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Dp(width)
+			lb := material.Label(theme.Material(), theme.TextSize, text)
+			lb.MaxLines = 1
+			return lb.Layout(gtx)
+		})
+	}
+
+	return layout.Inset{Top: unit.Dp(5), Bottom: unit.Dp(5), Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			column(150, c.Name),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return r.Value.Layout(gtx, theme)
+			}),
+			column(100, c.Path),
+			column(160, expires),
+			column(200, strings.Join(flags, ", ")),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				ib := widgets.IconButton{
+					Icon:      widgets.DeleteIcon,
+					Size:      unit.Dp(20),
+					Color:     theme.TextColor,
+					Clickable: &r.deleteButton,
+				}
+
+				ib.OnClick = func() {
+					if v.onDelete != nil {
+						v.onDelete(*c)
+					}
+				}
+
+				return ib.Layout(gtx, theme)
+			}),
+		)
+	})
+}
+
+func (v *View) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	rows := v.filteredRows()
+
+	if v.clearAllButton.Clicked(gtx) && v.onClearDomain != nil {
+		v.onClearDomain("")
+	}
+
+	v.mx.Lock()
+	description := "Cookies received in responses are stored per environment and sent with the next requests.\nClick on a value to edit it."
+	if v.jarTitle != "" {
+		description = "Cookies of " + v.jarTitle + " environment.\n" + description
+	}
+	v.mx.Unlock()
+
+	return layout.Inset{Top: unit.Dp(30), Left: unit.Dp(50), Right: unit.Dp(50)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle, Spacing: layout.SpaceEnd}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						lb := material.Label(theme.Material(), unit.Sp(18), "Cookies")
+						lb.Font.Weight = font.Bold
+						return lb.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &v.clearAllButton, widgets.DeleteIcon, widgets.IconPositionStart, "Clear All")
+						btn.Color = theme.ButtonTextColor
+						btn.Background = theme.SendButtonBgColor
+						return btn.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return material.Label(theme.Material(), theme.TextSize, description).Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Max.X = gtx.Dp(200)
+						return v.searchBox.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(rows) == 0 {
+					return material.Label(theme.Material(), theme.TextSize, "No cookies").Layout(gtx)
+				}
+
+				return material.List(theme.Material(), v.list).Layout(gtx, len(rows), func(gtx layout.Context, i int) layout.Dimensions {
+					if rows[i].cookie == nil {
+						return v.domainLayout(gtx, theme, rows[i])
+					}
+					return v.cookieLayout(gtx, theme, rows[i])
+				})
+			}),
+		)
+	})
+}
@@ -7,12 +7,15 @@ import (
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/converter"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
 type container struct {
 	// env container
+	Tabs        *widgets.Tabs
 	Items       *widgets.KeyValue
+	Proxy       *component.ProxySettings
 	Identifier  string
 	Title       *widgets.EditableLabel
 	SearchBox   *widgets.TextField
@@ -21,14 +24,19 @@ type container struct {
 	DataChanged bool
 }
 
-func newContainer(id, name string, items []domain.KeyValue) *container {
+func newContainer(env *domain.Environment, theme *chapartheme.Theme) *container {
 	search := widgets.NewTextField("", "Search items")
 	search.SetIcon(widgets.SearchIcon, widgets.IconPositionEnd)
 
 	c := &container{
-		Identifier: id,
-		Items:      widgets.NewKeyValue(converter.WidgetItemsFromKeyValue(items)...),
-		Title:      widgets.NewEditableLabel(name),
+		Identifier: env.MetaData.ID,
+		Tabs: widgets.NewTabs([]*widgets.Tab{
+			{Title: "Variables"},
+			{Title: "Proxy"},
+		}, nil),
+		Items:      widgets.NewKeyValue(converter.WidgetItemsFromKeyValue(env.Spec.Values)...),
+		Proxy:      component.NewProxySettings(env.Spec.Proxy, "Workspace default", theme),
+		Title:      widgets.NewEditableLabel(env.MetaData.Name),
 		SearchBox:  search,
 		SaveButton: widget.Clickable{},
 		Prompt:     widgets.NewPrompt("Save", "", widgets.ModalTypeWarn),
@@ -74,7 +82,13 @@ func (c *container) Layout(gtx layout.Context, theme *chapartheme.Theme, selecte
 					)
 				})
 			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return c.Tabs.Layout(gtx, theme)
+			}),
 			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				if c.Tabs.SelectedTab().Title == "Proxy" {
+					return c.Proxy.Layout(gtx, theme)
+				}
 				return c.Items.WithAddLayout(gtx, "", "Disabled items have no effect on your requests", theme)
 			}),
 		)
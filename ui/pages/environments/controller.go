@@ -36,6 +36,7 @@ func NewController(view *View, repo repository.Repository, envState *state.Envir
 	view.SetOnTreeViewNodeClicked(c.onTreeViewNodeDoubleClicked)
 	view.SetOnTabSelected(c.onTabSelected)
 	view.SetOnItemsChanged(c.onItemsChanged)
+	view.SetOnProxyChanged(c.onProxyChanged)
 	view.SetOnSave(c.onSave)
 	view.SetOnTabClose(c.onTabClose)
 	view.SetOnTreeViewMenuClicked(c.onTreeViewMenuClicked)
@@ -181,7 +182,34 @@ func (c *Controller) onItemsChanged(id string, items []domain.KeyValue) {
 		return
 	}
 
-	c.view.SetTabDirty(id, !domain.CompareKeyValues(env.Spec.Values, envFromFile.Spec.Values))
+	c.view.SetTabDirty(id, !domain.CompareEnvSpecs(env.Spec, envFromFile.Spec))
+}
+
+func (c *Controller) onProxyChanged(id string, proxy *domain.ProxySettings) {
+	env := c.state.GetEnvironment(id)
+	if env == nil {
+		return
+	}
+
+	// is data changed?
+	if domain.CompareProxySettings(env.Spec.Proxy, proxy) {
+		return
+	}
+
+	env.Spec.Proxy = proxy
+	if err := c.state.UpdateEnvironment(env, state.SourceController, true); err != nil {
+		fmt.Println("failed to update environment", err)
+		return
+	}
+
+	// set tab dirty if the in memory data is different from the file
+	envFromFile, err := c.state.GetEnvironmentFromDisc(id)
+	if err != nil {
+		fmt.Println("failed to get environment from file", err)
+		return
+	}
+
+	c.view.SetTabDirty(id, !domain.CompareEnvSpecs(env.Spec, envFromFile.Spec))
 }
 
 func (c *Controller) onSave(id string) {
@@ -205,7 +233,7 @@ func (c *Controller) onTabClose(id string) {
 	}
 
 	// if data is not changed close the tab
-	if domain.CompareKeyValues(env.Spec.Values, envFromFile.Spec.Values) {
+	if domain.CompareEnvSpecs(env.Spec, envFromFile.Spec) {
 		c.view.CloseTab(id)
 		return
 	}
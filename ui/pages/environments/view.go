@@ -36,6 +36,7 @@ type View struct {
 	onImportEnv           func()
 	onTabClose            func(id string)
 	onItemsChanged        func(id string, items []domain.KeyValue)
+	onProxyChanged        func(id string, proxy *domain.ProxySettings)
 	onSave                func(id string)
 	onTreeViewNodeClicked func(id string)
 	onTreeViewMenuClicked func(id string, action string)
@@ -47,6 +48,8 @@ type View struct {
 	treeViewNodes *safemap.Map[*widgets.TreeNode]
 
 	tipsView *tips.Tips
+
+	theme *chapartheme.Theme
 }
 
 func NewView(theme *chapartheme.Theme) *View {
@@ -73,6 +76,7 @@ func NewView(theme *chapartheme.Theme) *View {
 		containers:    safemap.New[*container](),
 
 		tipsView: tips.New(),
+		theme:    theme,
 	}
 
 	v.treeViewSearchBox.SetOnTextChange(func(text string) {
@@ -135,6 +139,10 @@ func (v *View) SetOnItemsChanged(onItemsChanged func(id string, items []domain.K
 	v.onItemsChanged = onItemsChanged
 }
 
+func (v *View) SetOnProxyChanged(onProxyChanged func(id string, proxy *domain.ProxySettings)) {
+	v.onProxyChanged = onProxyChanged
+}
+
 func (v *View) SetOnTreeViewNodeClicked(onTreeViewNodeClicked func(id string)) {
 	v.onTreeViewNodeClicked = onTreeViewNodeClicked
 	v.treeView.OnNodeClick(func(node *widgets.TreeNode) {
@@ -240,7 +248,7 @@ func (v *View) OpenContainer(env *domain.Environment) {
 		return
 	}
 
-	ct := newContainer(env.MetaData.ID, env.MetaData.Name, env.Spec.Values)
+	ct := newContainer(env, v.theme)
 	ct.Title.SetOnChanged(func(text string) {
 		if v.onTitleChanged != nil {
 			v.onTitleChanged(env.MetaData.ID, text)
@@ -253,6 +261,12 @@ func (v *View) OpenContainer(env *domain.Environment) {
 		}
 	})
 
+	ct.Proxy.SetOnChange(func(proxy *domain.ProxySettings) {
+		if v.onProxyChanged != nil {
+			v.onProxyChanged(env.MetaData.ID, proxy)
+		}
+	})
+
 	ct.SearchBox.SetOnTextChange(func(text string) {
 		if ct.Items == nil {
 			return
@@ -266,6 +280,7 @@ func (v *View) OpenContainer(env *domain.Environment) {
 func (v *View) ReloadContainerData(env *domain.Environment) {
 	if ct, ok := v.containers.Get(env.MetaData.ID); ok {
 		ct.SetItems(env.Spec.Values)
+		ct.Proxy.SetProxy(env.Spec.Proxy)
 	}
 }
 
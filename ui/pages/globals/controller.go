@@ -0,0 +1,95 @@
+package globals
+
+import (
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/state"
+)
+
+type Controller struct {
+	view  *View
+	state *state.Environments
+}
+
+func NewController(view *View, envState *state.Environments) *Controller {
+	c := &Controller{
+		view:  view,
+		state: envState,
+	}
+
+	view.SetOnItemsChanged(c.onItemsChanged)
+	view.SetOnSave(c.onSave)
+	view.SetOnClear(c.onClear)
+
+	envState.AddGlobalsChangeListener(func(globals *domain.Globals, source state.Source) {
+		if source == state.SourceController {
+			return
+		}
+
+		// post requests set the globals while the changes of the view are in the state, so they are kept
+		c.view.SetItems(globals.Spec.Values)
+	})
+
+	return c
+}
+
+func (c *Controller) LoadData() error {
+	globals, err := c.state.GetGlobals()
+	if err != nil {
+		return err
+	}
+
+	c.view.SetItems(globals.Spec.Values)
+	c.view.SetDirty(false)
+	return nil
+}
+
+func (c *Controller) onItemsChanged(items []domain.KeyValue) {
+	globals, err := c.state.GetGlobals()
+	if err != nil {
+		fmt.Println("failed to get globals", err)
+		return
+	}
+
+	if domain.CompareKeyValues(globals.Spec.Values, items) {
+		return
+	}
+
+	if err := c.state.SetGlobals(items, state.SourceController, true); err != nil {
+		fmt.Println("failed to update globals", err)
+		return
+	}
+
+	c.view.SetDirty(true)
+}
+
+func (c *Controller) onSave() {
+	globals, err := c.state.GetGlobals()
+	if err != nil {
+		fmt.Println("failed to get globals", err)
+		return
+	}
+
+	if err := c.state.SetGlobals(globals.Spec.Values, state.SourceController, false); err != nil {
+		fmt.Println("failed to save globals", err)
+		return
+	}
+
+	c.view.SetDirty(false)
+}
+
+func (c *Controller) onClear() {
+	if err := c.state.SetGlobals(nil, state.SourceController, false); err != nil {
+		fmt.Println("failed to clear globals", err)
+		return
+	}
+
+	c.reload()
+}
+
+func (c *Controller) reload() {
+	if err := c.LoadData(); err != nil {
+		fmt.Println("failed to load globals", err)
+	}
+}
@@ -0,0 +1,146 @@
+package globals
+
+import (
+	"sync"
+
+	"gioui.org/app"
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/converter"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const description = "Global variables are available whichever environment is active, the collection and environment variables override them.\nPost request actions with the global scope set them."
+
+type View struct {
+	window *app.Window
+
+	saveButton     widget.Clickable
+	clearAllButton widget.Clickable
+	searchBox      *widgets.TextField
+	items          *widgets.KeyValue
+
+	mx    *sync.Mutex
+	dirty bool
+
+	onItemsChanged func(items []domain.KeyValue)
+	onSave         func()
+	onClear        func()
+}
+
+func NewView(w *app.Window) *View {
+	search := widgets.NewTextField("", "Search items")
+	search.SetIcon(widgets.SearchIcon, widgets.IconPositionEnd)
+
+	v := &View{
+		window:    w,
+		searchBox: search,
+		items:     widgets.NewKeyValue(),
+		mx:        &sync.Mutex{},
+	}
+
+	v.searchBox.SetOnTextChange(func(text string) {
+		v.items.Filter(text)
+	})
+
+	v.items.SetOnChanged(func(items []*widgets.KeyValueItem) {
+		if v.onItemsChanged != nil {
+			v.onItemsChanged(converter.KeyValueFromWidgetItems(items))
+		}
+	})
+
+	return v
+}
+
+func (v *View) SetOnItemsChanged(f func(items []domain.KeyValue)) {
+	v.onItemsChanged = f
+}
+
+func (v *View) SetOnSave(f func()) {
+	v.onSave = f
+}
+
+// SetOnClear sets the callback to remove all the global variables.
+func (v *View) SetOnClear(f func()) {
+	v.onClear = f
+}
+
+// SetItems sets the variables to show, it is called from the goroutines of the requests so the window is invalidated.
+func (v *View) SetItems(items []domain.KeyValue) {
+	v.items.SetItems(converter.WidgetItemsFromKeyValue(items))
+	v.window.Invalidate()
+}
+
+// SetDirty sets whether the variables have changes which are not saved yet.
+func (v *View) SetDirty(dirty bool) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	v.dirty = dirty
+}
+
+func (v *View) isDirty() bool {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	return v.dirty
+}
+
+func (v *View) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if v.saveButton.Clicked(gtx) && v.onSave != nil {
+		v.onSave()
+	}
+
+	if v.clearAllButton.Clicked(gtx) && v.onClear != nil {
+		v.onClear()
+	}
+
+	return layout.Inset{Top: unit.Dp(30), Left: unit.Dp(50), Right: unit.Dp(50), Bottom: unit.Dp(20)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceEnd}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								lb := material.Label(theme.Material(), unit.Sp(18), "Globals")
+								lb.Font.Weight = font.Bold
+								return lb.Layout(gtx)
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if !v.isDirty() {
+									return layout.Dimensions{}
+								}
+								return widgets.SaveButtonLayout(gtx, theme, &v.saveButton)
+							}),
+						)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &v.clearAllButton, widgets.DeleteIcon, widgets.IconPositionStart, "Clear All")
+						btn.Color = theme.ButtonTextColor
+						btn.Background = theme.SendButtonBgColor
+						return btn.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return material.Label(theme.Material(), theme.TextSize, description).Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Max.X = gtx.Dp(200)
+						return v.searchBox.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return v.items.WithAddLayout(gtx, "", "Disabled items have no effect on your requests", theme)
+			}),
+		)
+	})
+}
@@ -0,0 +1,168 @@
+package protofiles
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/grpc"
+	"github.com/chapar-rest/chapar/internal/state"
+	"github.com/chapar-rest/chapar/ui/explorer"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	view *View
+
+	state    *state.ProtoFiles
+	explorer *explorer.Explorer
+}
+
+func NewController(view *View, protoFilesState *state.ProtoFiles, explorer *explorer.Explorer) *Controller {
+	c := &Controller{
+		view:     view,
+		state:    protoFilesState,
+		explorer: explorer,
+	}
+
+	view.SetOnAddFile(c.onAddFile)
+	view.SetOnDeleteFile(c.onDeleteFile)
+	view.SetOnAddImportPath(c.onAddImportPath)
+	view.SetOnDeleteImportPath(c.onDeleteImportPath)
+	view.SetOnReload(c.onReload)
+
+	return c
+}
+
+func (c *Controller) LoadData() error {
+	list, err := c.state.GetProtoFiles()
+	if err != nil {
+		return err
+	}
+
+	c.view.SetError("")
+	c.view.SetProtoFiles(list.Spec)
+	return nil
+}
+
+func (c *Controller) onAddFile() {
+	c.explorer.ChoseFile(func(result explorer.Result) {
+		if result.Error != nil {
+			fmt.Println("failed to get file", result.Error)
+			return
+		}
+		if result.FilePath == "" {
+			return
+		}
+
+		list, err := c.state.GetProtoFiles()
+		if err != nil {
+			fmt.Println("failed to get proto files", err)
+			return
+		}
+
+		if slices.ContainsFunc(list.Spec.Files, func(f domain.ProtoFile) bool { return f.Path == result.FilePath }) {
+			c.view.SetError(result.FilePath + " is already added")
+			return
+		}
+
+		// only the new file is parsed, so the errors of the other files do not prevent adding it
+		file := domain.ProtoFile{ID: uuid.NewString(), Path: result.FilePath}
+		files, err := c.parse(domain.ProtoFileListSpec{ImportPaths: list.Spec.ImportPaths, Files: []domain.ProtoFile{file}})
+		if err != nil {
+			c.view.SetError(err.Error())
+			return
+		}
+
+		list.Spec.Files = append(list.Spec.Files, files...)
+		c.save(list)
+	}, ".proto")
+}
+
+func (c *Controller) onDeleteFile(id string) {
+	list, err := c.state.GetProtoFiles()
+	if err != nil {
+		fmt.Println("failed to get proto files", err)
+		return
+	}
+
+	list.Spec.Files = slices.DeleteFunc(list.Spec.Files, func(f domain.ProtoFile) bool { return f.ID == id })
+	c.save(list)
+}
+
+func (c *Controller) onAddImportPath(path string) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return
+	}
+
+	list, err := c.state.GetProtoFiles()
+	if err != nil {
+		fmt.Println("failed to get proto files", err)
+		return
+	}
+
+	if slices.Contains(list.Spec.ImportPaths, path) {
+		return
+	}
+
+	list.Spec.ImportPaths = append(list.Spec.ImportPaths, path)
+	c.save(list)
+}
+
+func (c *Controller) onDeleteImportPath(path string) {
+	list, err := c.state.GetProtoFiles()
+	if err != nil {
+		fmt.Println("failed to get proto files", err)
+		return
+	}
+
+	list.Spec.ImportPaths = slices.DeleteFunc(list.Spec.ImportPaths, func(p string) bool { return p == path })
+	c.save(list)
+}
+
+// onReload parses all the files again to pick up the changes made to them on disk.
+func (c *Controller) onReload() {
+	list, err := c.state.GetProtoFiles()
+	if err != nil {
+		fmt.Println("failed to get proto files", err)
+		return
+	}
+
+	files, err := c.parse(list.Spec)
+	if err != nil {
+		c.view.SetError(err.Error())
+		return
+	}
+
+	list.Spec.Files = files
+	c.save(list)
+}
+
+// parse parses the files of the spec and returns them with their package and services filled.
+func (c *Controller) parse(spec domain.ProtoFileListSpec) ([]domain.ProtoFile, error) {
+	descriptors, err := grpc.ParseProtoFiles(context.Background(), spec)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]domain.ProtoFile, len(spec.Files))
+	for i, f := range spec.Files {
+		files[i] = f
+		files[i].Package, files[i].Services = grpc.ProtoFileInfo(descriptors[i])
+	}
+	return files, nil
+}
+
+func (c *Controller) save(list *domain.ProtoFileList) {
+	if err := c.state.UpdateProtoFiles(list, state.SourceController); err != nil {
+		fmt.Println("failed to update proto files", err)
+		c.view.SetError(err.Error())
+		return
+	}
+
+	c.view.SetError("")
+	c.view.SetProtoFiles(list.Spec)
+}
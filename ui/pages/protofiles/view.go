@@ -0,0 +1,282 @@
+package protofiles
+
+import (
+	"strings"
+	"sync"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+type View struct {
+	addFileButton       widget.Clickable
+	reloadButton        widget.Clickable
+	addImportPathButton widget.Clickable
+	importPathField     *widgets.TextField
+
+	mx         *sync.Mutex
+	importPath string
+	errorText  string
+
+	files       []*fileRow
+	importPaths []*importPathRow
+	list        *widget.List
+
+	onAddFile          func()
+	onDeleteFile       func(id string)
+	onAddImportPath    func(path string)
+	onDeleteImportPath func(path string)
+	onReload           func()
+}
+
+type fileRow struct {
+	file         domain.ProtoFile
+	deleteButton widget.Clickable
+}
+
+type importPathRow struct {
+	path         string
+	deleteButton widget.Clickable
+}
+
+func NewView() *View {
+	v := &View{
+		mx:              &sync.Mutex{},
+		importPathField: widgets.NewTextField("", "/path/to/protos"),
+		list: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+
+	v.importPathField.SetOnTextChange(func(text string) {
+		v.mx.Lock()
+		defer v.mx.Unlock()
+		v.importPath = text
+	})
+
+	return v
+}
+
+func (v *View) SetOnAddFile(f func()) {
+	v.onAddFile = f
+}
+
+func (v *View) SetOnDeleteFile(f func(id string)) {
+	v.onDeleteFile = f
+}
+
+func (v *View) SetOnAddImportPath(f func(path string)) {
+	v.onAddImportPath = f
+}
+
+func (v *View) SetOnDeleteImportPath(f func(path string)) {
+	v.onDeleteImportPath = f
+}
+
+func (v *View) SetOnReload(f func()) {
+	v.onReload = f
+}
+
+// SetError shows the error of the last parse, an empty text hides it.
+func (v *View) SetError(text string) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	v.errorText = text
+}
+
+func (v *View) SetProtoFiles(spec domain.ProtoFileListSpec) {
+	files := make([]*fileRow, 0, len(spec.Files))
+	for _, f := range spec.Files {
+		files = append(files, &fileRow{file: f})
+	}
+
+	importPaths := make([]*importPathRow, 0, len(spec.ImportPaths))
+	for _, p := range spec.ImportPaths {
+		importPaths = append(importPaths, &importPathRow{path: p})
+	}
+
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	v.files = files
+	v.importPaths = importPaths
+}
+
+func (v *View) deleteButton(gtx layout.Context, theme *chapartheme.Theme, clickable *widget.Clickable, onClick func()) layout.Dimensions {
+	ib := widgets.IconButton{
+		Icon:      widgets.DeleteIcon,
+		Size:      unit.Dp(20),
+		Color:     theme.TextColor,
+		Clickable: clickable,
+		OnClick:   onClick,
+	}
+	return ib.Layout(gtx, theme)
+}
+
+func (v *View) sectionTitle(theme *chapartheme.Theme, text string) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			lb := material.Label(theme.Material(), unit.Sp(15), text)
+			lb.Font.Weight = font.Bold
+			return lb.Layout(gtx)
+		})
+	}
+}
+
+func (v *View) importPathLayout(gtx layout.Context, theme *chapartheme.Theme, r *importPathRow) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(5), Bottom: unit.Dp(5), Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, r.path).Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return v.deleteButton(gtx, theme, &r.deleteButton, func() {
+					if v.onDeleteImportPath != nil {
+						v.onDeleteImportPath(r.path)
+					}
+				})
+			}),
+		)
+	})
+}
+
+func (v *View) fileLayout(gtx layout.Context, theme *chapartheme.Theme, r *fileRow) layout.Dimensions {
+	column := func(width unit.Dp, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Dp(width)
+			lb := material.Label(theme.Material(), theme.TextSize, text)
+			lb.MaxLines = 1
+			return lb.Layout(gtx)
+		})
+	}
+
+	return layout.Inset{Top: unit.Dp(5), Bottom: unit.Dp(5), Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, r.file.Path).Layout),
+			column(200, r.file.Package),
+			column(250, strings.Join(r.file.Services, ", ")),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return v.deleteButton(gtx, theme, &r.deleteButton, func() {
+					if v.onDeleteFile != nil {
+						v.onDeleteFile(r.file.ID)
+					}
+				})
+			}),
+		)
+	})
+}
+
+func (v *View) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if v.addFileButton.Clicked(gtx) && v.onAddFile != nil {
+		v.onAddFile()
+	}
+
+	if v.reloadButton.Clicked(gtx) && v.onReload != nil {
+		v.onReload()
+	}
+
+	v.mx.Lock()
+	importPath := v.importPath
+	errorText := v.errorText
+	files := v.files
+	importPaths := v.importPaths
+	v.mx.Unlock()
+
+	if v.addImportPathButton.Clicked(gtx) && v.onAddImportPath != nil && strings.TrimSpace(importPath) != "" {
+		v.onAddImportPath(importPath)
+		v.importPathField.SetText("")
+		v.mx.Lock()
+		v.importPath = ""
+		v.mx.Unlock()
+	}
+
+	// the import paths and the files are laid out as a single list, the import paths come first
+	rows := make([]layout.Widget, 0, len(importPaths)+len(files)+3)
+	rows = append(rows, v.sectionTitle(theme, "Import paths"))
+	rows = append(rows, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return v.importPathField.Layout(gtx, theme)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				btn := widgets.Button(theme.Material(), &v.addImportPathButton, widgets.PlusIcon, widgets.IconPositionStart, "Add")
+				btn.Color = theme.ButtonTextColor
+				return btn.Layout(gtx, theme)
+			}),
+		)
+	})
+	for _, r := range importPaths {
+		rows = append(rows, func(gtx layout.Context) layout.Dimensions {
+			return v.importPathLayout(gtx, theme, r)
+		})
+	}
+
+	rows = append(rows, v.sectionTitle(theme, "Files"))
+	if len(files) == 0 {
+		rows = append(rows, material.Label(theme.Material(), theme.TextSize, "No proto files").Layout)
+	}
+	for _, r := range files {
+		rows = append(rows, func(gtx layout.Context) layout.Dimensions {
+			return v.fileLayout(gtx, theme, r)
+		})
+	}
+
+	return layout.Inset{Top: unit.Dp(30), Left: unit.Dp(50), Right: unit.Dp(50)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle, Spacing: layout.SpaceEnd}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						lb := material.Label(theme.Material(), unit.Sp(18), "Proto files")
+						lb.Font.Weight = font.Bold
+						return lb.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								btn := widgets.Button(theme.Material(), &v.reloadButton, widgets.RefreshIcon, widgets.IconPositionStart, "Reload")
+								btn.Color = theme.ButtonTextColor
+								return btn.Layout(gtx, theme)
+							}),
+							layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								btn := widgets.Button(theme.Material(), &v.addFileButton, widgets.PlusIcon, widgets.IconPositionStart, "Add file")
+								btn.Color = theme.ButtonTextColor
+								btn.Background = theme.SendButtonBgColor
+								return btn.Layout(gtx, theme)
+							}),
+						)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				text := "gRPC requests with server reflection turned off read their services and messages from these files.\nImports are resolved from the import paths and then from the directory of each file."
+				return material.Label(theme.Material(), theme.TextSize, text).Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if errorText == "" {
+					return layout.Dimensions{}
+				}
+
+				return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					lb := material.Label(theme.Material(), theme.TextSize, errorText)
+					lb.Color = theme.ErrorColor
+					return lb.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return material.List(theme.Material(), v.list).Layout(gtx, len(rows), func(gtx layout.Context, i int) layout.Dimensions {
+					return rows[i](gtx)
+				})
+			}),
+		)
+	})
+}
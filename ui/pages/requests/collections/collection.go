@@ -5,8 +5,11 @@ import (
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/runner"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/converter"
 	"github.com/chapar-rest/chapar/ui/keys"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
@@ -16,7 +19,9 @@ type Collection struct {
 
 	saveButton *widget.Clickable
 
-	prompt *widgets.Prompt
+	prompt    *widgets.Prompt
+	variables *widgets.KeyValue
+	runner    *component.Runner
 
 	dataChanged   bool
 	onSave        func(id string)
@@ -49,11 +54,61 @@ func (c *Collection) SetOnSave(f func(id string)) {
 	c.onSave = f
 }
 
+func (c *Collection) SetOnVariablesChanged(f func(id string, values []domain.KeyValue)) {
+	c.variables.SetOnChanged(func(items []*widgets.KeyValueItem) {
+		f(c.collection.MetaData.ID, converter.KeyValueFromWidgetItems(items))
+	})
+}
+
+// SetVariables shows the given variables, they are left as they are when the editor already shows them.
+func (c *Collection) SetVariables(values []domain.KeyValue) {
+	if domain.CompareKeyValues(converter.KeyValueFromWidgetItems(c.variables.GetItems()), values) {
+		return
+	}
+	c.variables.SetItems(converter.WidgetItemsFromKeyValue(values))
+}
+
+func (c *Collection) SetOnRun(f func(id string, opts runner.Options)) {
+	c.runner.SetOnRun(func(opts runner.Options) {
+		f(c.collection.MetaData.ID, opts)
+	})
+}
+
+func (c *Collection) SetOnStopRun(f func(id string)) {
+	c.runner.SetOnStop(func() {
+		f(c.collection.MetaData.ID)
+	})
+}
+
+func (c *Collection) SetOnSelectRunDataFile(f func(id string)) {
+	c.runner.SetOnSelectDataFile(func() {
+		f(c.collection.MetaData.ID)
+	})
+}
+
+func (c *Collection) SetRunDataFile(path string) {
+	c.runner.SetDataFile(path)
+}
+
+func (c *Collection) SetRunStarted() {
+	c.runner.SetRunStarted()
+}
+
+func (c *Collection) AddRunResult(result runner.Result) {
+	c.runner.AddResult(result)
+}
+
+func (c *Collection) SetRunFinished(report *runner.Report, err error) {
+	c.runner.SetRunFinished(report, err)
+}
+
 func New(collection *domain.Collection) *Collection {
 	c := &Collection{
 		collection: collection,
 		Title:      widgets.NewEditableLabel(collection.MetaData.Name),
 		prompt:     widgets.NewPrompt("", "", ""),
+		variables:  widgets.NewKeyValue(converter.WidgetItemsFromKeyValue(collection.Spec.Variables)...),
+		runner:     component.NewRunner("Run the requests of the collection in order against the active environment"),
 		saveButton: new(widget.Clickable),
 	}
 	c.prompt.WithoutRememberBool()
@@ -102,6 +157,16 @@ func (c *Collection) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 					)
 				})
 			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				// the variables take up to a third of the page, the rest is for the runner
+				gtx.Constraints.Max.Y /= 3
+				return layout.Inset{Bottom: unit.Dp(15)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return c.variables.WithAddLayout(gtx, "Variables", "Shared by the requests of the collection, the environment overrides them", theme)
+				})
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return c.runner.Layout(gtx, theme)
+			}),
 		)
 	})
 }
@@ -16,20 +16,33 @@ type AddressBar struct {
 
 	lastSelectedMethod string
 	methodDropDown     *widgets.DropDown
+	// withMethod is false for protocols which do not have a method, the method drop down is hidden
+	withMethod bool
 
 	sendClickable widget.Clickable
 	sendButton    material.ButtonStyle
 
+	// sending is true while a request is in flight, the send button becomes a cancel button
+	sending bool
+
+	submitText string
+	cancelText string
+
 	onURLChanged    func(url string)
 	onMethodChanged func(method string)
 	onSubmit        func()
+	onCancel        func()
 }
 
+// NewAddressBar creates an address bar, the method drop down is hidden when method is empty.
 func NewAddressBar(theme *chapartheme.Theme, address, method string) *AddressBar {
 	a := &AddressBar{
 		url:                &widget.Editor{},
 		methodDropDown:     widgets.NewDropDownWithoutBorder(theme),
 		lastSelectedMethod: method,
+		withMethod:         method != "",
+		submitText:         "Send",
+		cancelText:         "Cancel",
 	}
 
 	a.url.SingleLine = true
@@ -67,6 +80,20 @@ func (a *AddressBar) SetOnSubmit(onSubmit func()) {
 	a.onSubmit = onSubmit
 }
 
+func (a *AddressBar) SetOnCancel(onCancel func()) {
+	a.onCancel = onCancel
+}
+
+// SetButtonTexts sets the texts of the button when it submits and when it cancels, e.g. Connect and Disconnect.
+func (a *AddressBar) SetButtonTexts(submit, cancel string) {
+	a.submitText = submit
+	a.cancelText = cancel
+}
+
+func (a *AddressBar) SetSendingRequest(sending bool) {
+	a.sending = sending
+}
+
 func (a *AddressBar) SetURL(url string) {
 	a.url.SetText(url)
 }
@@ -95,7 +122,7 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 		}
 	}
 
-	if a.methodDropDown.GetSelected().Text != a.lastSelectedMethod {
+	if a.withMethod && a.methodDropDown.GetSelected().Text != a.lastSelectedMethod {
 		a.lastSelectedMethod = a.methodDropDown.GetSelected().Text
 		if a.onMethodChanged != nil {
 			a.onMethodChanged(a.lastSelectedMethod)
@@ -115,10 +142,18 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 						Alignment: layout.Middle,
 					}.Layout(gtx,
 						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if !a.withMethod {
+								return layout.Dimensions{}
+							}
 							gtx.Constraints.Min.Y = gtx.Dp(20)
 							return a.methodDropDown.Layout(gtx, theme)
 						}),
-						widgets.DrawLineFlex(theme.SeparatorColor, unit.Dp(20), unit.Dp(1)),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if !a.withMethod {
+								return layout.Dimensions{}
+							}
+							return widgets.DrawLine(gtx, theme.SeparatorColor, unit.Dp(20), unit.Dp(1))
+						}),
 						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 							return layout.Inset{Left: unit.Dp(10), Right: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 								gtx.Constraints.Min.Y = gtx.Dp(20)
@@ -134,13 +169,24 @@ func (a *AddressBar) Layout(gtx layout.Context, theme *chapartheme.Theme) layout
 		}),
 		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 			if a.sendClickable.Clicked(gtx) {
-				if a.onSubmit != nil {
+				if a.sending {
+					if a.onCancel != nil {
+						a.onCancel()
+					}
+				} else if a.onSubmit != nil {
 					go a.onSubmit()
 				}
 			}
 
 			gtx.Constraints.Min.X = gtx.Dp(80)
-			btn := material.Button(theme.Material(), &a.sendClickable, "Send")
+			if a.sending {
+				btn := material.Button(theme.Material(), &a.sendClickable, a.cancelText)
+				btn.Background = theme.ErrorColor
+				btn.Color = theme.ButtonTextColor
+				return btn.Layout(gtx)
+			}
+
+			btn := material.Button(theme.Material(), &a.sendClickable, a.submitText)
 			btn.Background = theme.SendButtonBgColor
 			btn.Color = theme.ButtonTextColor
 			return btn.Layout(gtx)
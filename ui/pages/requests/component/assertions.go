@@ -0,0 +1,357 @@
+package component
+
+import (
+	"sync"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+var assertionTypes = []struct {
+	title string
+	value string
+}{
+	{"Status", domain.AssertionTypeStatus},
+	{"JSONPath", domain.AssertionTypeJSONPath},
+	{"Header", domain.AssertionTypeHeader},
+	{"Body Contains", domain.AssertionTypeBodyContains},
+	{"Latency", domain.AssertionTypeLatency},
+}
+
+var assertionOperatorTitles = map[string]string{
+	domain.AssertionOperatorEquals:   "Equals",
+	domain.AssertionOperatorInRange:  "In Range",
+	domain.AssertionOperatorMatches:  "Matches",
+	domain.AssertionOperatorExists:   "Exists",
+	domain.AssertionOperatorContains: "Contains",
+	domain.AssertionOperatorLessThan: "Less Than (ms)",
+}
+
+// Assertions is the editor of the tests of a request, every row is an assertion checked against the response.
+type Assertions struct {
+	mx    *sync.Mutex
+	items []*assertionItem
+
+	theme     *chapartheme.Theme
+	addButton *widgets.IconButton
+	list      *widget.List
+
+	onChange func(assertions []domain.Assertion)
+}
+
+type assertionItem struct {
+	assertion domain.Assertion
+
+	enable           widget.Bool
+	typeDropDown     *widgets.DropDown
+	operatorDropDown *widgets.DropDown
+	targetEditor     widget.Editor
+	valueEditor      widget.Editor
+	deleteButton     widget.Clickable
+}
+
+func NewAssertions(theme *chapartheme.Theme, assertions []domain.Assertion) *Assertions {
+	a := &Assertions{
+		mx:    &sync.Mutex{},
+		theme: theme,
+		addButton: &widgets.IconButton{
+			Icon:      widgets.PlusIcon,
+			Size:      unit.Dp(20),
+			Clickable: &widget.Clickable{},
+		},
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	a.addButton.OnClick = func() {
+		a.mx.Lock()
+		a.items = append(a.items, a.newItem(domain.NewAssertion()))
+		a.mx.Unlock()
+		a.triggerChanged()
+	}
+
+	a.SetAssertions(assertions)
+	return a
+}
+
+func (a *Assertions) newItem(assertion domain.Assertion) *assertionItem {
+	item := &assertionItem{
+		assertion:        assertion,
+		enable:           widget.Bool{Value: assertion.Enable},
+		typeDropDown:     widgets.NewDropDown(a.theme),
+		operatorDropDown: widgets.NewDropDown(a.theme),
+	}
+
+	item.targetEditor.SingleLine = true
+	item.targetEditor.SetText(assertion.Target)
+	item.valueEditor.SingleLine = true
+	item.valueEditor.SetText(assertion.Value)
+
+	opts := make([]*widgets.DropDownOption, 0, len(assertionTypes))
+	for _, t := range assertionTypes {
+		opts = append(opts, widgets.NewDropDownOption(t.title).WithValue(t.value))
+	}
+	item.typeDropDown.SetOptions(opts...)
+	item.typeDropDown.SetSelectedByValue(assertion.Type)
+	item.typeDropDown.MinWidth = unit.Dp(120)
+
+	item.setOperators()
+	item.operatorDropDown.MinWidth = unit.Dp(120)
+	return item
+}
+
+// setOperators sets the operators of the assertion type, the operator is reset to the default one if the type does not support it.
+func (i *assertionItem) setOperators() {
+	operators := domain.AssertionOperators(i.assertion.Type)
+	opts := make([]*widgets.DropDownOption, 0, len(operators))
+	supported := false
+	for _, op := range operators {
+		opts = append(opts, widgets.NewDropDownOption(assertionOperatorTitles[op]).WithValue(op))
+		if op == i.assertion.Operator {
+			supported = true
+		}
+	}
+
+	if !supported && len(operators) > 0 {
+		i.assertion.Operator = operators[0]
+	}
+
+	i.operatorDropDown.SetOptions(opts...)
+	i.operatorDropDown.SetSelectedByValue(i.assertion.Operator)
+}
+
+func (a *Assertions) SetAssertions(assertions []domain.Assertion) {
+	items := make([]*assertionItem, 0, len(assertions))
+	for _, assertion := range assertions {
+		items = append(items, a.newItem(assertion))
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+	a.items = items
+}
+
+func (a *Assertions) GetAssertions() []domain.Assertion {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	out := make([]domain.Assertion, 0, len(a.items))
+	for _, item := range a.items {
+		out = append(out, item.assertion)
+	}
+	return out
+}
+
+func (a *Assertions) SetOnChange(f func(assertions []domain.Assertion)) {
+	a.onChange = f
+}
+
+func (a *Assertions) triggerChanged() {
+	if a.onChange != nil {
+		a.onChange(a.GetAssertions())
+	}
+}
+
+// update applies the changes of the row widgets to the assertion and reports whether there was any.
+func (i *assertionItem) update(gtx layout.Context) bool {
+	changed := false
+
+	if i.enable.Update(gtx) {
+		i.assertion.Enable = i.enable.Value
+		changed = true
+	}
+
+	// dropdowns report changes on another goroutine, so their selection is compared on every frame instead
+	if selected := i.typeDropDown.GetSelected().Value; selected != i.assertion.Type {
+		i.assertion.Type = selected
+		i.setOperators()
+		changed = true
+	}
+
+	if selected := i.operatorDropDown.GetSelected().Value; selected != i.assertion.Operator {
+		i.assertion.Operator = selected
+		changed = true
+	}
+
+	for {
+		event, ok := i.targetEditor.Update(gtx)
+		if !ok {
+			break
+		}
+		if _, ok := event.(widget.ChangeEvent); ok {
+			i.assertion.Target = i.targetEditor.Text()
+			changed = true
+		}
+	}
+
+	for {
+		event, ok := i.valueEditor.Update(gtx)
+		if !ok {
+			break
+		}
+		if _, ok := event.(widget.ChangeEvent); ok {
+			i.assertion.Value = i.valueEditor.Text()
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func (a *Assertions) itemLayout(gtx layout.Context, theme *chapartheme.Theme, item *assertionItem, last bool) layout.Dimensions {
+	leftPadding := layout.Inset{Left: unit.Dp(8)}
+
+	editor := func(ed *widget.Editor, hint string) layout.FlexChild {
+		return layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				e := material.Editor(theme.Material(), ed, hint)
+				e.SelectionColor = theme.TextSelectionColor
+				return e.Layout(gtx)
+			})
+		})
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				ch := material.CheckBox(theme.Material(), &item.enable, "")
+				ch.IconColor = theme.CheckBoxColor
+				return ch.Layout(gtx)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return item.typeDropDown.Layout(gtx, theme)
+			})
+		}),
+	}
+
+	switch item.assertion.Type {
+	case domain.AssertionTypeJSONPath:
+		children = append(children, editor(&item.targetEditor, "$.path"))
+	case domain.AssertionTypeHeader:
+		children = append(children, editor(&item.targetEditor, "Header name"))
+	}
+
+	children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+		return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return item.operatorDropDown.Layout(gtx, theme)
+		})
+	}))
+
+	if item.assertion.Operator != domain.AssertionOperatorExists {
+		hint := "Value"
+		switch item.assertion.Operator {
+		case domain.AssertionOperatorInRange:
+			hint = "200-299"
+		case domain.AssertionOperatorMatches:
+			hint = "Regular expression"
+		case domain.AssertionOperatorLessThan:
+			hint = "Milliseconds"
+		}
+		children = append(children, editor(&item.valueEditor, hint))
+	} else {
+		children = append(children, layout.Flexed(1, layout.Spacer{}.Layout))
+	}
+
+	children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+		ib := widgets.IconButton{
+			Icon:      widgets.DeleteIcon,
+			Size:      unit.Dp(20),
+			Color:     theme.TextColor,
+			Clickable: &item.deleteButton,
+		}
+		return ib.Layout(gtx, theme)
+	}))
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if last {
+				return layout.Dimensions{}
+			}
+			return widgets.DrawLine(gtx, theme.TableBorderColor, unit.Dp(1), unit.Dp(gtx.Constraints.Max.X))
+		}),
+	)
+}
+
+func (a *Assertions) handleChanges(gtx layout.Context) {
+	a.mx.Lock()
+	changed := false
+	items := a.items[:0:0]
+	for _, item := range a.items {
+		if item.deleteButton.Clicked(gtx) {
+			changed = true
+			continue
+		}
+
+		if item.update(gtx) {
+			changed = true
+		}
+		items = append(items, item)
+	}
+	a.items = items
+	a.mx.Unlock()
+
+	if changed {
+		a.triggerChanged()
+	}
+}
+
+func (a *Assertions) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	a.handleChanges(gtx)
+
+	a.mx.Lock()
+	items := a.items
+	a.mx.Unlock()
+
+	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(material.Label(theme.Material(), theme.TextSize, "Tests").Layout),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Left: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx,
+							material.Label(theme.Material(), unit.Sp(10), "Assertions are checked against the response after every send").Layout,
+						)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							a.addButton.BackgroundColor = theme.Palette.Bg
+							a.addButton.Color = theme.TextColor
+							return a.addButton.Layout(gtx, theme)
+						})
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				border := widget.Border{
+					Color:        theme.TableBorderColor,
+					CornerRadius: unit.Dp(4),
+					Width:        unit.Dp(1),
+				}
+
+				return border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					if len(items) == 0 {
+						return layout.UniformInset(unit.Dp(10)).Layout(gtx, material.Label(theme.Material(), unit.Sp(14), "No tests").Layout)
+					}
+
+					return material.List(theme.Material(), a.list).Layout(gtx, len(items), func(gtx layout.Context, i int) layout.Dimensions {
+						return a.itemLayout(gtx, theme, items[i], i == len(items)-1)
+					})
+				})
+			}),
+		)
+	})
+}
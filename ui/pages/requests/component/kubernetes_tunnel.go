@@ -0,0 +1,139 @@
+package component
+
+import (
+	"strconv"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const (
+	k8sTarget      = "Target"
+	k8sContext     = "Context"
+	k8sNamespace   = "Namespace"
+	k8sLocalPort   = "Local Port"
+	k8sTargetPort  = "Target Port"
+	k8sIdleTimeout = "Keep Alive"
+	k8sDescription = "Runs kubectl port-forward and sends the request through it. The target port defaults to the port of the request url and the forward is kept open for the keep alive after the last request, one minute by default."
+)
+
+// KubernetesTunnelForm edits the kubectl port forward a request is sent through.
+type KubernetesTunnelForm struct {
+	form       *Form
+	targetType *widgets.DropDown
+
+	onChange func(tunnel *domain.KubernetesTunnel)
+}
+
+func NewKubernetesTunnelForm(theme *chapartheme.Theme) *KubernetesTunnelForm {
+	k := &KubernetesTunnelForm{
+		form: NewForm([]*Field{
+			{Label: k8sTarget},
+			{Label: k8sContext},
+			{Label: k8sNamespace},
+			{Label: k8sLocalPort},
+			{Label: k8sTargetPort},
+			{Label: k8sIdleTimeout},
+		}),
+		targetType: widgets.NewDropDown(theme,
+			widgets.NewDropDownOption("Pod").WithValue(domain.KubernetesTargetPod),
+			widgets.NewDropDownOption("Service").WithValue(domain.KubernetesTargetService),
+		),
+	}
+	k.targetType.MinWidth = unit.Dp(150)
+	return k
+}
+
+func (k *KubernetesTunnelForm) SetTunnel(tunnel *domain.KubernetesTunnel) {
+	if tunnel == nil {
+		tunnel = &domain.KubernetesTunnel{}
+	}
+
+	idle := ""
+	if tunnel.IdleTimeout > 0 {
+		idle = tunnel.IdleTimeout.String()
+	}
+
+	k.form.SetValues(map[string]string{
+		k8sTarget:      tunnel.Target,
+		k8sContext:     tunnel.Context,
+		k8sNamespace:   tunnel.Namespace,
+		k8sLocalPort:   portText(tunnel.LocalPort),
+		k8sTargetPort:  portText(tunnel.TargetPort),
+		k8sIdleTimeout: idle,
+	})
+
+	if tunnel.TargetType == domain.KubernetesTargetService {
+		k.targetType.SetSelectedByValue(domain.KubernetesTargetService)
+	} else {
+		k.targetType.SetSelectedByValue(domain.KubernetesTargetPod)
+	}
+}
+
+func (k *KubernetesTunnelForm) SetOnChange(f func(tunnel *domain.KubernetesTunnel)) {
+	k.onChange = f
+	k.form.SetOnChange(func(values map[string]string) {
+		k.triggerChanged()
+	})
+
+	k.targetType.SetOnChanged(func(value string) {
+		k.triggerChanged()
+	})
+}
+
+func (k *KubernetesTunnelForm) triggerChanged() {
+	if k.onChange != nil {
+		k.onChange(k.getTunnel())
+	}
+}
+
+// getTunnel returns the tunnel of the form, invalid ports and durations are left as zero.
+func (k *KubernetesTunnelForm) getTunnel() *domain.KubernetesTunnel {
+	values := k.form.GetValues()
+	port := func(label string) int {
+		p, _ := strconv.Atoi(values[label])
+		return p
+	}
+
+	idle, _ := time.ParseDuration(values[k8sIdleTimeout])
+	return &domain.KubernetesTunnel{
+		Target:      values[k8sTarget],
+		TargetType:  k.targetType.GetSelected().Value,
+		Context:     values[k8sContext],
+		Namespace:   values[k8sNamespace],
+		LocalPort:   port(k8sLocalPort),
+		TargetPort:  port(k8sTargetPort),
+		IdleTimeout: max(idle, 0),
+	}
+}
+
+func (k *KubernetesTunnelForm) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, material.Label(theme.Material(), unit.Sp(12), k8sDescription).Layout)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							gtx.Constraints.Min.X = gtx.Dp(85)
+							return material.Label(theme.Material(), theme.TextSize, "Type").Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return k.targetType.Layout(gtx, theme)
+						}),
+					)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return k.form.Layout(gtx, theme)
+			}),
+		)
+	})
+}
@@ -0,0 +1,349 @@
+package component
+
+import (
+	"sync"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+var postRequestSources = []struct {
+	title string
+	value string
+	hint  string
+}{
+	{"From Response", domain.PostRequestSetFromResponseBody, "$.path"},
+	{"From Header", domain.PostRequestSetFromResponseHeader, "Header name"},
+	{"From Cookie", domain.PostRequestSetFromResponseCookie, "Cookie name"},
+}
+
+var postRequestScopes = []struct {
+	title string
+	value string
+}{
+	{"Environment", domain.PostRequestScopeEnvironment},
+	{"Collection", domain.PostRequestScopeCollection},
+	{"Global", domain.PostRequestScopeGlobal},
+}
+
+// PostRequestRules is the editor of the rules of a post request, every row stores a value of the response in a variable.
+type PostRequestRules struct {
+	mx    *sync.Mutex
+	items []*postRequestRuleItem
+
+	theme     *chapartheme.Theme
+	addButton *widgets.IconButton
+	list      *widget.List
+
+	onChange func(rules []domain.PostRequestSet)
+}
+
+type postRequestRuleItem struct {
+	rule    domain.PostRequestSet
+	preview string
+
+	targetEditor     widget.Editor
+	statusCodeEditor widget.Editor
+	fromKeyEditor    widget.Editor
+	fromDropDown     *widgets.DropDown
+	scopeDropDown    *widgets.DropDown
+	deleteButton     widget.Clickable
+}
+
+func NewPostRequestRules(theme *chapartheme.Theme) *PostRequestRules {
+	r := &PostRequestRules{
+		mx:    &sync.Mutex{},
+		theme: theme,
+		addButton: &widgets.IconButton{
+			Icon:      widgets.PlusIcon,
+			Size:      unit.Dp(20),
+			Clickable: &widget.Clickable{},
+		},
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	r.addButton.OnClick = func() {
+		r.mx.Lock()
+		r.items = append(r.items, r.newItem(domain.PostRequestSet{
+			StatusCode: "2xx",
+			From:       domain.PostRequestSetFromResponseBody,
+			Scope:      domain.PostRequestScopeEnvironment,
+		}))
+		r.mx.Unlock()
+		r.triggerChanged()
+	}
+
+	return r
+}
+
+func (r *PostRequestRules) newItem(rule domain.PostRequestSet) *postRequestRuleItem {
+	// rules without a scope store their values in the environment
+	if rule.Scope == "" {
+		rule.Scope = domain.PostRequestScopeEnvironment
+	}
+
+	item := &postRequestRuleItem{
+		rule:          rule,
+		fromDropDown:  widgets.NewDropDown(r.theme),
+		scopeDropDown: widgets.NewDropDown(r.theme),
+	}
+
+	item.targetEditor.SingleLine = true
+	item.targetEditor.SetText(rule.Target)
+	item.statusCodeEditor.SingleLine = true
+	item.statusCodeEditor.SetText(rule.StatusCode)
+	item.fromKeyEditor.SingleLine = true
+	item.fromKeyEditor.SetText(rule.FromKey)
+
+	sources := make([]*widgets.DropDownOption, 0, len(postRequestSources))
+	for _, s := range postRequestSources {
+		sources = append(sources, widgets.NewDropDownOption(s.title).WithValue(s.value))
+	}
+	item.fromDropDown.SetOptions(sources...)
+	item.fromDropDown.SetSelectedByValue(rule.From)
+	item.fromDropDown.MinWidth = unit.Dp(130)
+
+	scopes := make([]*widgets.DropDownOption, 0, len(postRequestScopes))
+	for _, s := range postRequestScopes {
+		scopes = append(scopes, widgets.NewDropDownOption(s.title).WithValue(s.value))
+	}
+	item.scopeDropDown.SetOptions(scopes...)
+	item.scopeDropDown.SetSelectedByValue(rule.Scope)
+	item.scopeDropDown.MinWidth = unit.Dp(110)
+	return item
+}
+
+func (r *PostRequestRules) SetRules(rules []domain.PostRequestSet) {
+	items := make([]*postRequestRuleItem, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, r.newItem(rule))
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.items = items
+}
+
+func (r *PostRequestRules) GetRules() []domain.PostRequestSet {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	out := make([]domain.PostRequestSet, 0, len(r.items))
+	for _, item := range r.items {
+		out = append(out, item.rule)
+	}
+	return out
+}
+
+// SetPreviews sets the values the rules extract from the last response, in the order of the rules.
+func (r *PostRequestRules) SetPreviews(previews []string) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	for i, item := range r.items {
+		item.preview = ""
+		if i < len(previews) {
+			item.preview = previews[i]
+		}
+	}
+}
+
+func (r *PostRequestRules) SetOnChange(f func(rules []domain.PostRequestSet)) {
+	r.onChange = f
+}
+
+func (r *PostRequestRules) triggerChanged() {
+	if r.onChange != nil {
+		r.onChange(r.GetRules())
+	}
+}
+
+// update applies the changes of the row widgets to the rule and reports whether there was any.
+func (i *postRequestRuleItem) update(gtx layout.Context) bool {
+	changed := false
+
+	// dropdowns report changes on another goroutine, so their selection is compared on every frame instead
+	if selected := i.fromDropDown.GetSelected().Value; selected != i.rule.From {
+		i.rule.From = selected
+		changed = true
+	}
+
+	if selected := i.scopeDropDown.GetSelected().Value; selected != i.rule.Scope {
+		i.rule.Scope = selected
+		changed = true
+	}
+
+	editors := []struct {
+		editor *widget.Editor
+		value  *string
+	}{
+		{&i.targetEditor, &i.rule.Target},
+		{&i.statusCodeEditor, &i.rule.StatusCode},
+		{&i.fromKeyEditor, &i.rule.FromKey},
+	}
+
+	for _, e := range editors {
+		for {
+			event, ok := e.editor.Update(gtx)
+			if !ok {
+				break
+			}
+			if _, ok := event.(widget.ChangeEvent); ok {
+				*e.value = e.editor.Text()
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+func (i *postRequestRuleItem) fromKeyHint() string {
+	for _, s := range postRequestSources {
+		if s.value == i.rule.From {
+			return s.hint
+		}
+	}
+	return "Path/Key"
+}
+
+func (r *PostRequestRules) itemLayout(gtx layout.Context, theme *chapartheme.Theme, item *postRequestRuleItem, last bool) layout.Dimensions {
+	leftPadding := layout.Inset{Left: unit.Dp(8)}
+
+	editor := func(ed *widget.Editor, hint string) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				e := material.Editor(theme.Material(), ed, hint)
+				e.SelectionColor = theme.TextSelectionColor
+				return e.Layout(gtx)
+			})
+		}
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, editor(&item.targetEditor, "Variable")),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							return item.fromDropDown.Layout(gtx, theme)
+						})
+					}),
+					layout.Flexed(1, editor(&item.fromKeyEditor, item.fromKeyHint())),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(70)
+						gtx.Constraints.Max.X = gtx.Constraints.Min.X
+						return editor(&item.statusCodeEditor, "2xx")(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return leftPadding.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							return item.scopeDropDown.Layout(gtx, theme)
+						})
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						ib := widgets.IconButton{
+							Icon:      widgets.DeleteIcon,
+							Size:      unit.Dp(20),
+							Color:     theme.TextColor,
+							Clickable: &item.deleteButton,
+						}
+						return ib.Layout(gtx, theme)
+					}),
+				)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if item.preview == "" {
+				return layout.Dimensions{}
+			}
+
+			return layout.Inset{Left: unit.Dp(8), Bottom: unit.Dp(4)}.Layout(gtx,
+				material.Label(theme.Material(), unit.Sp(12), "Preview: "+item.preview).Layout,
+			)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if last {
+				return layout.Dimensions{}
+			}
+			return widgets.DrawLine(gtx, theme.TableBorderColor, unit.Dp(1), unit.Dp(gtx.Constraints.Max.X))
+		}),
+	)
+}
+
+func (r *PostRequestRules) handleChanges(gtx layout.Context) {
+	r.mx.Lock()
+	changed := false
+	items := r.items[:0:0]
+	for _, item := range r.items {
+		if item.deleteButton.Clicked(gtx) {
+			changed = true
+			continue
+		}
+
+		if item.update(gtx) {
+			changed = true
+		}
+		items = append(items, item)
+	}
+	r.items = items
+	r.mx.Unlock()
+
+	if changed {
+		r.triggerChanged()
+	}
+}
+
+func (r *PostRequestRules) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	r.handleChanges(gtx)
+
+	r.mx.Lock()
+	items := r.items
+	r.mx.Unlock()
+
+	return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(material.Label(theme.Material(), theme.TextSize, "Rules").Layout),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Left: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx,
+							material.Label(theme.Material(), unit.Sp(10), "Status codes can be classes like 2xx, leave empty to match every response").Layout,
+						)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							r.addButton.BackgroundColor = theme.Palette.Bg
+							r.addButton.Color = theme.TextColor
+							return r.addButton.Layout(gtx, theme)
+						})
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				border := widget.Border{
+					Color:        theme.TableBorderColor,
+					CornerRadius: unit.Dp(4),
+					Width:        unit.Dp(1),
+				}
+
+				return border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					if len(items) == 0 {
+						return layout.UniformInset(unit.Dp(10)).Layout(gtx, material.Label(theme.Material(), unit.Sp(14), "No rules").Layout)
+					}
+
+					return material.List(theme.Material(), r.list).Layout(gtx, len(items), func(gtx layout.Context, i int) layout.Dimensions {
+						return r.itemLayout(gtx, theme, items[i], i == len(items)-1)
+					})
+				})
+			}),
+		)
+	})
+}
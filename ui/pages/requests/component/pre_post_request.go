@@ -1,7 +1,7 @@
 package component
 
 import (
-	"strconv"
+	"time"
 
 	"gioui.org/layout"
 	"gioui.org/unit"
@@ -9,7 +9,6 @@ import (
 	"gioui.org/widget/material"
 	"github.com/chapar-rest/chapar/internal/domain"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
-	"github.com/chapar-rest/chapar/ui/keys"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
@@ -19,19 +18,16 @@ type PrePostRequest struct {
 
 	dropDownItems []Option
 
+	// timeout is the timeout of scripts, empty uses the default
+	timeout widget.Editor
+
 	onScriptChanged   func(script string)
 	onDropDownChanged func(selected string)
+	onTimeoutChanged  func(timeout time.Duration)
 
-	setEnvForm          *SetEnvForm
-	onSetEnvFormChanged func(statusCode int, item, from, fromKey string)
-}
-
-type SetEnvForm struct {
-	statusCodeEditor widget.Editor
-	targetEditor     widget.Editor
-	fromEditor       widget.Editor
-	fromDropDown     *widgets.DropDown
-	preview          string
+	rules            *PostRequestRules
+	sshTunnel        *SSHTunnelForm
+	kubernetesTunnel *KubernetesTunnelForm
 }
 
 const (
@@ -54,14 +50,10 @@ func NewPrePostRequest(options []Option, theme *chapartheme.Theme) *PrePostReque
 		dropDown:      widgets.NewDropDown(theme),
 		script:        widgets.NewCodeEditor("", "Python", theme),
 		dropDownItems: options,
-		setEnvForm: &SetEnvForm{
-			fromDropDown: widgets.NewDropDown(
-				theme,
-				widgets.NewDropDownOption("From Response").WithValue(domain.PostRequestSetFromResponseBody),
-				widgets.NewDropDownOption("From Header").WithValue(domain.PostRequestSetFromResponseHeader),
-				widgets.NewDropDownOption("From Cookie").WithValue(domain.PostRequestSetFromResponseCookie),
-			),
-		},
+		rules:         NewPostRequestRules(theme),
+		sshTunnel:     NewSSHTunnelForm(),
+
+		kubernetesTunnel: NewKubernetesTunnelForm(theme),
 	}
 
 	opts := make([]*widgets.DropDownOption, 0, len(options))
@@ -70,6 +62,7 @@ func NewPrePostRequest(options []Option, theme *chapartheme.Theme) *PrePostReque
 	}
 
 	p.dropDown.SetOptions(opts...)
+	p.timeout.SingleLine = true
 	return p
 }
 
@@ -91,26 +84,77 @@ func (p *PrePostRequest) SetCode(code string) {
 	p.script.SetCode(code)
 }
 
-func (p *PrePostRequest) SetPreview(preview string) {
-	p.setEnvForm.preview = preview
+// SetTimeout sets the timeout of the script, zero leaves the field empty to use the default.
+func (p *PrePostRequest) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		p.timeout.SetText("")
+		return
+	}
+	p.timeout.SetText(timeout.String())
 }
 
-func (p *PrePostRequest) SetPostRequestSetValues(set domain.PostRequestSet) {
-	p.setEnvForm.statusCodeEditor.SetText(strconv.Itoa(set.StatusCode))
-	p.setEnvForm.targetEditor.SetText(set.Target)
-	p.setEnvForm.fromEditor.SetText(set.FromKey)
-	p.setEnvForm.fromDropDown.SetSelectedByValue(set.From)
+// SetOnTimeoutChanged sets the callback of timeout changes, invalid durations are reported as zero.
+func (p *PrePostRequest) SetOnTimeoutChanged(f func(timeout time.Duration)) {
+	p.onTimeoutChanged = f
 }
 
-func (p *PrePostRequest) SetOnPostRequestSetChanged(f func(statusCode int, item, from, fromKey string)) {
-	p.onSetEnvFormChanged = f
-	p.setEnvForm.fromDropDown.SetOnChanged(func(selected string) {
-		statusCode, _ := strconv.Atoi(p.setEnvForm.statusCodeEditor.Text())
-		p.onSetEnvFormChanged(statusCode, p.setEnvForm.targetEditor.Text(), selected, p.setEnvForm.fromEditor.Text())
-	})
+func (p *PrePostRequest) isScript() bool {
+	switch p.dropDownItems[p.dropDown.SelectedIndex()].Type {
+	case TypeScript, TypeShellScript:
+		return true
+	}
+	return false
+}
+
+func (p *PrePostRequest) handleTimeoutChanges(gtx layout.Context) {
+	for {
+		event, ok := p.timeout.Update(gtx)
+		if !ok {
+			break
+		}
+
+		if _, ok := event.(widget.ChangeEvent); ok && p.onTimeoutChanged != nil {
+			timeout, err := time.ParseDuration(p.timeout.Text())
+			if err != nil || timeout < 0 {
+				timeout = 0
+			}
+			p.onTimeoutChanged(timeout)
+		}
+	}
+}
+
+// SetPreviews sets the values the rules extract from the last response.
+func (p *PrePostRequest) SetPreviews(previews []string) {
+	p.rules.SetPreviews(previews)
+}
+
+func (p *PrePostRequest) SetPostRequestRules(rules []domain.PostRequestSet) {
+	p.rules.SetRules(rules)
+}
+
+func (p *PrePostRequest) SetOnPostRequestRulesChanged(f func(rules []domain.PostRequestSet)) {
+	p.rules.SetOnChange(f)
+}
+
+func (p *PrePostRequest) SetSSHTunnel(tunnel *domain.SShTunnel) {
+	p.sshTunnel.SetTunnel(tunnel)
+}
+
+func (p *PrePostRequest) SetOnSSHTunnelChanged(f func(tunnel *domain.SShTunnel)) {
+	p.sshTunnel.SetOnChange(f)
+}
+
+func (p *PrePostRequest) SetKubernetesTunnel(tunnel *domain.KubernetesTunnel) {
+	p.kubernetesTunnel.SetTunnel(tunnel)
+}
+
+func (p *PrePostRequest) SetOnKubernetesTunnelChanged(f func(tunnel *domain.KubernetesTunnel)) {
+	p.kubernetesTunnel.SetOnChange(f)
 }
 
 func (p *PrePostRequest) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	p.handleTimeoutChanges(gtx)
+
 	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
 	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{
@@ -122,6 +166,19 @@ func (p *PrePostRequest) Layout(gtx layout.Context, theme *chapartheme.Theme) la
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 						return p.dropDown.Layout(gtx, theme)
 					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if !p.isScript() {
+							return layout.Dimensions{}
+						}
+
+						return layout.Inset{Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							gtx.Constraints.Min.X = gtx.Dp(150)
+							gtx.Constraints.Max.X = gtx.Constraints.Min.X
+							e := material.Editor(theme.Material(), &p.timeout, "Timeout, like 30s")
+							e.SelectionColor = theme.TextSelectionColor
+							return e.Layout(gtx)
+						})
+					}),
 				)
 			}),
 			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
@@ -129,113 +186,19 @@ func (p *PrePostRequest) Layout(gtx layout.Context, theme *chapartheme.Theme) la
 				selectedItem := p.dropDownItems[selectedIndex]
 
 				switch selectedItem.Type {
-				case TypeScript:
+				case TypeScript, TypeShellScript:
 					return layout.Inset{Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 						return p.script.Layout(gtx, theme, selectedItem.Hint)
 					})
 				case TypeSetEnv:
-					return p.SetEnvForm(gtx, theme)
+					return p.rules.Layout(gtx, theme)
+				case TypeSSHTunnel:
+					return p.sshTunnel.Layout(gtx, theme)
+				case TypeK8sTunnel:
+					return p.kubernetesTunnel.Layout(gtx, theme)
 				}
 				return layout.Dimensions{}
 			}),
 		)
 	})
 }
-
-func (p *PrePostRequest) handleDataChange() {
-	if p.onSetEnvFormChanged != nil {
-		statusCode, _ := strconv.Atoi(p.setEnvForm.statusCodeEditor.Text())
-
-		p.onSetEnvFormChanged(statusCode, p.setEnvForm.targetEditor.Text(), p.setEnvForm.fromDropDown.GetSelected().Value, p.setEnvForm.fromEditor.Text())
-	}
-}
-
-func (p *PrePostRequest) enforceNumericEditor(editor *widget.Editor) {
-	if _, err := strconv.Atoi(editor.Text()); err != nil {
-		editor.SetText("0")
-	}
-}
-
-func (p *PrePostRequest) SetEnvForm(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
-	topButtonInset := layout.Inset{Top: unit.Dp(8), Bottom: unit.Dp(4)}
-
-	keys.OnEditorChange(gtx, &p.setEnvForm.statusCodeEditor, func() {
-		p.enforceNumericEditor(&p.setEnvForm.statusCodeEditor)
-		p.handleDataChange()
-	})
-
-	keys.OnEditorChange(gtx, &p.setEnvForm.targetEditor, func() {
-		p.handleDataChange()
-	})
-
-	keys.OnEditorChange(gtx, &p.setEnvForm.fromEditor, func() {
-		p.handleDataChange()
-	})
-
-	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return topButtonInset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				lb := &widgets.LabeledInput{
-					Label:          "Target Key",
-					SpaceBetween:   5,
-					MinEditorWidth: unit.Dp(150),
-					MinLabelWidth:  unit.Dp(80),
-					Editor:         &p.setEnvForm.targetEditor,
-				}
-				return lb.Layout(gtx, theme)
-			})
-		}),
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return topButtonInset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				lb := &widgets.LabeledInput{
-					Label:          "Status Code",
-					SpaceBetween:   5,
-					MinEditorWidth: unit.Dp(150),
-					MinLabelWidth:  unit.Dp(80),
-					Editor:         &p.setEnvForm.statusCodeEditor,
-				}
-				return lb.Layout(gtx, theme)
-			})
-		}),
-
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return layout.Flex{
-				Axis:      layout.Horizontal,
-				Alignment: layout.Middle,
-			}.Layout(gtx,
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					gtx.Constraints.Min.X = gtx.Dp(85)
-					return material.Label(theme.Material(), theme.TextSize, "From").Layout(gtx)
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					p.setEnvForm.fromDropDown.MinWidth = unit.Dp(162)
-					return topButtonInset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-						return p.setEnvForm.fromDropDown.Layout(gtx, theme)
-					})
-				}),
-			)
-		}),
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return topButtonInset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				lb := &widgets.LabeledInput{
-					Label:          "Path/Key",
-					SpaceBetween:   5,
-					MinEditorWidth: unit.Dp(150),
-					MinLabelWidth:  unit.Dp(80),
-					Editor:         &p.setEnvForm.fromEditor,
-				}
-				return lb.Layout(gtx, theme)
-			})
-		}),
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return topButtonInset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				return material.Label(theme.Material(), theme.TextSize, "Preview:").Layout(gtx)
-			})
-		}),
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return topButtonInset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				return material.Label(theme.Material(), theme.TextSize, p.setEnvForm.preview).Layout(gtx)
-			})
-		}),
-	)
-}
@@ -0,0 +1,150 @@
+package component
+
+import (
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const (
+	proxyHost     = "Host"
+	proxyUsername = "Username"
+	proxyPassword = "Password"
+	proxyBypass   = "Bypass"
+
+	proxyModeDefault = ""
+	proxyModeNone    = "none"
+)
+
+// ProxySettings edits a proxy, the default mode leaves it unset, like to use the workspace proxy while an environment is active.
+type ProxySettings struct {
+	Mode *widgets.DropDown
+	Form *Form
+
+	onChange func(proxy *domain.ProxySettings)
+}
+
+// NewProxySettings returns the editor of the proxy, defaultLabel names the mode which leaves it unset.
+func NewProxySettings(proxy *domain.ProxySettings, defaultLabel string, theme *chapartheme.Theme) *ProxySettings {
+	mode := widgets.NewDropDown(
+		theme,
+		widgets.NewDropDownOption(defaultLabel).WithValue(proxyModeDefault),
+		widgets.NewDropDownOption("No proxy").WithValue(proxyModeNone),
+		widgets.NewDropDownOption("HTTP").WithValue(domain.ProxyTypeHTTP),
+		widgets.NewDropDownOption("SOCKS5").WithValue(domain.ProxyTypeSOCKS5),
+	)
+	mode.MinWidth = unit.Dp(162)
+
+	p := &ProxySettings{
+		Mode: mode,
+		Form: NewForm([]*Field{
+			{Label: proxyHost, Value: ""},
+			{Label: proxyUsername, Value: ""},
+			{Label: proxyPassword, Value: ""},
+			{Label: proxyBypass, Value: ""},
+		}),
+	}
+
+	p.SetProxy(proxy)
+	return p
+}
+
+func (p *ProxySettings) SetProxy(proxy *domain.ProxySettings) {
+	if proxy == nil {
+		p.Mode.SetSelectedByValue(proxyModeDefault)
+		p.Form.SetValues(map[string]string{})
+		return
+	}
+
+	mode := proxy.Type
+	if !proxy.Enabled {
+		mode = proxyModeNone
+	} else if mode == "" {
+		mode = domain.ProxyTypeHTTP
+	}
+
+	p.Mode.SetSelectedByValue(mode)
+	p.Form.SetValues(map[string]string{
+		proxyHost:     proxy.Host,
+		proxyUsername: proxy.Username,
+		proxyPassword: proxy.Password,
+		proxyBypass:   strings.Join(proxy.Bypass, ", "),
+	})
+}
+
+func (p *ProxySettings) SetOnChange(f func(proxy *domain.ProxySettings)) {
+	p.onChange = f
+
+	p.Form.SetOnChange(func(values map[string]string) {
+		p.onChange(p.GetProxy())
+	})
+
+	p.Mode.SetOnChanged(func(value string) {
+		p.onChange(p.GetProxy())
+	})
+}
+
+// GetProxy returns the proxy of the form, or nil when the default mode is selected.
+func (p *ProxySettings) GetProxy() *domain.ProxySettings {
+	mode := p.Mode.GetSelected().Value
+	if mode == proxyModeDefault {
+		return nil
+	}
+
+	values := p.Form.GetValues()
+	proxy := &domain.ProxySettings{
+		Enabled:  mode != proxyModeNone,
+		Host:     strings.TrimSpace(values[proxyHost]),
+		Username: values[proxyUsername],
+		Password: values[proxyPassword],
+	}
+
+	if proxy.Enabled {
+		proxy.Type = mode
+	}
+
+	for _, entry := range strings.Split(values[proxyBypass], ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			proxy.Bypass = append(proxy.Bypass, entry)
+		}
+	}
+
+	return proxy
+}
+
+func (p *ProxySettings) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Start}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							gtx.Constraints.Min.X = gtx.Dp(130)
+							return material.Label(theme.Material(), theme.TextSize, "Proxy").Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return p.Mode.Layout(gtx, theme)
+						}),
+					)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				switch p.Mode.GetSelected().Value {
+				case proxyModeDefault, proxyModeNone:
+					return layout.Dimensions{}
+				}
+				return p.Form.Layout(gtx, theme)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				l := material.Label(theme.Material(), theme.TextSize, "Bypass is a comma separated list of hosts, domains (e.g. .internal.io) and CIDR ranges reached without the proxy")
+				l.Color = theme.TextColor
+				return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, l.Layout)
+			}),
+		)
+	})
+}
@@ -0,0 +1,410 @@
+package component
+
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/runner"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+var runPassedColor = color.NRGBA{R: 0x43, G: 0xa0, B: 0x47, A: 0xff}
+
+// Runner holds the options of a run of a collection or a request and shows the report of the results as they arrive.
+type Runner struct {
+	mx *sync.Mutex
+
+	// hint is shown until the first run
+	hint string
+
+	iterationsEditor widget.Editor
+	delayEditor      widget.Editor
+	stopOnFailure    widget.Bool
+
+	// dataFile is the csv or json file whose rows are the variables of the iterations
+	dataFile        string
+	dataFileButton  widget.Clickable
+	clearDataButton widget.Clickable
+
+	runButton  widget.Clickable
+	stopButton widget.Clickable
+
+	running bool
+	results []runner.Result
+	report  *runner.Report
+	err     error
+
+	list *widget.List
+
+	onRun            func(opts runner.Options)
+	onStop           func()
+	onSelectDataFile func()
+}
+
+func NewRunner(hint string) *Runner {
+	r := &Runner{
+		mx:   &sync.Mutex{},
+		hint: hint,
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	r.iterationsEditor.SingleLine = true
+	r.iterationsEditor.Filter = "0123456789"
+	r.iterationsEditor.SetText("1")
+	r.delayEditor.SingleLine = true
+	r.delayEditor.Filter = "0123456789"
+	r.delayEditor.SetText("0")
+	return r
+}
+
+func (r *Runner) SetOnRun(f func(opts runner.Options)) {
+	r.onRun = f
+}
+
+func (r *Runner) SetOnStop(f func()) {
+	r.onStop = f
+}
+
+func (r *Runner) SetOnSelectDataFile(f func()) {
+	r.onSelectDataFile = f
+}
+
+func (r *Runner) SetDataFile(path string) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.dataFile = path
+}
+
+// SetRunStarted clears the previous report.
+func (r *Runner) SetRunStarted() {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.running = true
+	r.results = nil
+	r.report = nil
+	r.err = nil
+}
+
+func (r *Runner) AddResult(result runner.Result) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.results = append(r.results, result)
+}
+
+func (r *Runner) SetRunFinished(report *runner.Report, err error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.running = false
+	r.report = report
+	r.err = err
+	if report != nil {
+		r.results = report.Results
+	}
+}
+
+func (r *Runner) options() runner.Options {
+	iterations, _ := strconv.Atoi(r.iterationsEditor.Text())
+	delay, _ := strconv.Atoi(r.delayEditor.Text())
+
+	r.mx.Lock()
+	dataFile := r.dataFile
+	r.mx.Unlock()
+
+	return runner.Options{
+		Iterations:    max(iterations, 1),
+		DataFile:      dataFile,
+		Delay:         time.Duration(delay) * time.Millisecond,
+		StopOnFailure: r.stopOnFailure.Value,
+	}
+}
+
+func (r *Runner) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	r.mx.Lock()
+	running := r.running
+	results := r.results
+	report := r.report
+	err := r.err
+	r.mx.Unlock()
+
+	if r.runButton.Clicked(gtx) && !running && r.onRun != nil {
+		go r.onRun(r.options())
+	}
+
+	if r.stopButton.Clicked(gtx) && running && r.onStop != nil {
+		r.onStop()
+	}
+
+	if r.dataFileButton.Clicked(gtx) && r.onSelectDataFile != nil {
+		r.onSelectDataFile()
+	}
+
+	if r.clearDataButton.Clicked(gtx) {
+		r.SetDataFile("")
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return r.optionsLayout(gtx, theme, running)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return material.Label(theme.Material(), theme.TextSize, runSummary(results, report, running, r.hint)).Layout(gtx)
+			})
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			if err != nil {
+				return Message(gtx, MessageTypeError, theme, err.Error())
+			}
+
+			return material.List(theme.Material(), r.list).Layout(gtx, len(results), func(gtx layout.Context, i int) layout.Dimensions {
+				// results are grouped by iteration under a header with the variables of the iteration
+				if i > 0 && results[i-1].Iteration == results[i].Iteration {
+					return r.resultLayout(gtx, theme, results[i])
+				}
+
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return iterationLayout(gtx, theme, results[i])
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return r.resultLayout(gtx, theme, results[i])
+					}),
+				)
+			})
+		}),
+	)
+}
+
+func (r *Runner) optionsLayout(gtx layout.Context, theme *chapartheme.Theme, running bool) layout.Dimensions {
+	field := func(label string, editor *widget.Editor) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Right: unit.Dp(15)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lb := &widgets.LabeledInput{
+					Label:          label,
+					SpaceBetween:   5,
+					MinEditorWidth: unit.Dp(60),
+					Editor:         editor,
+				}
+				return lb.Layout(gtx, theme)
+			})
+		})
+	}
+
+	r.mx.Lock()
+	dataFile := r.dataFile
+	r.mx.Unlock()
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return r.actionsLayout(gtx, theme, running, dataFile, field)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return r.dataFileLayout(gtx, theme, dataFile)
+			})
+		}),
+	)
+}
+
+func (r *Runner) dataFileLayout(gtx layout.Context, theme *chapartheme.Theme, dataFile string) layout.Dimensions {
+	text := "No data file, attach a csv or json file to run an iteration per row"
+	if dataFile != "" {
+		text = filepath.Base(dataFile) + ", every row is an iteration"
+	}
+
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			btn := widgets.Button(theme.Material(), &r.dataFileButton, widgets.FileFolderIcon, widgets.IconPositionStart, "Data File")
+			btn.Color = theme.ButtonTextColor
+			return btn.Layout(gtx, theme)
+		}),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+		layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, text).Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if dataFile == "" {
+				return layout.Dimensions{}
+			}
+
+			ib := widgets.IconButton{
+				Icon:      widgets.CloseIcon,
+				Size:      unit.Dp(20),
+				Color:     theme.TextColor,
+				Clickable: &r.clearDataButton,
+			}
+			return ib.Layout(gtx, theme)
+		}),
+	)
+}
+
+func (r *Runner) actionsLayout(gtx layout.Context, theme *chapartheme.Theme, running bool, dataFile string, field func(label string, editor *widget.Editor) layout.FlexChild) layout.Dimensions {
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			// the rows of the data file set the number of iterations
+			if dataFile != "" {
+				gtx = gtx.Disabled()
+			}
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, field("Iterations", &r.iterationsEditor))
+		}),
+		field("Delay (ms)", &r.delayEditor),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			ch := material.CheckBox(theme.Material(), &r.stopOnFailure, "Stop on failure")
+			ch.IconColor = theme.CheckBoxColor
+			return ch.Layout(gtx)
+		}),
+		layout.Flexed(1, layout.Spacer{}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if running {
+				btn := widgets.Button(theme.Material(), &r.stopButton, widgets.StopIcon, widgets.IconPositionStart, "Stop")
+				btn.Color = theme.ButtonTextColor
+				return btn.Layout(gtx, theme)
+			}
+
+			btn := widgets.Button(theme.Material(), &r.runButton, widgets.PlayIcon, widgets.IconPositionStart, "Run")
+			btn.Color = theme.ButtonTextColor
+			btn.Background = theme.SendButtonBgColor
+			return btn.Layout(gtx, theme)
+		}),
+	)
+}
+
+func runSummary(results []runner.Result, report *runner.Report, running bool, hint string) string {
+	if len(results) == 0 && report == nil {
+		if running {
+			return "Running..."
+		}
+		return hint
+	}
+
+	passed, failed, skipped := 0, 0, 0
+	for _, res := range results {
+		switch res.Status {
+		case runner.StatusPassed:
+			passed++
+		case runner.StatusFailed:
+			failed++
+		case runner.StatusSkipped:
+			skipped++
+		}
+	}
+
+	text := fmt.Sprintf("%d passed, %d failed, %d skipped", passed, failed, skipped)
+	switch {
+	case running:
+		text = "Running... " + text
+	case report != nil && report.Stopped:
+		text = fmt.Sprintf("Stopped after %s, %s", report.Duration.Round(time.Millisecond), text)
+	case report != nil:
+		text = fmt.Sprintf("Finished %d iterations in %s, %s", report.Iterations, report.Duration.Round(time.Millisecond), text)
+	}
+	return text
+}
+
+func iterationLayout(gtx layout.Context, theme *chapartheme.Theme, res runner.Result) layout.Dimensions {
+	text := fmt.Sprintf("Iteration %d", res.Iteration)
+	if len(res.Variables) > 0 {
+		names := make([]string, 0, len(res.Variables))
+		for k := range res.Variables {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		pairs := make([]string, 0, len(names))
+		for _, k := range names {
+			pairs = append(pairs, k+"="+res.Variables[k])
+		}
+		text += ", " + strings.Join(pairs, ", ")
+	}
+
+	return layout.Inset{Top: unit.Dp(10), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		lb := material.Label(theme.Material(), theme.TextSize, text)
+		lb.Font.Weight = font.Bold
+		return lb.Layout(gtx)
+	})
+}
+
+func (r *Runner) resultLayout(gtx layout.Context, theme *chapartheme.Theme, res runner.Result) layout.Dimensions {
+	status, statusColor := "PASS", runPassedColor
+	switch res.Status {
+	case runner.StatusFailed:
+		status, statusColor = "FAIL", theme.ErrorColor
+	case runner.StatusSkipped:
+		status, statusColor = "SKIP", theme.WarningColor
+	}
+
+	column := func(width unit.Dp, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Dp(width)
+			return material.Label(theme.Material(), theme.TextSize, text).Layout(gtx)
+		})
+	}
+
+	code := ""
+	if res.StatusCode != 0 {
+		code = strconv.Itoa(res.StatusCode)
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(50)
+					lb := material.Label(theme.Material(), theme.TextSize, status)
+					lb.Color = statusColor
+					return lb.Layout(gtx)
+				}),
+				column(70, res.Method),
+				layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, res.Name).Layout),
+				column(50, code),
+				column(90, res.Duration.Round(time.Millisecond).String()),
+			)
+		}),
+	}
+
+	if res.Message != "" {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Left: unit.Dp(50)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lb := material.Label(theme.Material(), theme.TextSize, res.Message)
+				lb.Color = theme.TextColor
+				return lb.Layout(gtx)
+			})
+		}))
+	}
+
+	for _, t := range res.TestResults {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			mark, markColor := "✓", runPassedColor
+			if !t.Passed {
+				mark, markColor = "✗", theme.ErrorColor
+			}
+
+			return layout.Inset{Left: unit.Dp(50)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lb := material.Label(theme.Material(), theme.TextSize, fmt.Sprintf("%s %s, %s", mark, t.Assertion, t.Message))
+				lb.Color = markColor
+				return lb.Layout(gtx)
+			})
+		}))
+	}
+
+	return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
@@ -0,0 +1,114 @@
+package component
+
+import (
+	"strconv"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+)
+
+const (
+	sshHost        = "Host"
+	sshPort        = "Port"
+	sshUser        = "User"
+	sshPassword    = "Password"
+	sshKeyPath     = "Key Path"
+	sshKnownHosts  = "Known Hosts"
+	sshLocalPort   = "Local Port"
+	sshTargetHost  = "Target Host"
+	sshTargetPort  = "Target Port"
+	sshDescription = "The request is sent through the server to the target, which defaults to the host and port of the request url. Leave the local port empty to pick a free one."
+)
+
+// SSHTunnelForm edits the ssh tunnel a request is sent through.
+type SSHTunnelForm struct {
+	form *Form
+
+	onChange func(tunnel *domain.SShTunnel)
+}
+
+func NewSSHTunnelForm() *SSHTunnelForm {
+	return &SSHTunnelForm{
+		form: NewForm([]*Field{
+			{Label: sshHost},
+			{Label: sshPort},
+			{Label: sshUser},
+			{Label: sshPassword},
+			{Label: sshKeyPath},
+			{Label: sshKnownHosts},
+			{Label: sshLocalPort},
+			{Label: sshTargetHost},
+			{Label: sshTargetPort},
+		}),
+	}
+}
+
+func (s *SSHTunnelForm) SetTunnel(tunnel *domain.SShTunnel) {
+	if tunnel == nil {
+		tunnel = &domain.SShTunnel{}
+	}
+
+	s.form.SetValues(map[string]string{
+		sshHost:       tunnel.Host,
+		sshPort:       portText(tunnel.Port),
+		sshUser:       tunnel.User,
+		sshPassword:   tunnel.Password,
+		sshKeyPath:    tunnel.KeyPath,
+		sshKnownHosts: tunnel.KnownHostsPath,
+		sshLocalPort:  portText(tunnel.LocalPort),
+		sshTargetHost: tunnel.TargetHost,
+		sshTargetPort: portText(tunnel.TargetPort),
+	})
+}
+
+func (s *SSHTunnelForm) SetOnChange(f func(tunnel *domain.SShTunnel)) {
+	s.onChange = f
+	s.form.SetOnChange(func(values map[string]string) {
+		if s.onChange != nil {
+			s.onChange(s.getTunnel(values))
+		}
+	})
+}
+
+// getTunnel returns the tunnel of the form values, invalid ports are left as zero.
+func (s *SSHTunnelForm) getTunnel(values map[string]string) *domain.SShTunnel {
+	port := func(label string) int {
+		p, _ := strconv.Atoi(values[label])
+		return p
+	}
+
+	return &domain.SShTunnel{
+		Host:           values[sshHost],
+		Port:           port(sshPort),
+		User:           values[sshUser],
+		Password:       values[sshPassword],
+		KeyPath:        values[sshKeyPath],
+		KnownHostsPath: values[sshKnownHosts],
+		LocalPort:      port(sshLocalPort),
+		TargetHost:     values[sshTargetHost],
+		TargetPort:     port(sshTargetPort),
+	}
+}
+
+func (s *SSHTunnelForm) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, material.Label(theme.Material(), unit.Sp(12), sshDescription).Layout)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return s.form.Layout(gtx, theme)
+			}),
+		)
+	})
+}
+
+func portText(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(port)
+}
@@ -6,6 +6,11 @@ import (
 
 	"gioui.org/layout"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/graphql"
+	"github.com/chapar-rest/chapar/internal/grpc"
+	"github.com/chapar-rest/chapar/internal/loadtest"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/runner"
 )
 
 const (
@@ -25,19 +30,76 @@ type Container interface {
 	HidePrompt()
 }
 
-type RestContainer interface {
-	SetHTTPResponse(response domain.HTTPResponseDetail)
-	GetHTTPResponse() *domain.HTTPResponseDetail
-	SetPostRequestSetPreview(preview string)
+// SendingContainer is a container which shows the progress of sending its request.
+type SendingContainer interface {
 	ShowSendingRequestLoading()
 	HideSendingRequestLoading()
+}
+
+// ResponseContainer is a container which sends http requests and shows their response.
+type ResponseContainer interface {
+	SendingContainer
+	SetHTTPResponse(response domain.HTTPResponseDetail)
+	GetHTTPResponse() *domain.HTTPResponseDetail
+	SetResponseHistory(responses []domain.HTTPResponse)
+	SetStreamStarted(code int, headers []domain.KeyValue)
+	AddStreamEvent(event rest.StreamEvent)
+}
+
+// LoadTestContainer is a container which load tests its request and shows the stats while the test runs.
+type LoadTestContainer interface {
+	SetLoadTestStarted()
+	SetLoadTestStats(stats loadtest.Stats)
+	SetLoadTestFinished(stats loadtest.Stats, err error)
+}
+
+type RestContainer interface {
+	ResponseContainer
+	SetPostRequestRulePreviews(previews []string)
 	SetQueryParams(params []domain.KeyValue)
 	SetPathParams(params []domain.KeyValue)
 	SetURL(url string)
-	SetPostRequestSetValues(set domain.PostRequestSet)
-	SetOnPostRequestSetChanged(f func(id string, statusCode int, item, from, fromKey string))
+	SetPostRequestRules(rules []domain.PostRequestSet)
+	SetOnPostRequestRulesChanged(f func(id string, rules []domain.PostRequestSet))
 	SetOnBinaryFileSelect(f func(id string))
 	SetBinaryBodyFilePath(filePath string)
 	SetOnFormDataFileSelect(f func(requestId, fieldId string))
 	AddFileToFormData(fieldId, filePath string)
 }
+
+type GraphQLContainer interface {
+	ResponseContainer
+	SetSchema(schema *graphql.Schema, err error)
+}
+
+type GRPCContainer interface {
+	SendingContainer
+	SetServices(services []domain.GRPCService, err error)
+	SetRequestTemplate(template string, err error)
+	SetGRPCResponse(response *grpc.Response, err error)
+	SetStreamOpened(clientStreaming bool)
+	AddStreamMessage(msg grpc.StreamMessage)
+	SetStreamError(err error)
+}
+
+// RunnerContainer is a container which runs its requests with iterations or a data file and shows the run report.
+type RunnerContainer interface {
+	SetRunDataFile(path string)
+	SetRunStarted()
+	AddRunResult(result runner.Result)
+	SetRunFinished(report *runner.Report, err error)
+}
+
+// CollectionContainer is a container which runs the requests of a collection and shows the run report.
+type CollectionContainer interface {
+	RunnerContainer
+	SetVariables(values []domain.KeyValue)
+}
+
+type WebSocketContainer interface {
+	SetConnecting()
+	SetConnected()
+	SetDisconnected(err error)
+	AddMessage(msg rest.WebSocketMessage)
+	AddError(err error)
+}
@@ -1,30 +1,47 @@
 package requests
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"gioui.org/io/clipboard"
 	"gioui.org/layout"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/grpc"
 	"github.com/chapar-rest/chapar/internal/importer"
+	"github.com/chapar-rest/chapar/internal/loadtest"
 	"github.com/chapar-rest/chapar/internal/notify"
 	"github.com/chapar-rest/chapar/internal/repository"
 	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/runner"
+	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/internal/state"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/explorer"
 	"github.com/chapar-rest/chapar/ui/widgets"
 )
 
+// fetchTimeout bounds fetches which are not requests of the user, like the graphql schema or the gRPC services.
+const fetchTimeout = 30 * time.Second
+
+const (
+	fetchGraphQLSchema = "graphql-schema"
+	fetchGRPCServices  = "grpc-services"
+	fetchGRPCTemplate  = "grpc-template"
+)
+
 type Controller struct {
 	model *state.Requests
 	view  *View
 
-	envState *state.Environments
+	envState     *state.Environments
+	historyState *state.ResponseHistory
 
 	repo repository.Repository
 
@@ -33,18 +50,46 @@ type Controller struct {
 	explorer *explorer.Explorer
 
 	restService *rest.Service
+	grpcService *grpc.Service
+	runner      *runner.Runner
+
+	// cancelFuncs holds the cancel function of in flight requests by request id
+	cancelFuncs *safemap.Map[context.CancelFunc]
+	// loadTests holds the cancel function of running load tests by request id, so sending the request does not stop them
+	loadTests *safemap.Map[context.CancelFunc]
+	// runs holds the cancel function of the runs of collections and requests by their id
+	runs *safemap.Map[context.CancelFunc]
+	// fetches holds the cancel function of the fetches of a request by the request id and the kind of the fetch
+	fetches *safemap.Map[context.CancelFunc]
+	// webSockets holds the open websocket connections by request id
+	webSockets *safemap.Map[*rest.WebSocketConn]
+	// grpcStreams holds the open gRPC streams by request id
+	grpcStreams *safemap.Map[*grpc.Stream]
+	// responseFiles holds the temp file of the last truncated response by request id
+	responseFiles *safemap.Map[string]
 }
 
-func NewController(view *View, repo repository.Repository, model *state.Requests, envState *state.Environments, explorer *explorer.Explorer, restService *rest.Service) *Controller {
+func NewController(view *View, repo repository.Repository, model *state.Requests, envState *state.Environments, historyState *state.ResponseHistory, explorer *explorer.Explorer, restService *rest.Service, grpcService *grpc.Service) *Controller {
 	c := &Controller{
-		view:     view,
-		model:    model,
-		repo:     repo,
-		envState: envState,
+		view:         view,
+		model:        model,
+		repo:         repo,
+		envState:     envState,
+		historyState: historyState,
 
 		explorer: explorer,
 
 		restService: restService,
+		grpcService: grpcService,
+		runner:      runner.New(model, restService),
+		cancelFuncs: safemap.New[context.CancelFunc](),
+		loadTests:   safemap.New[context.CancelFunc](),
+		runs:        safemap.New[context.CancelFunc](),
+		fetches:     safemap.New[context.CancelFunc](),
+		webSockets:  safemap.New[*rest.WebSocketConn](),
+		grpcStreams: safemap.New[*grpc.Stream](),
+
+		responseFiles: safemap.New[string](),
 	}
 
 	view.SetOnNewRequest(c.onNewRequest)
@@ -57,10 +102,26 @@ func NewController(view *View, repo repository.Repository, model *state.Requests
 	view.SetOnDataChanged(c.onDataChanged)
 	view.SetOnSave(c.onSave)
 	view.SetOnSubmit(c.onSubmit)
+	view.SetOnCancel(c.onCancel)
 	view.SetOnCopyResponse(c.onCopyResponse)
+	view.SetOnSaveResponse(c.onSaveResponse)
+	view.SetOnClearResponseHistory(c.onClearResponseHistory)
 	view.SetOnBinaryFileSelect(c.onSelectBinaryFile)
-	view.SetOnPostRequestSetChanged(c.onPostRequestSetChanged)
+	view.SetOnPostRequestRulesChanged(c.onPostRequestRulesChanged)
 	view.SetOnFormDataFileSelect(c.onFormDataFileSelect)
+	view.SetOnFetchGraphQLSchema(c.onFetchGraphQLSchema)
+	view.SetOnWebSocketSend(c.onWebSocketSend)
+	view.SetOnGRPCReloadServices(c.onGRPCReloadServices)
+	view.SetOnGRPCRequestTemplate(c.onGRPCRequestTemplate)
+	view.SetOnGRPCSend(c.onGRPCSend)
+	view.SetOnGRPCCloseSend(c.onGRPCCloseSend)
+	view.SetOnRun(c.onRun)
+	view.SetOnStopRun(c.onStopRun)
+	view.SetOnSelectRunDataFile(c.onSelectRunDataFile)
+	view.SetOnCollectionVariablesChanged(c.onCollectionVariablesChanged)
+	view.SetOnLoadTest(c.onLoadTest)
+	view.SetOnStopLoadTest(c.onStopLoadTest)
+	model.AddCollectionChangeListener(c.onCollectionChange)
 	return c
 }
 
@@ -106,7 +167,7 @@ func (c *Controller) onFormDataFileSelect(requestId, fieldId string) {
 	}, "")
 }
 
-func (c *Controller) onPostRequestSetChanged(id string, statusCode int, item, from, fromKey string) {
+func (c *Controller) onPostRequestRulesChanged(id string, rules []domain.PostRequestSet) {
 	req := c.model.GetRequest(id)
 	if req == nil {
 		return
@@ -116,65 +177,61 @@ func (c *Controller) onPostRequestSetChanged(id string, statusCode int, item, fr
 	clone := req.Clone()
 	clone.MetaData.ID = id
 
-	clone.Spec.HTTP.Request.PostRequest.PostRequestSet = domain.PostRequestSet{
-		Target:     item,
-		StatusCode: statusCode,
-		From:       from,
-		FromKey:    fromKey,
-	}
+	// the rules replace the single rule of requests saved by older versions
+	clone.Spec.HTTP.Request.PostRequest.PostRequestSet = domain.PostRequestSet{}
+	clone.Spec.HTTP.Request.PostRequest.Rules = rules
 	c.onRequestDataChanged(id, clone)
 
+	c.setPostRequestPreviews(id, rules)
+}
+
+// setPostRequestPreviews shows the values the rules would extract from the last response of the request.
+func (c *Controller) setPostRequestPreviews(id string, rules []domain.PostRequestSet) {
 	responseData := c.view.GetHTTPResponse(id)
-	if responseData == nil {
+	if responseData == nil || responseData.Response == "" {
 		return
 	}
 
-	if responseData.Response == "" {
-		return
+	previews := make([]string, len(rules))
+	for i, rule := range rules {
+		switch rule.From {
+		case domain.PostRequestSetFromResponseBody:
+			previews[i] = previewFromResponse(responseData, rule.FromKey)
+		case domain.PostRequestSetFromResponseHeader:
+			previews[i] = previewFromKeyValues(responseData.Headers, rule.FromKey)
+		case domain.PostRequestSetFromResponseCookie:
+			previews[i] = previewFromKeyValues(responseData.Cookies, rule.FromKey)
+		}
 	}
 
-	switch clone.Spec.HTTP.Request.PostRequest.PostRequestSet.From {
-	case domain.PostRequestSetFromResponseBody:
-		c.setPreviewFromResponse(id, responseData, fromKey)
-	case domain.PostRequestSetFromResponseHeader:
-		c.setPreviewFromHeader(id, responseData, fromKey)
-	case domain.PostRequestSetFromResponseCookie:
-		c.setPreviewFromCookie(id, responseData, fromKey)
-	}
+	c.view.SetPostRequestRulePreviews(id, previews)
 }
 
-func (c *Controller) setPreviewFromResponse(id string, responseData *domain.HTTPResponseDetail, fromKey string) {
+func previewFromResponse(responseData *domain.HTTPResponseDetail, fromKey string) string {
+	if fromKey == "" {
+		return ""
+	}
+
 	resp, err := rest.GetJSONPATH(responseData.Response, fromKey)
 	if err != nil {
 		fmt.Println("failed to get data from response", err)
-		return
+		return ""
 	}
 
 	if resp == nil {
-		return
-	}
-
-	if result, ok := resp.(string); ok {
-		c.view.SetPostRequestSetPreview(id, result)
+		return ""
 	}
-}
 
-func (c *Controller) setPreviewFromHeader(id string, responseData *domain.HTTPResponseDetail, fromKey string) {
-	for _, header := range responseData.Headers {
-		if header.Key == fromKey {
-			c.view.SetPostRequestSetPreview(id, header.Value)
-			return
-		}
-	}
+	return rest.FormatValue(resp)
 }
 
-func (c *Controller) setPreviewFromCookie(id string, responseData *domain.HTTPResponseDetail, fromKey string) {
-	for _, cookie := range responseData.Cookies {
-		if cookie.Key == fromKey {
-			c.view.SetPostRequestSetPreview(id, cookie.Value)
-			return
+func previewFromKeyValues(values []domain.KeyValue, key string) string {
+	for _, kv := range values {
+		if kv.Key == key {
+			return kv.Value
 		}
 	}
+	return ""
 }
 
 func (c *Controller) onTitleChanged(id string, title, containerType string) {
@@ -194,8 +251,107 @@ func (c *Controller) onSave(id string) {
 }
 
 func (c *Controller) onSubmit(id, containerType string) {
-	if containerType == TypeRequest {
-		c.onSubmitRequest(id)
+	if containerType != TypeRequest {
+		return
+	}
+
+	if req := c.model.GetRequest(id); req != nil {
+		switch {
+		case req.Spec.WebSocket != nil:
+			c.connectWebSocket(id)
+			return
+		case req.Spec.GRPC != nil:
+			c.onInvokeGRPC(id)
+			return
+		}
+	}
+
+	c.onSubmitRequest(id)
+}
+
+func (c *Controller) onCancel(id string) {
+	if cancel, ok := c.cancelFuncs.Get(id); ok {
+		cancel()
+	}
+
+	for _, kind := range []string{fetchGraphQLSchema, fetchGRPCServices, fetchGRPCTemplate} {
+		if cancel, ok := c.fetches.Get(id + "/" + kind); ok {
+			cancel()
+			c.fetches.Delete(id + "/" + kind)
+		}
+	}
+
+	c.closeWebSocket(id)
+}
+
+// fetch calls f with a context bound by fetchTimeout, which is canceled when the request is canceled or its tab is closed.
+// a previous fetch of the same kind is canceled, like when the services are reloaded while they are fetched.
+func (c *Controller) fetch(id, kind string, f func(ctx context.Context)) {
+	key := id + "/" + kind
+	if cancel, ok := c.fetches.Get(key); ok {
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	c.fetches.Set(key, cancel)
+	defer cancel()
+
+	f(ctx)
+}
+
+func (c *Controller) connectWebSocket(id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelFuncs.Set(id, cancel)
+	defer func() {
+		cancel()
+		c.cancelFuncs.Delete(id)
+	}()
+
+	c.view.SetWebSocketConnecting(id)
+
+	conn, err := c.restService.ConnectWebSocket(ctx, id, c.activeEnvironmentID())
+	if err != nil {
+		c.view.SetWebSocketDisconnected(id, err)
+		return
+	}
+
+	c.webSockets.Set(id, conn)
+	c.view.SetWebSocketConnected(id)
+
+	go func() {
+		err := conn.Listen(func(msg rest.WebSocketMessage) {
+			c.view.AddWebSocketMessage(id, msg)
+		})
+
+		c.webSockets.Delete(id)
+		c.view.SetWebSocketDisconnected(id, err)
+	}()
+}
+
+func (c *Controller) onWebSocketSend(id, messageType, data string) {
+	conn, ok := c.webSockets.Get(id)
+	if !ok {
+		c.view.AddWebSocketError(id, fmt.Errorf("not connected"))
+		return
+	}
+
+	msg, err := conn.Send(messageType, data)
+	if err != nil {
+		c.view.AddWebSocketError(id, err)
+		return
+	}
+
+	c.view.AddWebSocketMessage(id, msg)
+}
+
+func (c *Controller) closeWebSocket(id string) {
+	conn, ok := c.webSockets.Get(id)
+	if !ok {
+		return
+	}
+
+	if err := conn.Close(); err != nil {
+		fmt.Println("failed to close websocket", err)
 	}
 }
 
@@ -208,36 +364,360 @@ func (c *Controller) onCopyResponse(gtx layout.Context, dataType, data string) {
 }
 
 func (c *Controller) onSubmitRequest(id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelFuncs.Set(id, cancel)
+	defer func() {
+		cancel()
+		c.cancelFuncs.Delete(id)
+	}()
+
 	c.view.SetSendingRequestLoading(id)
 	defer c.view.SetSendingRequestLoaded(id)
 
-	var envID = ""
-	activeEnvironment := c.envState.GetActiveEnvironment()
-	if activeEnvironment != nil {
-		envID = activeEnvironment.MetaData.ID
-	}
-
-	res, err := c.restService.SendRequest(id, envID)
+	c.removeResponseFile(id)
+	res, err := c.restService.SendStreamingRequest(ctx, id, c.activeEnvironmentID(), &rest.StreamHandler{
+		OnStart: func(res *rest.Response) {
+			c.view.SetStreamStarted(id, res.StatusCode, mapToKeyValue(res.Headers))
+		},
+		OnEvent: func(event rest.StreamEvent) {
+			c.view.AddStreamEvent(id, event)
+		},
+	})
 	if err != nil {
-		c.view.SetHTTPResponse(id, domain.HTTPResponseDetail{
-			Error: err,
-		})
+		detail := domain.HTTPResponseDetail{Error: err}
+		c.view.SetHTTPResponse(id, detail)
+		c.recordResponse(id, detail)
 		return
 	}
 
+	if res.BodyFile != "" {
+		c.responseFiles.Set(id, res.BodyFile)
+	}
+
 	resp := string(res.Body)
 	if res.IsJSON {
 		resp = res.JSON
 	}
 
-	c.view.SetHTTPResponse(id, domain.HTTPResponseDetail{
-		Response:   resp,
-		Headers:    mapToKeyValue(res.Headers),
-		Cookies:    cookieToKeyValue(res.Cookies),
-		StatusCode: res.StatusCode,
-		Duration:   res.TimePassed,
-		Size:       len(res.Body),
+	// binary bodies are not shown as text
+	if res.Binary {
+		resp = ""
+	}
+
+	detail := domain.HTTPResponseDetail{
+		Response:    resp,
+		Headers:     mapToKeyValue(res.Headers),
+		Cookies:     cookieToKeyValue(res.Cookies),
+		StatusCode:  res.StatusCode,
+		Duration:    res.TimePassed,
+		Timings:     res.Timings,
+		Size:        int(res.Size),
+		ContentType: res.ContentType,
+		Body:        res.Body,
+		Binary:      res.Binary,
+		Truncated:   res.Truncated,
+		BodyFile:    res.BodyFile,
+		TestResults: res.TestResults,
+	}
+
+	c.view.SetHTTPResponse(id, detail)
+	c.recordResponse(id, detail)
+
+	if req := c.model.GetRequest(id); req != nil && req.Spec.HTTP != nil {
+		c.setPostRequestPreviews(id, req.Spec.HTTP.Request.PostRequest.GetRules())
+	}
+}
+
+// onRun runs the requests of the collection, or the request, with the given id against the active environment,
+// reporting each result as it arrives.
+func (c *Controller) onRun(id string, opts runner.Options) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.runs.Set(id, cancel)
+	defer func() {
+		cancel()
+		c.runs.Delete(id)
+	}()
+
+	opts.EnvironmentID = c.activeEnvironmentID()
+	onResult := func(result runner.Result) {
+		c.view.AddRunResult(id, result)
+	}
+
+	c.view.SetRunStarted(id)
+
+	var report *runner.Report
+	var err error
+	if c.model.GetCollection(id) != nil {
+		report, err = c.runner.Run(ctx, id, opts, onResult)
+	} else {
+		report, err = c.runner.RunRequest(ctx, id, opts, onResult)
+	}
+
+	if err != nil {
+		fmt.Println("failed to run", err)
+	}
+
+	c.view.SetRunFinished(id, report, err)
+}
+
+func (c *Controller) onStopRun(id string) {
+	if cancel, ok := c.runs.Get(id); ok {
+		cancel()
+	}
+}
+
+func (c *Controller) onSelectRunDataFile(id string) {
+	c.explorer.ChoseFile(func(result explorer.Result) {
+		if result.Error != nil {
+			fmt.Println("failed to get file", result.Error)
+			return
+		}
+		if result.FilePath == "" {
+			return
+		}
+		c.view.SetRunDataFile(id, result.FilePath)
+	}, "csv", "json")
+}
+
+// onLoadTest load tests the request against the active environment, showing the stats while the test runs.
+func (c *Controller) onLoadTest(id string, opts loadtest.Options) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.loadTests.Set(id, cancel)
+	defer func() {
+		cancel()
+		c.loadTests.Delete(id)
+	}()
+
+	c.view.SetLoadTestStarted(id)
+
+	client, err := c.restService.NewLoadTestClient(id, c.activeEnvironmentID(), opts.Concurrency)
+	if err != nil {
+		fmt.Println("failed to load test request", err)
+		c.view.SetLoadTestFinished(id, loadtest.Stats{}, err)
+		return
+	}
+	defer client.Close()
+
+	stats, err := loadtest.Run(ctx, client.Send, opts, func(stats loadtest.Stats) {
+		c.view.SetLoadTestStats(id, stats)
 	})
+	c.view.SetLoadTestFinished(id, stats, err)
+}
+
+func (c *Controller) onStopLoadTest(id string) {
+	if cancel, ok := c.loadTests.Get(id); ok {
+		cancel()
+	}
+}
+
+// recordResponse adds the response to the history of the request and shows the updated history.
+func (c *Controller) recordResponse(id string, detail domain.HTTPResponseDetail) {
+	environment := ""
+	if env := c.envState.GetActiveEnvironment(); env != nil {
+		environment = env.MetaData.Name
+	}
+
+	if err := c.historyState.AddResponse(id, domain.NewHTTPResponse(detail, environment, time.Now())); err != nil {
+		fmt.Println("failed to add response to history", err)
+		return
+	}
+
+	c.showResponseHistory(id, false)
+}
+
+// showResponseHistory shows the history of the request, restore also shows the newest response as the response of the request.
+func (c *Controller) showResponseHistory(id string, restore bool) {
+	responses, err := c.historyState.GetResponses(id)
+	if err != nil {
+		fmt.Println("failed to get response history", err)
+		return
+	}
+
+	c.view.SetResponseHistory(id, responses)
+	if restore && len(responses) > 0 {
+		c.view.SetHTTPResponse(id, responses[0].ToDetail())
+	}
+}
+
+func (c *Controller) onClearResponseHistory(id string) {
+	if err := c.historyState.ClearResponses(id); err != nil {
+		fmt.Println("failed to clear response history", err)
+		return
+	}
+
+	c.view.SetResponseHistory(id, nil)
+}
+
+// closeRequest stops what the request is doing and removes its response file, like when its tab is closed or it is deleted.
+// load tests and runs are stopped too as there is no tab left to stop them.
+func (c *Controller) closeRequest(id string) {
+	c.onCancel(id)
+	c.onStopLoadTest(id)
+	c.onStopRun(id)
+	c.removeResponseFile(id)
+}
+
+// removeResponseFile removes the temp file of the previous response of the request, if any.
+func (c *Controller) removeResponseFile(id string) {
+	file, ok := c.responseFiles.Get(id)
+	if !ok {
+		return
+	}
+
+	c.responseFiles.Delete(id)
+	if err := os.Remove(file); err != nil {
+		fmt.Println("failed to remove response file", err)
+	}
+}
+
+// RemoveResponseFiles removes the temp files of the truncated responses, it should be called when the app exits.
+func (c *Controller) RemoveResponseFiles() {
+	for _, id := range c.responseFiles.Keys() {
+		c.removeResponseFile(id)
+	}
+}
+
+func (c *Controller) onSaveResponse(id string) {
+	res := c.view.GetHTTPResponse(id)
+	if res == nil {
+		return
+	}
+
+	name := "response"
+	if req := c.model.GetRequest(id); req != nil {
+		name = req.MetaData.Name
+	}
+
+	if exts, err := mime.ExtensionsByType(res.ContentType); err == nil && len(exts) > 0 {
+		name += exts[0]
+	}
+
+	c.explorer.SaveFile(name, func(w io.Writer) error {
+		// the whole body of truncated responses is only in the file
+		if res.BodyFile == "" {
+			_, err := w.Write(res.Body)
+			return err
+		}
+
+		file, err := os.Open(res.BodyFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(w, file)
+		return err
+	}, func(result explorer.Result) {
+		if result.Error != nil {
+			fmt.Println("failed to save response", result.Error)
+			notify.Send("Failed to save the response", 2*time.Second)
+			return
+		}
+
+		notify.Send("Response saved", 2*time.Second)
+	})
+}
+
+func (c *Controller) onFetchGraphQLSchema(id string) {
+	c.fetch(id, fetchGraphQLSchema, func(ctx context.Context) {
+		schema, err := c.restService.FetchGraphQLSchema(ctx, id, c.activeEnvironmentID())
+		c.view.SetGraphQLSchema(id, schema, err)
+	})
+}
+
+func (c *Controller) onInvokeGRPC(id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelFuncs.Set(id, cancel)
+	defer func() {
+		cancel()
+		c.cancelFuncs.Delete(id)
+	}()
+
+	c.view.SetSendingRequestLoading(id)
+	defer c.view.SetSendingRequestLoaded(id)
+
+	if req := c.model.GetRequest(id); req != nil {
+		if m, ok := req.Spec.GRPC.FindMethod(req.Spec.GRPC.Method); ok && (m.IsClientStreaming || m.IsServerStreaming) {
+			c.openGRPCStream(ctx, id, req.Spec.GRPC.Body)
+			return
+		}
+	}
+
+	res, err := c.grpcService.Invoke(ctx, id, c.activeEnvironmentID())
+	c.view.SetGRPCResponse(id, res, err)
+}
+
+// openGRPCStream opens the stream of the request and shows its messages until it ends, body is sent right away
+// unless the method is client streaming, in which case the messages are sent one by one from the request view.
+func (c *Controller) openGRPCStream(ctx context.Context, id, body string) {
+	stream, err := c.grpcService.OpenStream(ctx, id, c.activeEnvironmentID())
+	if err != nil {
+		c.view.SetGRPCResponse(id, nil, err)
+		return
+	}
+
+	c.grpcStreams.Set(id, stream)
+	defer c.grpcStreams.Delete(id)
+
+	c.view.SetGRPCStreamOpened(id, stream.ClientStreaming)
+
+	if !stream.ClientStreaming {
+		c.onGRPCSend(id, body)
+		c.onGRPCCloseSend(id)
+	}
+
+	res, err := stream.Receive(func(msg grpc.StreamMessage) {
+		c.view.AddGRPCStreamMessage(id, msg)
+	})
+	c.view.SetGRPCResponse(id, res, err)
+}
+
+func (c *Controller) onGRPCSend(id, body string) {
+	stream, ok := c.grpcStreams.Get(id)
+	if !ok {
+		c.view.SetGRPCStreamError(id, fmt.Errorf("stream is not open"))
+		return
+	}
+
+	msg, err := stream.Send(body)
+	if err != nil {
+		c.view.SetGRPCStreamError(id, err)
+		return
+	}
+
+	c.view.AddGRPCStreamMessage(id, msg)
+}
+
+func (c *Controller) onGRPCCloseSend(id string) {
+	stream, ok := c.grpcStreams.Get(id)
+	if !ok {
+		return
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		c.view.SetGRPCStreamError(id, err)
+	}
+}
+
+func (c *Controller) onGRPCReloadServices(id string) {
+	c.fetch(id, fetchGRPCServices, func(ctx context.Context) {
+		services, err := c.grpcService.GetServices(ctx, id, c.activeEnvironmentID())
+		c.view.SetGRPCServices(id, services, err)
+	})
+}
+
+func (c *Controller) onGRPCRequestTemplate(id, method string) {
+	c.fetch(id, fetchGRPCTemplate, func(ctx context.Context) {
+		template, err := c.grpcService.GetRequestTemplate(ctx, id, c.activeEnvironmentID(), method)
+		c.view.SetGRPCRequestTemplate(id, template, err)
+	})
+}
+
+func (c *Controller) activeEnvironmentID() string {
+	if env := c.envState.GetActiveEnvironment(); env != nil {
+		return env.MetaData.ID
+	}
+	return ""
 }
 
 func cookieToKeyValue(cookies []*http.Cookie) []domain.KeyValue {
@@ -269,6 +749,8 @@ func (c *Controller) onTabClose(id string) {
 }
 
 func (c *Controller) onCollectionTabClose(id string) {
+	// the run can not be stopped once its tab is closed
+	c.onStopRun(id)
 	c.view.CloseTab(id)
 }
 
@@ -299,6 +781,31 @@ func (c *Controller) onRequestDataChanged(id string, data any) {
 		return
 	}
 
+	// query params are synced with the url only for http requests
+	if req.Spec.HTTP != nil && inComingRequest.Spec.HTTP != nil {
+		c.syncQueryParams(id, req, inComingRequest)
+	}
+
+	// break the reference
+	clone := inComingRequest.Clone()
+	req.Spec = clone.Spec
+
+	if err := c.model.UpdateRequest(req, true); err != nil {
+		fmt.Println("failed to update request", err)
+		return
+	}
+
+	// set tab dirty if the in memory data is different from the file
+	reqFromFile, err := c.model.GetRequestFromDisc(id)
+	if err != nil {
+		fmt.Println("failed to get request from file", err)
+		return
+	}
+	c.view.SetTabDirty(id, !domain.CompareRequests(req, reqFromFile))
+	c.view.SetTreeViewNodePrefix(id, RequestPrefix(req), chapartheme.GetRequestPrefixColor(RequestPrefix(req)))
+}
+
+func (c *Controller) syncQueryParams(id string, req, inComingRequest *domain.Request) {
 	queryParamsChanged := !domain.CompareKeyValues(req.Spec.HTTP.Request.QueryParams, inComingRequest.Spec.HTTP.Request.QueryParams)
 	urlChanged := inComingRequest.Spec.HTTP.URL != req.Spec.HTTP.URL
 
@@ -318,24 +825,6 @@ func (c *Controller) onRequestDataChanged(id string, data any) {
 		c.view.SetPathParams(id, newPathParams)
 		inComingRequest.Spec.HTTP.Request.PathParams = newPathParams
 	}
-
-	// break the reference
-	clone := inComingRequest.Clone()
-	req.Spec = clone.Spec
-
-	if err := c.model.UpdateRequest(req, true); err != nil {
-		fmt.Println("failed to update request", err)
-		return
-	}
-
-	// set tab dirty if the in memory data is different from the file
-	reqFromFile, err := c.model.GetRequestFromDisc(id)
-	if err != nil {
-		fmt.Println("failed to get request from file", err)
-		return
-	}
-	c.view.SetTabDirty(id, !domain.CompareRequests(req, reqFromFile))
-	c.view.SetTreeViewNodePrefix(id, req.Spec.HTTP.Method, chapartheme.GetRequestPrefixColor(req.Spec.HTTP.Method))
 }
 
 func (c *Controller) getNewURLWithParams(params []domain.KeyValue, url string) string {
@@ -408,6 +897,7 @@ func (c *Controller) onRequestTabClose(id string) {
 
 	// if data is not changed close the tab
 	if domain.CompareRequests(req, reqFromFile) {
+		c.closeRequest(id)
 		c.view.CloseTab(id)
 		return
 	}
@@ -425,6 +915,7 @@ func (c *Controller) onRequestTabClose(id string) {
 				c.saveRequestToDisc(id)
 			}
 
+			c.closeRequest(id)
 			c.view.CloseTab(id)
 			c.model.ReloadRequestFromDisc(id)
 		},
@@ -474,8 +965,16 @@ func (c *Controller) onCollectionTitleChange(id, title string) {
 	c.view.UpdateTabTitle(col.MetaData.ID, col.MetaData.Name)
 }
 
-func (c *Controller) onNewRequest() {
+func (c *Controller) onNewRequest(requestType string) {
 	req := domain.NewRequest("New Request")
+	switch requestType {
+	case domain.RequestTypeGraphQL:
+		req = domain.NewGraphQLRequest("New Request")
+	case domain.RequestTypeWebSocket:
+		req = domain.NewWebSocketRequest("New Request")
+	case domain.RequestTypeGRPC:
+		req = domain.NewGRPCRequest("New Request")
+	}
 
 	newFilePath, err := c.repo.GetNewRequestFilePath(req.MetaData.Name)
 	if err != nil {
@@ -514,6 +1013,27 @@ func (c *Controller) onImport() {
 	}, "json")
 }
 
+func (c *Controller) onCollectionVariablesChanged(id string, values []domain.KeyValue) {
+	col := c.model.GetCollection(id)
+	if col == nil {
+		return
+	}
+
+	col.Spec.Variables = values
+	if err := c.model.UpdateCollection(col, false); err != nil {
+		fmt.Println("failed to update collection", err)
+	}
+}
+
+// onCollectionChange shows the variables set by the post requests in the open collection tabs.
+func (c *Controller) onCollectionChange(col *domain.Collection, action state.Action) {
+	if action != state.ActionUpdate {
+		return
+	}
+
+	c.view.SetCollectionVariables(col.MetaData.ID, col.Spec.Variables)
+}
+
 func (c *Controller) onNewCollection() {
 	col := domain.NewCollection("New Collection")
 
@@ -637,6 +1157,9 @@ func (c *Controller) viewRequest(id string) {
 
 	c.view.OpenTab(req.MetaData.ID, req.MetaData.Name, TypeRequest)
 	c.view.OpenRequestContainer(clone)
+
+	// responses of previous runs are not lost when the tab is closed
+	c.showResponseHistory(id, true)
 }
 
 func (c *Controller) viewCollection(id string) {
@@ -685,6 +1208,10 @@ func (c *Controller) deleteRequest(id string) {
 		return
 	}
 
+	c.closeRequest(id)
+	if err := c.historyState.ClearResponses(id); err != nil {
+		fmt.Println("failed to clear response history", err)
+	}
 	c.view.RemoveTreeViewNode(id)
 	c.view.CloseTab(id)
 }
@@ -698,6 +1225,16 @@ func (c *Controller) deleteCollection(id string) {
 		fmt.Println("failed to remove collection", err)
 		return
 	}
+
+	// the requests are deleted with the collection, so their history is not kept either
+	for _, req := range col.Spec.Requests {
+		c.closeRequest(req.MetaData.ID)
+		if err := c.historyState.ClearResponses(req.MetaData.ID); err != nil {
+			fmt.Println("failed to clear response history", err)
+		}
+	}
+
+	c.onStopRun(id)
 	c.view.RemoveTreeViewNode(id)
 	c.view.CloseTab(id)
 }
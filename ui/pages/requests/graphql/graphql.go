@@ -0,0 +1,275 @@
+package graphql
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	giox "gioui.org/x/component"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/graphql"
+	"github.com/chapar-rest/chapar/internal/loadtest"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+// ContainerType is the type shown in the breadcrumb and the tree view for GraphQL requests.
+const ContainerType = "GQL"
+
+type GraphQL struct {
+	Prompt *widgets.Prompt
+
+	Req *domain.Request
+
+	Breadcrumb *component.Breadcrumb
+	AddressBar *component.AddressBar
+	Response   *restful.Response
+	Request    *Request
+
+	split widgets.SplitView
+
+	onSave        func(id string)
+	onDataChanged func(id string, data any)
+	onSubmit      func(id string)
+	onCancel      func(id string)
+}
+
+func New(req *domain.Request, theme *chapartheme.Theme) *GraphQL {
+	r := &GraphQL{
+		Req:        req,
+		Prompt:     widgets.NewPrompt("", "", ""),
+		Breadcrumb: component.NewBreadcrumb(req.MetaData.ID, req.CollectionName, ContainerType, req.MetaData.Name),
+		AddressBar: component.NewAddressBar(theme, req.Spec.GraphQL.URL, ""),
+		split: widgets.SplitView{
+			Resize: giox.Resize{
+				Ratio: 0.5,
+			},
+			BarWidth: unit.Dp(2),
+		},
+		Response: restful.NewResponse(theme),
+		Request:  NewRequest(req, theme),
+	}
+	r.setupHooks()
+
+	return r
+}
+
+func (r *GraphQL) SetOnDataChanged(f func(id string, data any)) {
+	r.onDataChanged = f
+}
+
+func (r *GraphQL) SetOnSubmit(f func(id string)) {
+	r.onSubmit = f
+}
+
+func (r *GraphQL) SetOnCancel(f func(id string)) {
+	r.onCancel = f
+}
+
+func (r *GraphQL) SetOnFetchSchema(f func(id string)) {
+	r.Request.Schema.SetOnFetch(func() {
+		r.Request.Schema.SetLoading()
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *GraphQL) SetSchema(schema *graphql.Schema, err error) {
+	r.Request.Schema.SetSchema(schema, err)
+}
+
+func (r *GraphQL) SetDataChanged(changed bool) {
+	r.Breadcrumb.SetDataChanged(changed)
+}
+
+func (r *GraphQL) SetOnTitleChanged(f func(title string)) {
+	r.Breadcrumb.SetOnTitleChanged(f)
+}
+
+func (r *GraphQL) SetResponseHistory(responses []domain.HTTPResponse) {
+	r.Response.SetHistory(responses)
+}
+
+func (r *GraphQL) SetOnClearResponseHistory(f func(id string)) {
+	r.Response.SetOnClearHistory(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *GraphQL) SetOnSaveResponse(f func(id string)) {
+	r.Response.SetOnSaveResponse(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *GraphQL) SetOnLoadTest(f func(id string, opts loadtest.Options)) {
+	r.Request.LoadTest.SetOnStart(func(opts loadtest.Options) {
+		f(r.Req.MetaData.ID, opts)
+	})
+}
+
+func (r *GraphQL) SetOnStopLoadTest(f func(id string)) {
+	r.Request.LoadTest.SetOnStop(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *GraphQL) SetLoadTestStarted() {
+	r.Request.LoadTest.SetStarted()
+}
+
+func (r *GraphQL) SetLoadTestStats(stats loadtest.Stats) {
+	r.Request.LoadTest.SetStats(stats)
+}
+
+func (r *GraphQL) SetLoadTestFinished(stats loadtest.Stats, err error) {
+	r.Request.LoadTest.SetFinished(stats, err)
+}
+
+func (r *GraphQL) SetOnCopyResponse(f func(gtx layout.Context, dataType, data string)) {
+	r.Response.SetOnCopyResponse(f)
+}
+
+func (r *GraphQL) SetHTTPResponse(detail domain.HTTPResponseDetail) {
+	if detail.Error != nil {
+		r.Response.SetError(detail.Error)
+		return
+	}
+
+	r.Response.SetResponse(detail.Response)
+	r.Response.SetHeaders(detail.Headers)
+	r.Response.SetCookies(detail.Cookies)
+	r.Response.SetTimings(detail.Timings)
+	r.Response.SetTestResults(detail.TestResults)
+	r.Response.SetBody(detail)
+	r.Response.SetStatusParams(detail.StatusCode, detail.Duration, detail.Size)
+}
+
+func (r *GraphQL) GetHTTPResponse() *domain.HTTPResponseDetail {
+	return r.Response.GetResponse()
+}
+
+func (r *GraphQL) ShowSendingRequestLoading() {
+	r.Response.ClearStream()
+	r.Response.SetError(nil)
+	r.Response.SetMessage("Sending request...")
+	r.AddressBar.SetSendingRequest(true)
+}
+
+func (r *GraphQL) HideSendingRequestLoading() {
+	r.Response.SetMessage("")
+	r.AddressBar.SetSendingRequest(false)
+	r.AddressBar.SetButtonTexts("Send", "Cancel")
+}
+
+// SetStreamStarted shows the streamed response while it arrives, the request can be stopped with the cancel button.
+func (r *GraphQL) SetStreamStarted(code int, headers []domain.KeyValue) {
+	r.Response.SetStreamStarted(code, headers)
+	r.AddressBar.SetButtonTexts("Send", "Stop")
+}
+
+func (r *GraphQL) AddStreamEvent(event rest.StreamEvent) {
+	r.Response.AddStreamEvent(event)
+}
+
+func (r *GraphQL) SetOnSave(f func(id string)) {
+	r.onSave = f
+}
+
+func (r *GraphQL) ShowPrompt(title, content, modalType string, onSubmit func(selectedOption string, remember bool), options ...widgets.Option) {
+	r.Prompt.Type = modalType
+	r.Prompt.Title = title
+	r.Prompt.Content = content
+	r.Prompt.SetOptions(options...)
+	r.Prompt.WithoutRememberBool()
+	r.Prompt.SetOnSubmit(onSubmit)
+	r.Prompt.Show()
+}
+
+func (r *GraphQL) HidePrompt() {
+	r.Prompt.Hide()
+}
+
+func (r *GraphQL) setupHooks() {
+	r.Breadcrumb.SetOnSave(func(id string) {
+		r.onSave(id)
+	})
+
+	r.AddressBar.SetOnURLChanged(func(url string) {
+		r.Req.Spec.GraphQL.URL = url
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.AddressBar.SetOnSubmit(func() {
+		r.onSubmit(r.Req.MetaData.ID)
+	})
+
+	r.AddressBar.SetOnCancel(func() {
+		if r.onCancel != nil {
+			r.onCancel(r.Req.MetaData.ID)
+		}
+	})
+
+	r.Request.OperationName.SetOnTextChange(func(text string) {
+		r.Req.Spec.GraphQL.OperationName = text
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Query.SetOnChanged(func(text string) {
+		r.Req.Spec.GraphQL.Query = text
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Variables.SetOnChanged(func(text string) {
+		r.Req.Spec.GraphQL.Variables = text
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Headers.SetOnChange(func(headers []domain.KeyValue) {
+		r.Req.Spec.GraphQL.Headers = headers
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Auth.SetOnChange(func(auth domain.Auth) {
+		r.Req.Spec.GraphQL.Auth = auth
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Tests.SetOnChange(func(assertions []domain.Assertion) {
+		r.Req.Spec.GraphQL.Tests = assertions
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Settings.SetOnChange(func(settings *domain.HTTPClientSettings) {
+		r.Req.Spec.GraphQL.Settings = settings
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+}
+
+func (r *GraphQL) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.UniformInset(unit.Dp(10)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Prompt.Layout(gtx, theme)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(15), Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return r.Breadcrumb.Layout(gtx, theme)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.AddressBar.Layout(gtx, theme)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return r.split.Layout(gtx, theme,
+					func(gtx layout.Context) layout.Dimensions {
+						return r.Request.Layout(gtx, theme)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return r.Response.Layout(gtx, theme)
+					},
+				)
+			}),
+		)
+	})
+}
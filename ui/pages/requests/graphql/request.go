@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+type Request struct {
+	Tabs *widgets.Tabs
+
+	OperationName *widgets.TextField
+	Query         *widgets.CodeEditor
+	Variables     *widgets.CodeEditor
+
+	Headers  *restful.Headers
+	Auth     *restful.Auth
+	Settings *restful.Settings
+	Tests    *component.Assertions
+	Schema   *Schema
+	LoadTest *restful.LoadTest
+}
+
+func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
+	spec := req.Spec.GraphQL
+
+	r := &Request{
+		Tabs: widgets.NewTabs([]*widgets.Tab{
+			{Title: "Query"},
+			{Title: "Variables"},
+			{Title: "Headers"},
+			{Title: "Auth"},
+			{Title: "Tests"},
+			{Title: "Settings"},
+			{Title: "Schema"},
+			{Title: "Load Test"},
+		}, nil),
+		OperationName: widgets.NewTextField(spec.OperationName, "Operation name"),
+		Query:         widgets.NewCodeEditor(spec.Query, "GraphQL", theme),
+		Variables:     widgets.NewCodeEditor(spec.Variables, "JSON", theme),
+		Headers:       restful.NewHeaders(spec.Headers),
+		Auth:          restful.NewAuth(spec.Auth, theme),
+		Settings:      restful.NewSettings(spec.Settings, theme),
+		Tests:         component.NewAssertions(theme, spec.Tests),
+		Schema:        NewSchema(),
+		LoadTest:      restful.NewLoadTest(),
+	}
+
+	return r
+}
+
+func (r *Request) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	inset := layout.Inset{Top: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis:      layout.Vertical,
+			Alignment: layout.Start,
+		}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Tabs.Layout(gtx, theme)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch r.Tabs.SelectedTab().Title {
+				case "Query":
+					return r.queryLayout(gtx, theme)
+				case "Variables":
+					return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return r.Variables.Layout(gtx, theme, "Variables as a JSON object, e.g. {\"id\": \"{{userID}}\"}")
+					})
+				case "Headers":
+					return r.Headers.Layout(gtx, theme)
+				case "Auth":
+					return r.Auth.Layout(gtx, theme)
+				case "Tests":
+					return r.Tests.Layout(gtx, theme)
+				case "Settings":
+					return r.Settings.Layout(gtx, theme)
+				case "Schema":
+					return r.Schema.Layout(gtx, theme)
+				case "Load Test":
+					return r.LoadTest.Layout(gtx, theme)
+				default:
+					return layout.Dimensions{}
+				}
+			}),
+		)
+	})
+}
+
+func (r *Request) queryLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Max.X = gtx.Dp(300)
+					return r.OperationName.Layout(gtx, theme)
+				})
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return r.Query.Layout(gtx, theme, "query { ... }")
+			}),
+		)
+	})
+}
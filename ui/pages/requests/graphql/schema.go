@@ -0,0 +1,299 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/graphql"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+// Schema is a browsable explorer of the schema fetched through introspection.
+type Schema struct {
+	fetchButton widget.Clickable
+	searchBox   *widgets.TextField
+
+	mx         *sync.Mutex
+	schema     *graphql.Schema
+	err        error
+	loading    bool
+	filterText string
+
+	selectedType string
+	// history of the visited types, so the user can go back after following a field type
+	history    []string
+	backButton widget.Clickable
+
+	typeButtons  map[string]*widget.Clickable
+	fieldButtons []widget.Clickable
+
+	typesList   *widget.List
+	detailsList *widget.List
+
+	onFetch func()
+}
+
+func NewSchema() *Schema {
+	search := widgets.NewTextField("", "Search types...")
+	search.SetIcon(widgets.SearchIcon, widgets.IconPositionEnd)
+
+	s := &Schema{
+		mx:          &sync.Mutex{},
+		searchBox:   search,
+		typeButtons: make(map[string]*widget.Clickable),
+		typesList: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+		detailsList: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	s.searchBox.SetOnTextChange(func(text string) {
+		s.mx.Lock()
+		defer s.mx.Unlock()
+		s.filterText = strings.ToLower(text)
+	})
+
+	return s
+}
+
+func (s *Schema) SetOnFetch(f func()) {
+	s.onFetch = f
+}
+
+func (s *Schema) SetLoading() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.loading = true
+	s.err = nil
+}
+
+func (s *Schema) SetSchema(schema *graphql.Schema, err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.loading = false
+	s.err = err
+	if err != nil {
+		return
+	}
+
+	s.schema = schema
+	s.history = nil
+	s.selectedType = ""
+	if roots := schema.RootTypes(); len(roots) > 0 {
+		s.selectedType = roots[0]
+	}
+}
+
+func (s *Schema) selectType(name string) {
+	if name == s.selectedType || s.schema.Type(name) == nil {
+		return
+	}
+
+	if s.selectedType != "" {
+		s.history = append(s.history, s.selectedType)
+	}
+	s.selectedType = name
+	s.detailsList.Position.First = 0
+}
+
+func (s *Schema) typeButton(name string) *widget.Clickable {
+	btn, ok := s.typeButtons[name]
+	if !ok {
+		btn = &widget.Clickable{}
+		s.typeButtons[name] = btn
+	}
+	return btn
+}
+
+func (s *Schema) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if s.fetchButton.Clicked(gtx) && s.onFetch != nil {
+		go s.onFetch()
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						text := "Fetch Schema"
+						if s.schema != nil {
+							text = "Refresh Schema"
+						}
+						btn := widgets.Button(theme.Material(), &s.fetchButton, widgets.ForwardIcon, widgets.IconPositionStart, text)
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Max.X = gtx.Dp(200)
+						return s.searchBox.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch {
+				case s.loading:
+					return component.Message(gtx, component.MessageTypeInfo, theme, "Fetching schema...")
+				case s.err != nil:
+					return component.Message(gtx, component.MessageTypeError, theme, s.err.Error())
+				case s.schema == nil:
+					return component.Message(gtx, component.MessageTypeInfo, theme, "Fetch the schema to browse the types of the endpoint")
+				}
+
+				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Max.X = gtx.Dp(200)
+						gtx.Constraints.Min.X = gtx.Constraints.Max.X
+						return s.typesLayout(gtx, theme)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return s.detailsLayout(gtx, theme)
+					}),
+				)
+			}),
+		)
+	})
+}
+
+func (s *Schema) typesLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	names := make([]string, 0)
+	for _, name := range s.schema.TypeNames() {
+		if s.filterText == "" || strings.Contains(strings.ToLower(name), s.filterText) {
+			names = append(names, name)
+		}
+	}
+
+	return material.List(theme.Material(), s.typesList).Layout(gtx, len(names), func(gtx layout.Context, i int) layout.Dimensions {
+		name := names[i]
+		btn := s.typeButton(name)
+		if btn.Clicked(gtx) {
+			s.selectType(name)
+		}
+
+		return material.Clickable(gtx, btn, func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(unit.Dp(4)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+				lb := material.Label(theme.Material(), theme.TextSize, name)
+				lb.MaxLines = 1
+				if name == s.selectedType {
+					lb.Font.Weight = font.Bold
+				}
+				return lb.Layout(gtx)
+			})
+		})
+	})
+}
+
+func (s *Schema) detailsLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	t := s.schema.Type(s.selectedType)
+	if t == nil {
+		return layout.Dimensions{}
+	}
+
+	if s.backButton.Clicked(gtx) && len(s.history) > 0 {
+		s.selectedType = s.history[len(s.history)-1]
+		s.history = s.history[:len(s.history)-1]
+		t = s.schema.Type(s.selectedType)
+	}
+
+	// each row is a label, rows which refer to another type navigate to it when clicked
+	type row struct {
+		text   string
+		target string
+		muted  bool
+	}
+
+	rows := make([]row, 0)
+	if t.Description != "" {
+		rows = append(rows, row{text: t.Description, muted: true})
+	}
+
+	for _, f := range t.Fields {
+		text := f.Signature()
+		if f.IsDeprecated {
+			text += " (deprecated)"
+		}
+		rows = append(rows, row{text: text, target: f.Type.NamedType()})
+		if f.Description != "" {
+			rows = append(rows, row{text: "    " + f.Description, muted: true})
+		}
+	}
+
+	for _, f := range t.InputFields {
+		rows = append(rows, row{text: f.Name + ": " + f.Type.String(), target: f.Type.NamedType()})
+	}
+
+	for _, v := range t.EnumValues {
+		rows = append(rows, row{text: v.Name})
+	}
+
+	for _, p := range t.PossibleTypes {
+		rows = append(rows, row{text: p.NamedType(), target: p.NamedType()})
+	}
+
+	if len(s.fieldButtons) < len(rows) {
+		s.fieldButtons = make([]widget.Clickable, len(rows))
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if len(s.history) == 0 {
+						return layout.Dimensions{}
+					}
+					btn := widgets.Button(theme.Material(), &s.backButton, nil, widgets.IconPositionStart, "Back")
+					btn.Color = theme.ButtonTextColor
+					return layout.Inset{Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return btn.Layout(gtx, theme)
+					})
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					lb := material.Label(theme.Material(), unit.Sp(15), fmt.Sprintf("%s (%s)", t.Name, strings.ToLower(t.Kind)))
+					lb.Font.Weight = font.Bold
+					return lb.Layout(gtx)
+				}),
+			)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return material.List(theme.Material(), s.detailsList).Layout(gtx, len(rows), func(gtx layout.Context, i int) layout.Dimensions {
+				r := rows[i]
+				label := func(gtx layout.Context) layout.Dimensions {
+					return layout.UniformInset(unit.Dp(3)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Constraints.Max.X
+						lb := material.Label(theme.Material(), theme.TextSize, r.text)
+						if r.muted {
+							lb.Color = theme.TextColor
+						}
+						return lb.Layout(gtx)
+					})
+				}
+
+				if r.target == "" || s.schema.Type(r.target) == nil {
+					return label(gtx)
+				}
+
+				if s.fieldButtons[i].Clicked(gtx) {
+					s.selectType(r.target)
+				}
+				return material.Clickable(gtx, &s.fieldButtons[i], label)
+			})
+		}),
+	)
+}
@@ -0,0 +1,276 @@
+package grpc
+
+import (
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	giox "gioui.org/x/component"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/grpc"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+// ContainerType is the type shown in the breadcrumb and the tree view for gRPC requests.
+const ContainerType = "GRPC"
+
+type GRPC struct {
+	Prompt *widgets.Prompt
+
+	Req *domain.Request
+
+	Breadcrumb *component.Breadcrumb
+	AddressBar *component.AddressBar
+	Response   *Response
+	Request    *Request
+
+	split widgets.SplitView
+
+	onSave        func(id string)
+	onDataChanged func(id string, data any)
+	onSubmit      func(id string)
+	onCancel      func(id string)
+}
+
+func New(req *domain.Request, theme *chapartheme.Theme) *GRPC {
+	r := &GRPC{
+		Req:        req,
+		Prompt:     widgets.NewPrompt("", "", ""),
+		Breadcrumb: component.NewBreadcrumb(req.MetaData.ID, req.CollectionName, ContainerType, req.MetaData.Name),
+		AddressBar: component.NewAddressBar(theme, req.Spec.GRPC.Host, ""),
+		split: widgets.SplitView{
+			Resize: giox.Resize{
+				Ratio: 0.5,
+			},
+			BarWidth: unit.Dp(2),
+		},
+		Response: NewResponse(),
+		Request:  NewRequest(req, theme),
+	}
+	r.AddressBar.SetButtonTexts("Invoke", "Cancel")
+	r.setupHooks()
+
+	return r
+}
+
+func (r *GRPC) SetOnDataChanged(f func(id string, data any)) {
+	r.onDataChanged = f
+}
+
+func (r *GRPC) SetOnSubmit(f func(id string)) {
+	r.onSubmit = f
+}
+
+func (r *GRPC) SetOnCancel(f func(id string)) {
+	r.onCancel = f
+}
+
+// SetOnReloadServices sets the callback to discover the services of the host.
+func (r *GRPC) SetOnReloadServices(f func(id string)) {
+	r.Request.SetOnReload(func() {
+		r.Request.SetLoading(true)
+		f(r.Req.MetaData.ID)
+	})
+}
+
+// SetOnRequestTemplate sets the callback to get the template of the input message of the selected method.
+func (r *GRPC) SetOnRequestTemplate(f func(id, method string)) {
+	r.Request.SetOnTemplate(func() {
+		if method := r.Req.Spec.GRPC.Method; method != "" {
+			f(r.Req.MetaData.ID, method)
+		}
+	})
+}
+
+func (r *GRPC) SetServices(services []domain.GRPCService, err error) {
+	r.Request.SetLoading(false)
+	if err != nil {
+		r.Request.SetError(err)
+		return
+	}
+
+	r.Req.Spec.GRPC.Services = services
+	r.Request.SetServices(services, r.Req.Spec.GRPC.Method)
+	if _, ok := r.Req.Spec.GRPC.FindMethod(r.Req.Spec.GRPC.Method); !ok {
+		r.Req.Spec.GRPC.Method = r.Request.SelectedMethod()
+	}
+
+	r.onDataChanged(r.Req.MetaData.ID, r.Req)
+}
+
+func (r *GRPC) SetRequestTemplate(template string, err error) {
+	if err != nil {
+		r.Request.SetError(err)
+		return
+	}
+
+	r.Request.Body.SetCode(template)
+	r.Req.Spec.GRPC.Body = template
+	r.onDataChanged(r.Req.MetaData.ID, r.Req)
+}
+
+func (r *GRPC) SetGRPCResponse(response *grpc.Response, err error) {
+	if err != nil {
+		r.Response.SetError(err)
+		return
+	}
+
+	r.Response.SetResponse(response)
+}
+
+// SetOnSendMessage sets the callback to send a message on the open stream of the request.
+func (r *GRPC) SetOnSendMessage(f func(id, body string)) {
+	r.Request.SetOnSend(func(body string) {
+		f(r.Req.MetaData.ID, body)
+	})
+}
+
+// SetOnCloseSend sets the callback to half-close the open stream of the request.
+func (r *GRPC) SetOnCloseSend(f func(id string)) {
+	r.Request.SetOnCloseSend(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+// SetStreamOpened shows the messages of the stream as they are added, clientStreaming enables sending more messages.
+func (r *GRPC) SetStreamOpened(clientStreaming bool) {
+	r.Response.SetStreamStarted()
+	r.Request.SetClientStreaming(clientStreaming)
+}
+
+func (r *GRPC) AddStreamMessage(msg grpc.StreamMessage) {
+	r.Response.AddStreamMessage(msg)
+}
+
+// SetStreamError shows the error of sending on the stream, the stream stays open.
+func (r *GRPC) SetStreamError(err error) {
+	r.Request.SetError(err)
+}
+
+func (r *GRPC) ShowSendingRequestLoading() {
+	r.Response.Clear()
+	r.Response.SetMessage("Invoking method...")
+	r.AddressBar.SetSendingRequest(true)
+}
+
+func (r *GRPC) HideSendingRequestLoading() {
+	r.Response.SetMessage("")
+	r.Request.SetClientStreaming(false)
+	r.AddressBar.SetSendingRequest(false)
+}
+
+func (r *GRPC) SetDataChanged(changed bool) {
+	r.Breadcrumb.SetDataChanged(changed)
+}
+
+func (r *GRPC) SetOnTitleChanged(f func(title string)) {
+	r.Breadcrumb.SetOnTitleChanged(f)
+}
+
+func (r *GRPC) SetOnCopyResponse(f func(gtx layout.Context, dataType, data string)) {
+	r.Response.SetOnCopyResponse(f)
+}
+
+func (r *GRPC) SetOnSave(f func(id string)) {
+	r.onSave = f
+}
+
+func (r *GRPC) ShowPrompt(title, content, modalType string, onSubmit func(selectedOption string, remember bool), options ...widgets.Option) {
+	r.Prompt.Type = modalType
+	r.Prompt.Title = title
+	r.Prompt.Content = content
+	r.Prompt.SetOptions(options...)
+	r.Prompt.WithoutRememberBool()
+	r.Prompt.SetOnSubmit(onSubmit)
+	r.Prompt.Show()
+}
+
+func (r *GRPC) HidePrompt() {
+	r.Prompt.Hide()
+}
+
+func (r *GRPC) setupHooks() {
+	r.Breadcrumb.SetOnSave(func(id string) {
+		r.onSave(id)
+	})
+
+	r.AddressBar.SetOnURLChanged(func(host string) {
+		r.Req.Spec.GRPC.Host = host
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.AddressBar.SetOnSubmit(func() {
+		r.onSubmit(r.Req.MetaData.ID)
+	})
+
+	r.AddressBar.SetOnCancel(func() {
+		if r.onCancel != nil {
+			r.onCancel(r.Req.MetaData.ID)
+		}
+	})
+
+	r.Request.Methods.SetOnChanged(func(method string) {
+		r.Req.Spec.GRPC.Method = method
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+
+		// fill the message of the new method unless the user already wrote one
+		if body := strings.TrimSpace(r.Req.Spec.GRPC.Body); (body == "" || body == "{}") && r.Request.onTemplate != nil {
+			r.Request.onTemplate()
+		}
+	})
+
+	r.Request.SetOnReflectionChanged(func(enabled bool) {
+		r.Req.Spec.GRPC.ServerReflection = enabled
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Body.SetOnChanged(func(text string) {
+		r.Req.Spec.GRPC.Body = text
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Metadata.SetOnChange(func(values []domain.KeyValue) {
+		r.Req.Spec.GRPC.Metadata = values
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Auth.SetOnChange(func(auth domain.Auth) {
+		r.Req.Spec.GRPC.Auth = auth
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Settings.SetOnChange(func(settings domain.GRPCSettings) {
+		r.Req.Spec.GRPC.Settings = settings
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+}
+
+func (r *GRPC) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.UniformInset(unit.Dp(10)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Prompt.Layout(gtx, theme)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(15), Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return r.Breadcrumb.Layout(gtx, theme)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.AddressBar.Layout(gtx, theme)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return r.split.Layout(gtx, theme,
+					func(gtx layout.Context) layout.Dimensions {
+						return r.Request.Layout(gtx, theme)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return r.Response.Layout(gtx, theme)
+					},
+				)
+			}),
+		)
+	})
+}
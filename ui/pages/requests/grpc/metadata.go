@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/converter"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+// Metadata is the editor of the metadata sent with every call.
+type Metadata struct {
+	values *widgets.KeyValue
+
+	onChange func(values []domain.KeyValue)
+}
+
+func NewMetadata(metadata []domain.KeyValue) *Metadata {
+	return &Metadata{
+		values: widgets.NewKeyValue(
+			converter.WidgetItemsFromKeyValue(metadata)...,
+		),
+	}
+}
+
+func (m *Metadata) SetOnChange(f func(values []domain.KeyValue)) {
+	m.onChange = f
+
+	m.values.SetOnChanged(func(items []*widgets.KeyValueItem) {
+		m.onChange(converter.KeyValueFromWidgetItems(m.values.Items))
+	})
+}
+
+func (m *Metadata) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return m.values.WithAddLayout(gtx, "Metadata", "", theme)
+	})
+}
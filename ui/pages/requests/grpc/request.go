@@ -0,0 +1,260 @@
+package grpc
+
+import (
+	"sync"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+type Request struct {
+	Tabs *widgets.Tabs
+
+	Methods  *widgets.DropDown
+	Body     *widgets.CodeEditor
+	Metadata *Metadata
+	Auth     *restful.Auth
+	Settings *Settings
+	// Reflection reads the services from the server when checked, otherwise from the proto files of the workspace
+	Reflection *widget.Bool
+
+	reloadButton    widget.Clickable
+	templateButton  widget.Clickable
+	sendButton      widget.Clickable
+	closeSendButton widget.Clickable
+
+	mx      *sync.Mutex
+	loading bool
+	err     error
+	// clientStreaming is true while a stream which accepts several messages is open
+	clientStreaming bool
+
+	onReload            func()
+	onTemplate          func()
+	onReflectionChanged func(enabled bool)
+	onSend              func(body string)
+	onCloseSend         func()
+}
+
+func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
+	spec := req.Spec.GRPC
+
+	r := &Request{
+		Tabs: widgets.NewTabs([]*widgets.Tab{
+			{Title: "Message"},
+		}, nil),
+		Methods:    widgets.NewDropDown(theme),
+		Body:       widgets.NewCodeEditor(spec.Body, "JSON", theme),
+		Reflection: &widget.Bool{Value: spec.ServerReflection},
+		mx:         &sync.Mutex{},
+	}
+	r.Methods.MinWidth = unit.Dp(250)
+	r.SetServices(spec.Services, spec.Method)
+
+	return r
+}
+
+func (r *Request) SetOnReload(f func()) {
+	r.onReload = f
+}
+
+func (r *Request) SetOnTemplate(f func()) {
+	r.onTemplate = f
+}
+
+func (r *Request) SetOnReflectionChanged(f func(enabled bool)) {
+	r.onReflectionChanged = f
+}
+
+// SetOnSend sets the callback to send the message in the editor on the open stream.
+func (r *Request) SetOnSend(f func(body string)) {
+	r.onSend = f
+}
+
+// SetOnCloseSend sets the callback to half-close the open stream.
+func (r *Request) SetOnCloseSend(f func()) {
+	r.onCloseSend = f
+}
+
+// SetClientStreaming shows the buttons to send messages and half-close the stream while it is open.
+func (r *Request) SetClientStreaming(open bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.clientStreaming = open
+	if open {
+		r.err = nil
+	}
+}
+
+// SetServices sets the methods to pick from, selecting the given method.
+func (r *Request) SetServices(services []domain.GRPCService, selected string) {
+	options := make([]*widgets.DropDownOption, 0)
+	for _, s := range services {
+		for _, m := range s.Methods {
+			options = append(options, widgets.NewDropDownOption(s.Name+"/"+m.Name).WithValue(m.FullName))
+		}
+	}
+
+	r.Methods.SetOptions(options...)
+	r.Methods.SetSelectedByValue(selected)
+}
+
+// SelectedMethod returns the full name of the selected method, it is empty when there is no method.
+func (r *Request) SelectedMethod() string {
+	if len(r.Methods.Options()) == 0 {
+		return ""
+	}
+	return r.Methods.GetSelected().Value
+}
+
+func (r *Request) SetLoading(loading bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.loading = loading
+	if loading {
+		r.err = nil
+	}
+}
+
+func (r *Request) SetError(err error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.err = err
+}
+
+func (r *Request) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	inset := layout.Inset{Top: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis:      layout.Vertical,
+			Alignment: layout.Start,
+		}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Tabs.Layout(gtx, theme)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch r.Tabs.SelectedTab().Title {
+				case "Message":
+					return r.messageLayout(gtx, theme)
+				case "Metadata":
+					return r.Metadata.Layout(gtx, theme)
+				case "Auth":
+					return r.Auth.Layout(gtx, theme)
+				case "Settings":
+					return r.Settings.Layout(gtx, theme)
+				default:
+					return layout.Dimensions{}
+				}
+			}),
+		)
+	})
+}
+
+func (r *Request) messageLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if r.reloadButton.Clicked(gtx) && r.onReload != nil {
+		go r.onReload()
+	}
+
+	if r.templateButton.Clicked(gtx) && r.onTemplate != nil {
+		go r.onTemplate()
+	}
+
+	if r.Reflection.Update(gtx) && r.onReflectionChanged != nil {
+		r.onReflectionChanged(r.Reflection.Value)
+	}
+
+	if r.sendButton.Clicked(gtx) && r.onSend != nil {
+		go r.onSend(r.Body.Code())
+	}
+
+	if r.closeSendButton.Clicked(gtx) && r.onCloseSend != nil {
+		go r.onCloseSend()
+	}
+
+	r.mx.Lock()
+	loading, err, clientStreaming := r.loading, r.err, r.clientStreaming
+	r.mx.Unlock()
+
+	return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if len(r.Methods.Options()) == 0 {
+							return material.Label(theme.Material(), theme.TextSize, "No methods, reload the services").Layout(gtx)
+						}
+						return r.Methods.Layout(gtx, theme)
+					}),
+					layout.Flexed(1, layout.Spacer{}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						ch := material.CheckBox(theme.Material(), r.Reflection, "Server reflection")
+						ch.IconColor = theme.CheckBoxColor
+						ch.Color = theme.TextColor
+						return ch.Layout(gtx)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						text := "Reload services"
+						if loading {
+							text = "Loading..."
+						}
+						btn := widgets.Button(theme.Material(), &r.reloadButton, widgets.RefreshIcon, widgets.IconPositionStart, text)
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &r.templateButton, widgets.EditIcon, widgets.IconPositionStart, "Template")
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if err == nil {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return component.Message(gtx, component.MessageTypeError, theme, err.Error())
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return r.Body.Layout(gtx, theme, "Request message in JSON, environment variables like {{token}} are replaced when sending")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !clientStreaming {
+					return layout.Dimensions{}
+				}
+				return r.streamButtonsLayout(gtx, theme)
+			}),
+		)
+	})
+}
+
+func (r *Request) streamButtonsLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, "Stream is open, send messages then half-close it").Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				btn := widgets.Button(theme.Material(), &r.closeSendButton, widgets.CloseIcon, widgets.IconPositionStart, "Half-close")
+				btn.Color = theme.ButtonTextColor
+				return btn.Layout(gtx, theme)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				btn := widgets.Button(theme.Material(), &r.sendButton, widgets.SendIcon, widgets.IconPositionStart, "Send")
+				btn.Color = theme.ButtonTextColor
+				btn.Background = theme.SendButtonBgColor
+				return btn.Layout(gtx, theme)
+			}),
+		)
+	})
+}
@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"fmt"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/grpc"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
+	"github.com/chapar-rest/chapar/ui/widgets"
+	"github.com/dustin/go-humanize"
+)
+
+// Response shows the response message of unary calls, or the messages of streams, along with the metadata and the status of the call.
+type Response struct {
+	Tabs *widgets.Tabs
+
+	copyClickable widget.Clickable
+
+	headers  *component.ValuesTable
+	trailers *component.ValuesTable
+
+	response *grpc.Response
+	body     string
+	message  string
+	err      error
+
+	// streaming is true while the stream is open
+	streaming bool
+	stream    *restful.Stream
+
+	isBodyUpdated bool
+	jsonViewer    *widgets.JsonViewer
+
+	onCopyResponse func(gtx layout.Context, dataType, data string)
+}
+
+func NewResponse() *Response {
+	return &Response{
+		Tabs: widgets.NewTabs([]*widgets.Tab{
+			{Title: "Body"},
+			{Title: "Metadata"},
+			{Title: "Trailers"},
+		}, nil),
+		headers:    component.NewValuesTable("Metadata", nil),
+		trailers:   component.NewValuesTable("Trailers", nil),
+		stream:     restful.NewStream(),
+		jsonViewer: widgets.NewJsonViewer(),
+	}
+}
+
+func (r *Response) SetOnCopyResponse(f func(gtx layout.Context, dataType, data string)) {
+	r.onCopyResponse = f
+}
+
+// SetResponse shows the result of a call, for streams the received messages are kept
+// and the status of failed calls is added after them.
+func (r *Response) SetResponse(response *grpc.Response) {
+	if r.stream.Len() > 0 && response.Body != "" {
+		r.stream.AddEvent(rest.StreamEvent{Event: "status", Data: response.Body, Time: time.Now()})
+	}
+
+	r.response = response
+	r.body = response.Body
+	r.isBodyUpdated = false
+	r.streaming = false
+	r.err = nil
+	r.message = ""
+	r.headers.SetData(response.Headers)
+	r.trailers.SetData(response.Trailers)
+}
+
+// SetStreamStarted clears the previous response and shows the messages of the stream as they are added.
+func (r *Response) SetStreamStarted() {
+	r.Clear()
+	r.streaming = true
+}
+
+func (r *Response) AddStreamMessage(msg grpc.StreamMessage) {
+	event := "received"
+	if msg.Direction == grpc.StreamDirectionOut {
+		event = "sent"
+	}
+	r.stream.AddEvent(rest.StreamEvent{Event: event, Data: msg.Body, Time: msg.Time})
+}
+
+// Clear removes the previous response.
+func (r *Response) Clear() {
+	r.response = nil
+	r.body = ""
+	r.streaming = false
+	r.err = nil
+	r.message = ""
+	r.stream.Clear()
+	r.headers.SetData(nil)
+	r.trailers.SetData(nil)
+}
+
+func (r *Response) SetMessage(message string) {
+	r.message = message
+}
+
+func (r *Response) SetError(err error) {
+	r.err = err
+}
+
+func (r *Response) statusText() string {
+	if r.streaming {
+		return fmt.Sprintf("streaming, %d messages", r.stream.Len())
+	}
+
+	if r.response == nil {
+		return ""
+	}
+
+	status := r.response.Status
+	if r.response.StatusMessage != "" {
+		status += ": " + r.response.StatusMessage
+	}
+	return fmt.Sprintf("%s, %s, %s", status, r.response.TimePassed, humanize.Bytes(uint64(r.response.Size)))
+}
+
+func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if r.err != nil {
+		return component.Message(gtx, component.MessageTypeError, theme, r.err.Error())
+	}
+
+	if r.message != "" {
+		return component.Message(gtx, component.MessageTypeInfo, theme, r.message)
+	}
+
+	if r.response == nil && !r.streaming {
+		return component.Message(gtx, component.MessageTypeInfo, theme, "No response available yet ;)")
+	}
+
+	if r.copyClickable.Clicked(gtx) {
+		r.handleCopy(gtx)
+	}
+
+	return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Tabs.Layout(gtx, theme)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceBetween, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							lb := material.Label(theme.Material(), theme.TextSize, r.statusText())
+							lb.Color = theme.ResponseStatusColor
+							if r.response != nil && r.response.Status != "OK" {
+								lb.Color = theme.ErrorColor
+							}
+							return lb.Layout(gtx)
+						})
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &r.copyClickable, widgets.CopyIcon, widgets.IconPositionStart, "Copy")
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch r.Tabs.Selected() {
+				case 1:
+					return r.headers.Layout(gtx, theme)
+				case 2:
+					return r.trailers.Layout(gtx, theme)
+				default:
+					return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						if r.streaming || r.stream.Len() > 0 {
+							return r.stream.Layout(gtx, theme)
+						}
+
+						if !r.isBodyUpdated {
+							r.jsonViewer.SetData(r.body)
+							r.isBodyUpdated = true
+						}
+						return r.jsonViewer.Layout(gtx, theme)
+					})
+				}
+			}),
+		)
+	})
+}
+
+func (r *Response) handleCopy(gtx layout.Context) {
+	if r.onCopyResponse == nil {
+		return
+	}
+
+	switch r.Tabs.Selected() {
+	case 1:
+		r.onCopyResponse(gtx, "Metadata", domain.KeyValuesToText(r.headers.GetData()))
+	case 2:
+		r.onCopyResponse(gtx, "Trailers", domain.KeyValuesToText(r.trailers.GetData()))
+	default:
+		if r.stream.Len() > 0 {
+			r.onCopyResponse(gtx, "Messages", r.stream.Text())
+			return
+		}
+		r.onCopyResponse(gtx, "Response", r.body)
+	}
+}
@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const (
+	settingsDeadline       = "Deadline"
+	settingsServerName     = "Server Name"
+	settingsCACertPath     = "CA Cert Path"
+	settingsClientCertPath = "Client Cert Path"
+	settingsClientKeyPath  = "Client Key Path"
+)
+
+// Settings are the transport and the deadline of the calls of a request.
+type Settings struct {
+	Form *component.Form
+
+	Transport     *widgets.DropDown
+	SkipTLSVerify *widgets.DropDown
+
+	onChange func(settings domain.GRPCSettings)
+}
+
+func NewSettings(settings domain.GRPCSettings, theme *chapartheme.Theme) *Settings {
+	s := &Settings{
+		Form: component.NewForm([]*component.Field{
+			{Label: settingsDeadline, Value: ""},
+			{Label: settingsServerName, Value: ""},
+			{Label: settingsCACertPath, Value: ""},
+			{Label: settingsClientCertPath, Value: ""},
+			{Label: settingsClientKeyPath, Value: ""},
+		}),
+		Transport: widgets.NewDropDown(theme,
+			widgets.NewDropDownOption("Plaintext").WithValue(domain.GRPCTransportPlaintext),
+			widgets.NewDropDownOption("TLS").WithValue(domain.GRPCTransportTLS),
+			widgets.NewDropDownOption("mTLS").WithValue(domain.GRPCTransportMTLS),
+		),
+		SkipTLSVerify: widgets.NewDropDown(theme,
+			widgets.NewDropDownOption("No").WithValue("false"),
+			widgets.NewDropDownOption("Yes").WithValue("true"),
+		),
+	}
+	s.Transport.MinWidth = unit.Dp(162)
+	s.SkipTLSVerify.MinWidth = unit.Dp(162)
+
+	values := map[string]string{
+		settingsServerName:     settings.ServerName,
+		settingsCACertPath:     settings.CACertPath,
+		settingsClientCertPath: settings.ClientCertPath,
+		settingsClientKeyPath:  settings.ClientKeyPath,
+	}
+	if settings.Deadline > 0 {
+		values[settingsDeadline] = settings.Deadline.String()
+	}
+	s.Form.SetValues(values)
+
+	transport := settings.Transport
+	if transport == "" {
+		transport = domain.GRPCTransportPlaintext
+	}
+	s.Transport.SetSelectedByValue(transport)
+
+	if settings.InsecureSkipVerify {
+		s.SkipTLSVerify.SetSelectedByValue("true")
+	}
+
+	return s
+}
+
+func (s *Settings) SetOnChange(f func(settings domain.GRPCSettings)) {
+	s.onChange = f
+
+	s.Form.SetOnChange(func(values map[string]string) {
+		s.onChange(s.getSettings())
+	})
+
+	s.Transport.SetOnChanged(func(value string) {
+		s.onChange(s.getSettings())
+	})
+
+	s.SkipTLSVerify.SetOnChanged(func(value string) {
+		s.onChange(s.getSettings())
+	})
+}
+
+func (s *Settings) getSettings() domain.GRPCSettings {
+	values := s.Form.GetValues()
+
+	settings := domain.GRPCSettings{
+		Transport:          s.Transport.GetSelected().Value,
+		InsecureSkipVerify: s.SkipTLSVerify.GetSelected().Value == "true",
+		ServerName:         values[settingsServerName],
+		CACertPath:         values[settingsCACertPath],
+		ClientCertPath:     values[settingsClientCertPath],
+		ClientKeyPath:      values[settingsClientKeyPath],
+	}
+
+	// invalid values are ignored, so there is no deadline
+	if deadline, err := time.ParseDuration(values[settingsDeadline]); err == nil && deadline > 0 {
+		settings.Deadline = deadline
+	}
+
+	return settings
+}
+
+func (s *Settings) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	dropDown := func(label string, d *widgets.DropDown) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(130)
+						return material.Label(theme.Material(), theme.TextSize, label).Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return d.Layout(gtx, theme)
+					}),
+				)
+			})
+		})
+	}
+
+	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Start}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					l := material.Label(theme.Material(), theme.TextSize, "The deadline covers the whole call, e.g. 30s. The client certificate and key are used with mTLS")
+					l.Color = theme.TextColor
+					return l.Layout(gtx)
+				})
+			}),
+			dropDown("Transport", s.Transport),
+			dropDown("Skip TLS Verify", s.SkipTLSVerify),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return s.Form.Layout(gtx, theme)
+			}),
+		)
+	})
+}
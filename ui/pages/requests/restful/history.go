@@ -0,0 +1,323 @@
+package restful
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"net/http"
+	"strings"
+	"sync"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/diff"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+	"github.com/dustin/go-humanize"
+)
+
+const historyTimeLayout = "2006-01-02 15:04:05"
+
+var (
+	diffDeleteColor = color.NRGBA{R: 0xe5, G: 0x39, B: 0x35, A: 0x40}
+	diffInsertColor = color.NRGBA{R: 0x43, G: 0xa0, B: 0x47, A: 0x40}
+)
+
+// History lists the past responses of a request, a response can be viewed on its own
+// or two of them compared side by side.
+type History struct {
+	mx      *sync.Mutex
+	entries []*historyEntry
+	list    *widget.List
+
+	clearButton   widget.Clickable
+	compareButton widget.Clickable
+	backButton    widget.Clickable
+
+	// viewing is the entry which is shown, it is nil when the list or the diff is shown
+	viewing    *historyEntry
+	jsonViewer *widgets.JsonViewer
+
+	// diffRows are the rows of the compared responses, they are shown when diffTitle is set
+	diffTitle string
+	diffRows  []diff.Row
+	diffList  *widget.List
+
+	onClear func()
+}
+
+type historyEntry struct {
+	response   domain.HTTPResponse
+	selected   widget.Bool
+	viewButton widget.Clickable
+}
+
+func NewHistory() *History {
+	return &History{
+		mx:         &sync.Mutex{},
+		jsonViewer: widgets.NewJsonViewer(),
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+		diffList: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+}
+
+func (h *History) SetOnClear(f func()) {
+	h.onClear = f
+}
+
+// SetResponses replaces the listed responses, they are expected from the newest to the oldest.
+func (h *History) SetResponses(responses []domain.HTTPResponse) {
+	entries := make([]*historyEntry, 0, len(responses))
+	for _, r := range responses {
+		entries = append(entries, &historyEntry{response: r})
+	}
+
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	h.entries = entries
+	h.viewing = nil
+	h.diffTitle = ""
+	h.diffRows = nil
+}
+
+// historyText is the text of a response which is compared, it holds the status, the headers and the body.
+func historyText(r domain.HTTPResponse) string {
+	var b strings.Builder
+	if r.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", r.Error)
+	} else {
+		fmt.Fprintf(&b, "%d %s\n", r.StatusCode, http.StatusText(r.StatusCode))
+	}
+
+	for _, h := range r.Headers {
+		fmt.Fprintf(&b, "%s: %s\n", h.Key, h.Value)
+	}
+
+	b.WriteString("\n")
+	b.WriteString(r.Body)
+	return b.String()
+}
+
+func historySummary(r domain.HTTPResponse) string {
+	status := fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+	if r.Error != "" {
+		status = "Error"
+	}
+
+	env := r.Environment
+	if env == "" {
+		env = "No environment"
+	}
+
+	return fmt.Sprintf("%s, %s, %s, %s, %s", r.Time.Local().Format(historyTimeLayout), env, status, r.Duration, humanize.Bytes(uint64(r.Size)))
+}
+
+func (h *History) selected() []*historyEntry {
+	var out []*historyEntry
+	for _, e := range h.entries {
+		if e.selected.Value {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (h *History) handleClicks(gtx layout.Context) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	if h.backButton.Clicked(gtx) {
+		h.viewing = nil
+		h.diffTitle = ""
+		h.diffRows = nil
+	}
+
+	for _, e := range h.entries {
+		if e.viewButton.Clicked(gtx) {
+			h.viewing = e
+			h.jsonViewer.SetData(e.response.Body)
+		}
+	}
+
+	if h.compareButton.Clicked(gtx) {
+		// the older response is on the left, so the diff reads as what changed since then
+		if selected := h.selected(); len(selected) == 2 {
+			older, newer := selected[1].response, selected[0].response
+			h.diffTitle = fmt.Sprintf("%s  →  %s", older.Time.Local().Format(historyTimeLayout), newer.Time.Local().Format(historyTimeLayout))
+			h.diffRows = diff.SideBySide(historyText(older), historyText(newer))
+		}
+	}
+}
+
+func (h *History) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if h.clearButton.Clicked(gtx) && h.onClear != nil {
+		h.onClear()
+	}
+
+	h.handleClicks(gtx)
+
+	h.mx.Lock()
+	entries := h.entries
+	viewing := h.viewing
+	diffTitle := h.diffTitle
+	h.mx.Unlock()
+
+	if len(entries) == 0 {
+		return component.Message(gtx, component.MessageTypeInfo, theme, "No responses recorded yet")
+	}
+
+	return layout.Inset{Top: unit.Dp(5), Left: unit.Dp(5), Right: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		switch {
+		case viewing != nil:
+			return h.detailLayout(gtx, theme, historySummary(viewing.response), func(gtx layout.Context) layout.Dimensions {
+				if viewing.response.Error != "" {
+					return component.Message(gtx, component.MessageTypeError, theme, viewing.response.Error)
+				}
+				return h.jsonViewer.Layout(gtx, theme)
+			})
+		case diffTitle != "":
+			return h.detailLayout(gtx, theme, diffTitle, func(gtx layout.Context) layout.Dimensions {
+				return h.diffLayout(gtx, theme)
+			})
+		default:
+			return h.listLayout(gtx, theme, entries)
+		}
+	})
+}
+
+// detailLayout shows the content under a title with a back button to the list.
+func (h *History) detailLayout(gtx layout.Context, theme *chapartheme.Theme, title string, content layout.Widget) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &h.backButton, widgets.BackIcon, widgets.IconPositionStart, "Back")
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(10)}.Layout),
+					layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, title).Layout),
+				)
+			})
+		}),
+		layout.Flexed(1, content),
+	)
+}
+
+func (h *History) listLayout(gtx layout.Context, theme *chapartheme.Theme, entries []*historyEntry) layout.Dimensions {
+	selectedCount := 0
+	for _, e := range entries {
+		if e.selected.Value {
+			selectedCount++
+		}
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					text := fmt.Sprintf("%d responses, select two of them to compare", len(entries))
+					return material.Label(theme.Material(), theme.TextSize, text).Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if selectedCount != 2 {
+						return layout.Dimensions{}
+					}
+
+					return layout.Inset{Right: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &h.compareButton, widgets.SwapHoriz, widgets.IconPositionStart, "Compare")
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					})
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					btn := widgets.Button(theme.Material(), &h.clearButton, widgets.DeleteIcon, widgets.IconPositionStart, "Clear")
+					btn.Color = theme.ButtonTextColor
+					return btn.Layout(gtx, theme)
+				}),
+			)
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return material.List(theme.Material(), h.list).Layout(gtx, len(entries), func(gtx layout.Context, i int) layout.Dimensions {
+				e := entries[i]
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						// at most two responses can be compared
+						if !e.selected.Value && selectedCount >= 2 {
+							gtx = gtx.Disabled()
+						}
+						return material.CheckBox(theme.Material(), &e.selected, "").Layout(gtx)
+					}),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return e.viewButton.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							return layout.UniformInset(unit.Dp(5)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+								lb := material.Label(theme.Material(), theme.TextSize, historySummary(e.response))
+								if e.response.Error != "" || e.response.StatusCode >= http.StatusBadRequest {
+									lb.Color = theme.ErrorColor
+								}
+								return lb.Layout(gtx)
+							})
+						})
+					}),
+				)
+			})
+		}),
+	)
+}
+
+func (h *History) diffLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	h.mx.Lock()
+	rows := h.diffRows
+	h.mx.Unlock()
+
+	cell := func(text string, background color.NRGBA) layout.FlexChild {
+		return layout.Flexed(0.5, func(gtx layout.Context) layout.Dimensions {
+			return layout.Background{}.Layout(gtx,
+				func(gtx layout.Context) layout.Dimensions {
+					defer clip.Rect(image.Rectangle{Max: gtx.Constraints.Min}).Push(gtx.Ops).Pop()
+					paint.Fill(gtx.Ops, background)
+					return layout.Dimensions{Size: gtx.Constraints.Min}
+				},
+				func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Constraints.Max.X
+					return layout.Inset{Left: unit.Dp(5), Right: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						lb := material.Label(theme.Material(), theme.TextSize, text)
+						lb.Font.Typeface = "monospace"
+						return lb.Layout(gtx)
+					})
+				},
+			)
+		})
+	}
+
+	return material.List(theme.Material(), h.diffList).Layout(gtx, len(rows), func(gtx layout.Context, i int) layout.Dimensions {
+		row := rows[i]
+
+		left, right := color.NRGBA{}, color.NRGBA{}
+		switch row.Op {
+		case diff.OpDelete:
+			left = diffDeleteColor
+		case diff.OpInsert:
+			right = diffInsertColor
+		case diff.OpChange:
+			left, right = diffDeleteColor, diffInsertColor
+		}
+
+		return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+			cell(row.Left, left),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(4)}.Layout),
+			cell(row.Right, right),
+		)
+	})
+}
@@ -0,0 +1,64 @@
+package restful
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"os"
+
+	"gioui.org/layout"
+	"gioui.org/op/paint"
+	"gioui.org/widget"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+)
+
+// ImagePreview shows image responses, the image is decoded when it is set so the layout stays fast.
+type ImagePreview struct {
+	img paint.ImageOp
+	err error
+}
+
+// isImage reports whether the content type is an image format which can be previewed.
+func isImage(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	}
+	return false
+}
+
+// SetImage decodes the image from data, or from file when it is set as data only holds the beginning of large images.
+func (p *ImagePreview) SetImage(data []byte, file string) {
+	var reader io.Reader = bytes.NewReader(data)
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			p.img, p.err = paint.ImageOp{}, err
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		p.img, p.err = paint.ImageOp{}, err
+		return
+	}
+
+	p.img, p.err = paint.NewImageOp(img), nil
+}
+
+func (p *ImagePreview) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if p.err != nil {
+		return component.Message(gtx, component.MessageTypeError, theme, "failed to decode image: "+p.err.Error())
+	}
+
+	return widget.Image{Src: p.img, Fit: widget.ScaleDown, Position: layout.NW}.Layout(gtx)
+}
@@ -0,0 +1,486 @@
+package restful
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/loadtest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+var (
+	throughputColor = color.NRGBA{R: 0x45, G: 0x89, B: 0xf5, A: 0xff}
+	latencyColor    = color.NRGBA{R: 0xff, G: 0xa7, B: 0x26, A: 0xff}
+)
+
+// chartSeconds is the minimum number of seconds the chart has room for, so the bars of short tests are not too wide.
+const chartSeconds = 30
+
+// LoadTest holds the options of a load test of the request and shows its stats while it runs.
+type LoadTest struct {
+	mx *sync.Mutex
+
+	concurrencyEditor widget.Editor
+	requestsEditor    widget.Editor
+	durationEditor    widget.Editor
+	rpsEditor         widget.Editor
+
+	startButton widget.Clickable
+	stopButton  widget.Clickable
+
+	running bool
+	stats   *loadtest.Stats
+	err     error
+
+	list *widget.List
+
+	onStart func(opts loadtest.Options)
+	onStop  func()
+}
+
+func NewLoadTest() *LoadTest {
+	l := &LoadTest{
+		mx: &sync.Mutex{},
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	for _, editor := range []*widget.Editor{&l.concurrencyEditor, &l.requestsEditor, &l.durationEditor, &l.rpsEditor} {
+		editor.SingleLine = true
+		editor.Filter = "0123456789"
+	}
+	l.concurrencyEditor.SetText("10")
+	l.requestsEditor.SetText("100")
+	l.durationEditor.SetText("0")
+	l.rpsEditor.SetText("0")
+	return l
+}
+
+func (l *LoadTest) SetOnStart(f func(opts loadtest.Options)) {
+	l.onStart = f
+}
+
+func (l *LoadTest) SetOnStop(f func()) {
+	l.onStop = f
+}
+
+// SetStarted clears the stats of the previous test.
+func (l *LoadTest) SetStarted() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.running = true
+	l.stats = nil
+	l.err = nil
+}
+
+func (l *LoadTest) SetStats(stats loadtest.Stats) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.stats = &stats
+}
+
+func (l *LoadTest) SetFinished(stats loadtest.Stats, err error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.running = false
+	l.err = err
+	if err == nil {
+		l.stats = &stats
+	}
+}
+
+func (l *LoadTest) options() loadtest.Options {
+	concurrency, _ := strconv.Atoi(l.concurrencyEditor.Text())
+	requests, _ := strconv.Atoi(l.requestsEditor.Text())
+	duration, _ := strconv.Atoi(l.durationEditor.Text())
+	rps, _ := strconv.Atoi(l.rpsEditor.Text())
+
+	return loadtest.Options{
+		Concurrency: concurrency,
+		Requests:    requests,
+		Duration:    time.Duration(duration) * time.Second,
+		RPS:         rps,
+	}
+}
+
+func (l *LoadTest) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	l.mx.Lock()
+	running := l.running
+	stats := l.stats
+	err := l.err
+	l.mx.Unlock()
+
+	if l.startButton.Clicked(gtx) && !running && l.onStart != nil {
+		go l.onStart(l.options())
+	}
+
+	if l.stopButton.Clicked(gtx) && running && l.onStop != nil {
+		l.onStop()
+	}
+
+	sections := []layout.Widget{
+		func(gtx layout.Context) layout.Dimensions {
+			return l.optionsLayout(gtx, theme, running)
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return material.Label(theme.Material(), theme.TextSize, loadTestSummary(stats, running)).Layout(gtx)
+			})
+		},
+	}
+
+	switch {
+	case err != nil:
+		sections = append(sections, func(gtx layout.Context) layout.Dimensions {
+			return component.Message(gtx, component.MessageTypeError, theme, err.Error())
+		})
+	case stats != nil:
+		sections = append(sections,
+			func(gtx layout.Context) layout.Dimensions {
+				return latenciesLayout(gtx, theme, stats)
+			},
+			func(gtx layout.Context) layout.Dimensions {
+				return chartLayout(gtx, theme, stats.Timeline, running)
+			},
+			func(gtx layout.Context) layout.Dimensions {
+				return histogramLayout(gtx, theme, stats.Histogram)
+			},
+			func(gtx layout.Context) layout.Dimensions {
+				return statusCodesLayout(gtx, theme, stats.StatusCodes)
+			},
+			func(gtx layout.Context) layout.Dimensions {
+				return errorsLayout(gtx, theme, stats.ErrorMessages)
+			},
+		)
+	}
+
+	return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return material.List(theme.Material(), l.list).Layout(gtx, len(sections), func(gtx layout.Context, i int) layout.Dimensions {
+			return sections[i](gtx)
+		})
+	})
+}
+
+func (l *LoadTest) optionsLayout(gtx layout.Context, theme *chapartheme.Theme, running bool) layout.Dimensions {
+	field := func(label string, editor *widget.Editor) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Right: unit.Dp(15)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lb := &widgets.LabeledInput{
+					Label:          label,
+					SpaceBetween:   5,
+					MinEditorWidth: unit.Dp(50),
+					Editor:         editor,
+				}
+				return lb.Layout(gtx, theme)
+			})
+		})
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if running {
+				gtx = gtx.Disabled()
+			}
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				field("Concurrency", &l.concurrencyEditor),
+				field("Requests", &l.requestsEditor),
+				field("Duration (s)", &l.durationEditor),
+				field("RPS", &l.rpsEditor),
+			)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						text := "Zero requests or duration is no limit, the test stops at the first limit reached. Zero RPS sends as fast as possible."
+						lb := material.Label(theme.Material(), theme.TextSize, text)
+						lb.Color = theme.TextColor
+						return lb.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if running {
+							btn := widgets.Button(theme.Material(), &l.stopButton, widgets.StopIcon, widgets.IconPositionStart, "Stop")
+							btn.Color = theme.ButtonTextColor
+							return btn.Layout(gtx, theme)
+						}
+
+						btn := widgets.Button(theme.Material(), &l.startButton, widgets.PlayIcon, widgets.IconPositionStart, "Start")
+						btn.Color = theme.ButtonTextColor
+						btn.Background = theme.SendButtonBgColor
+						return btn.Layout(gtx, theme)
+					}),
+				)
+			})
+		}),
+	)
+}
+
+func loadTestSummary(stats *loadtest.Stats, running bool) string {
+	if stats == nil {
+		if running {
+			return "Starting..."
+		}
+		return "Send the request concurrently against the active environment, post requests and tests are not applied"
+	}
+
+	text := fmt.Sprintf("%d requests, %d errors, %.1f req/s in %s", stats.Total, stats.Errors, stats.RPS, stats.Elapsed.Round(time.Millisecond))
+	switch {
+	case running:
+		return "Running... " + text
+	case stats.Stopped:
+		return "Stopped, " + text
+	default:
+		return "Finished, " + text
+	}
+}
+
+// latency rounds the latency to a precision which is readable for both fast and slow responses.
+func latency(d time.Duration) string {
+	if d >= 100*time.Millisecond {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
+
+func sectionTitle(gtx layout.Context, theme *chapartheme.Theme, title string) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return material.Label(theme.Material(), theme.TextSize, title).Layout(gtx)
+	})
+}
+
+func latenciesLayout(gtx layout.Context, theme *chapartheme.Theme, stats *loadtest.Stats) layout.Dimensions {
+	values := []struct {
+		title string
+		value time.Duration
+	}{
+		{"p50", stats.P50},
+		{"p90", stats.P90},
+		{"p99", stats.P99},
+		{"Min", stats.Min},
+		{"Mean", stats.Mean},
+		{"Max", stats.Max},
+	}
+
+	children := make([]layout.FlexChild, 0, len(values))
+	for _, v := range values {
+		children = append(children, layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					lb := material.Label(theme.Material(), theme.TextSize, v.title)
+					lb.Color = theme.TextColor
+					return lb.Layout(gtx)
+				}),
+				layout.Rigid(material.Label(theme.Material(), theme.TextSize, latency(v.value)).Layout),
+			)
+		}))
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return sectionTitle(gtx, theme, "Latency")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, children...)
+		}),
+	)
+}
+
+// chartLayout draws the requests of every second as bars and their mean latency as a line, each on its own scale.
+// the current second of a running test is left out as it is not complete yet.
+func chartLayout(gtx layout.Context, theme *chapartheme.Theme, timeline []loadtest.Point, running bool) layout.Dimensions {
+	if running && len(timeline) > 0 {
+		timeline = timeline[:len(timeline)-1]
+	}
+
+	peakRequests, peakLatency := 0, time.Duration(0)
+	for _, p := range timeline {
+		peakRequests = max(peakRequests, p.Requests)
+		peakLatency = max(peakLatency, p.MeanLatency)
+	}
+
+	title := fmt.Sprintf("Requests per second (bars, peak %d) and mean latency (line, peak %s)", peakRequests, latency(peakLatency))
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return sectionTitle(gtx, theme, title)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			width := gtx.Constraints.Max.X
+			height := gtx.Dp(unit.Dp(120))
+			size := image.Pt(width, height)
+
+			paint.FillShape(gtx.Ops, theme.BorderColor, clip.Stroke{Path: clip.Rect{Max: size}.Path(), Width: 1}.Op())
+			if len(timeline) == 0 || peakRequests == 0 {
+				return layout.Dimensions{Size: size}
+			}
+
+			slot := float32(width) / float32(max(len(timeline), chartSeconds))
+			for i, p := range timeline {
+				barHeight := int(float32(height) * float32(p.Requests) / float32(peakRequests))
+				x0 := int(float32(i)*slot) + 1
+				x1 := max(int(float32(i+1)*slot)-1, x0+1)
+				paint.FillShape(gtx.Ops, throughputColor, clip.Rect(image.Rect(x0, height-barHeight, x1, height)).Op())
+			}
+
+			if peakLatency > 0 && len(timeline) > 1 {
+				var path clip.Path
+				path.Begin(gtx.Ops)
+				for i, p := range timeline {
+					pt := f32.Pt(float32(i)*slot+slot/2, float32(height)-float32(height)*float32(p.MeanLatency)/float32(peakLatency))
+					if i == 0 {
+						path.MoveTo(pt)
+					} else {
+						path.LineTo(pt)
+					}
+				}
+				paint.FillShape(gtx.Ops, latencyColor, clip.Stroke{Path: path.End(), Width: float32(gtx.Dp(unit.Dp(2)))}.Op())
+			}
+
+			return layout.Dimensions{Size: size}
+		}),
+	)
+}
+
+// barRow lays out a row of a distribution with its title, count and a bar on the scale of the largest count.
+func barRow(gtx layout.Context, theme *chapartheme.Theme, title string, count, peak int, barColor color.NRGBA) layout.Dimensions {
+	column := func(width unit.Dp, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Dp(width)
+			return material.Label(theme.Material(), theme.TextSize, text).Layout(gtx)
+		})
+	}
+
+	return layout.Inset{Top: unit.Dp(3), Bottom: unit.Dp(3)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			column(170, title),
+			column(70, strconv.Itoa(count)),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				width := gtx.Constraints.Max.X
+				height := gtx.Dp(unit.Dp(12))
+				barWidth := 0
+				if peak > 0 {
+					barWidth = int(float64(width) * float64(count) / float64(peak))
+				}
+				if count > 0 {
+					barWidth = max(barWidth, 1)
+				}
+
+				paint.FillShape(gtx.Ops, barColor, clip.Rect(image.Rect(0, 0, barWidth, height)).Op())
+				return layout.Dimensions{Size: image.Pt(width, height)}
+			}),
+		)
+	})
+}
+
+func histogramLayout(gtx layout.Context, theme *chapartheme.Theme, buckets []loadtest.Bucket) layout.Dimensions {
+	if len(buckets) == 0 {
+		return layout.Dimensions{}
+	}
+
+	peak := 0
+	for _, b := range buckets {
+		peak = max(peak, b.Count)
+	}
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return sectionTitle(gtx, theme, "Latency histogram")
+		}),
+	}
+	for _, b := range buckets {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return barRow(gtx, theme, fmt.Sprintf("%s - %s", latency(b.From), latency(b.To)), b.Count, peak, throughputColor)
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
+
+func statusCodesLayout(gtx layout.Context, theme *chapartheme.Theme, statusCodes map[int]int) layout.Dimensions {
+	if len(statusCodes) == 0 {
+		return layout.Dimensions{}
+	}
+
+	codes := make([]int, 0, len(statusCodes))
+	peak := 0
+	for code, count := range statusCodes {
+		codes = append(codes, code)
+		peak = max(peak, count)
+	}
+	sort.Ints(codes)
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return sectionTitle(gtx, theme, "Status codes")
+		}),
+	}
+	for _, code := range codes {
+		barColor := testPassedColor
+		if code >= http.StatusBadRequest {
+			barColor = theme.ErrorColor
+		}
+
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return barRow(gtx, theme, fmt.Sprintf("%d %s", code, http.StatusText(code)), statusCodes[code], peak, barColor)
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
+
+func errorsLayout(gtx layout.Context, theme *chapartheme.Theme, messages map[string]int) layout.Dimensions {
+	if len(messages) == 0 {
+		return layout.Dimensions{}
+	}
+
+	// the most frequent errors come first
+	keys := make([]string, 0, len(messages))
+	for k := range messages {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if messages[keys[i]] != messages[keys[j]] {
+			return messages[keys[i]] > messages[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return sectionTitle(gtx, theme, "Errors")
+		}),
+	}
+	for _, k := range keys {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(3), Bottom: unit.Dp(3)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(70)
+						lb := material.Label(theme.Material(), theme.TextSize, strconv.Itoa(messages[k]))
+						lb.Color = theme.ErrorColor
+						return lb.Layout(gtx)
+					}),
+					layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, k).Layout),
+				)
+			})
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
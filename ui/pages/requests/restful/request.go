@@ -14,11 +14,15 @@ type Request struct {
 
 	PreRequest  *component.PrePostRequest
 	PostRequest *component.PrePostRequest
+	Tests       *component.Assertions
+	LoadTest    *LoadTest
+	Runner      *component.Runner
 
-	Body    *Body
-	Params  *Params
-	Headers *Headers
-	Auth    *Auth
+	Body     *Body
+	Params   *Params
+	Headers  *Headers
+	Auth     *Auth
+	Settings *Settings
 }
 
 func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
@@ -28,27 +32,35 @@ func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
 			{Title: "Body"},
 			{Title: "Auth"},
 			{Title: "Headers"},
-			//	{Title: "Pre Request"},
+			{Title: "Pre Request"},
 			{Title: "Post Request"},
+			{Title: "Tests"},
+			{Title: "Settings"},
+			{Title: "Runner"},
+			{Title: "Load Test"},
 		}, nil),
-		//PreRequest: component.NewPrePostRequest([]component.Option{
-		//	{Title: "None", Value: domain.PostRequestTypeNone},
-		//	{Title: "Python", Value: domain.PostRequestTypePythonScript, Type: component.TypeScript, Hint: "Write your pre request python script here"},
-		//	{Title: "Shell Script", Value: domain.PostRequestTypeSSHTunnel, Type: component.TypeScript, Hint: "Write your pre request shell script here"},
-		//	{Title: "Kubectl tunnel", Value: domain.PostRequestTypeK8sTunnel, Type: component.TypeScript, Hint: "Run kubectl port-forward command"},
-		//	{Title: "SSH tunnel", Value: domain.PostRequestTypeSSHTunnel, Type: component.TypeScript, Hint: "Run ssh command"},
-		//}, theme),
+		PreRequest: component.NewPrePostRequest([]component.Option{
+			{Title: "None", Value: domain.PrePostTypeNone},
+			{Title: "Python", Value: domain.PrePostTypePython, Type: component.TypeScript, Hint: "Write your pre request python script here"},
+			{Title: "Shell Script", Value: domain.PrePostTypeShell, Type: component.TypeShellScript, Hint: "Write your pre request shell script here"},
+			{Title: "Kubectl tunnel", Value: domain.PrePostTypeK8sTunnel, Type: component.TypeK8sTunnel},
+			{Title: "SSH tunnel", Value: domain.PrePostTypeSSHTunnel, Type: component.TypeSSHTunnel},
+		}, theme),
 		PostRequest: component.NewPrePostRequest([]component.Option{
 			{Title: "None", Value: domain.PostRequestTypeNone},
-			{Title: "Set Environment Variable", Value: domain.PostRequestTypeSetEnv, Type: component.TypeSetEnv, Hint: "Set environment variable"},
-			//	{Title: "Python", Value: domain.PostRequestTypePythonScript, Type: component.TypeScript, Hint: "Write your post request python script here"},
-			//	{Title: "Shell Script", Value: domain.PostRequestTypeShellScript, Type: component.TypeScript, Hint: "Write your post request shell script here"},
+			{Title: "Set Variables", Value: domain.PostRequestTypeSetEnv, Type: component.TypeSetEnv, Hint: "Set variables from the response"},
+			{Title: "Python", Value: domain.PostRequestTypePythonScript, Type: component.TypeScript, Hint: "Write your post request python script here"},
+			{Title: "Shell Script", Value: domain.PostRequestTypeShellScript, Type: component.TypeShellScript, Hint: "Write your post request shell script here"},
 		}, theme),
+		Tests:    component.NewAssertions(theme, req.Spec.HTTP.Request.Tests),
+		LoadTest: NewLoadTest(),
+		Runner:   component.NewRunner("Run the request with iterations, or an iteration per row of a data file, against the active environment"),
 
-		Body:    NewBody(req.Spec.HTTP.Request.Body, theme),
-		Params:  NewParams(nil, nil),
-		Headers: NewHeaders(nil),
-		Auth:    NewAuth(req.Spec.HTTP.Request.Auth, theme),
+		Body:     NewBody(req.Spec.HTTP.Request.Body, theme),
+		Params:   NewParams(nil, nil),
+		Headers:  NewHeaders(nil),
+		Auth:     NewAuth(req.Spec.HTTP.Request.Auth, theme),
+		Settings: NewSettings(req.Spec.HTTP.Settings, theme),
 	}
 
 	if req != nil && req.Spec != (domain.RequestSpec{}) && req.Spec.HTTP != nil && req.Spec.HTTP.Request != nil {
@@ -56,19 +68,21 @@ func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
 		r.Params.SetPathParams(req.Spec.HTTP.Request.PathParams)
 		r.Headers.SetHeaders(req.Spec.HTTP.Request.Headers)
 
-		//if req.Spec.HTTP.Request.PreRequest != (domain.PreRequest{}) {
-		//	r.PreRequest.SetSelectedDropDown(req.Spec.HTTP.Request.PreRequest.Type)
-		//	r.PreRequest.SetCode(req.Spec.HTTP.Request.PreRequest.Script)
-		//}
+		if req.Spec.HTTP.Request.PreRequest != (domain.PreRequest{}) {
+			r.PreRequest.SetSelectedDropDown(req.Spec.HTTP.Request.PreRequest.Type)
+			r.PreRequest.SetCode(req.Spec.HTTP.Request.PreRequest.Script)
+			r.PreRequest.SetTimeout(req.Spec.HTTP.Request.PreRequest.Timeout)
+			r.PreRequest.SetSSHTunnel(req.Spec.HTTP.Request.PreRequest.SShTunnel)
+			r.PreRequest.SetKubernetesTunnel(req.Spec.HTTP.Request.PreRequest.KubernetesTunnel)
+		}
 
-		if req.Spec.HTTP.Request.PostRequest != (domain.PostRequest{}) {
+		if !req.Spec.HTTP.Request.PostRequest.IsEmpty() {
 			r.PostRequest.SetSelectedDropDown(req.Spec.HTTP.Request.PostRequest.Type)
-			//	r.PostRequest.SetCode(req.Spec.HTTP.Request.PostRequest.Script)
+			r.PostRequest.SetCode(req.Spec.HTTP.Request.PostRequest.Script)
+			r.PostRequest.SetTimeout(req.Spec.HTTP.Request.PostRequest.Timeout)
 		}
 
-		if req.Spec.HTTP.Request.PostRequest.PostRequestSet != (domain.PostRequestSet{}) {
-			r.PostRequest.SetPostRequestSetValues(req.Spec.HTTP.Request.PostRequest.PostRequestSet)
-		}
+		r.PostRequest.SetPostRequestRules(req.Spec.HTTP.Request.PostRequest.GetRules())
 	}
 
 	return r
@@ -86,10 +100,12 @@ func (r *Request) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Di
 			}),
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 				switch r.Tabs.SelectedTab().Title {
-				//case "Pre Request":
-				//	return r.PreRequest.Layout(gtx, theme)
+				case "Pre Request":
+					return r.PreRequest.Layout(gtx, theme)
 				case "Post Request":
 					return r.PostRequest.Layout(gtx, theme)
+				case "Tests":
+					return r.Tests.Layout(gtx, theme)
 				case "Params":
 					return r.Params.Layout(gtx, theme)
 				case "Headers":
@@ -98,6 +114,12 @@ func (r *Request) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Di
 					return r.Auth.Layout(gtx, theme)
 				case "Body":
 					return r.Body.Layout(gtx, theme)
+				case "Settings":
+					return r.Settings.Layout(gtx, theme)
+				case "Runner":
+					return r.Runner.Layout(gtx, theme)
+				case "Load Test":
+					return r.LoadTest.Layout(gtx, theme)
 				default:
 					return layout.Dimensions{}
 				}
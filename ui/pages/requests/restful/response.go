@@ -1,11 +1,13 @@
 package restful
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
 
 	"gioui.org/layout"
 	"gioui.org/unit"
@@ -22,6 +24,7 @@ type Response struct {
 	Tabs       *widgets.Tabs
 
 	copyClickable widget.Clickable
+	saveClickable widget.Clickable
 
 	responseCode int
 	duration     time.Duration
@@ -29,16 +32,32 @@ type Response struct {
 
 	responseHeaders *component.ValuesTable
 	responseCookies *component.ValuesTable
+	timings         *Timings
+	tests           *TestResults
+	history         *History
 
 	response string
 	message  string
 	err      error
 
+	// body is the raw body, it only holds the beginning of truncated responses whose whole body is in bodyFile
+	body        []byte
+	bodyFile    string
+	contentType string
+	binary      bool
+	truncated   bool
+	image       *ImagePreview
+
 	onCopyResponse func(gtx layout.Context, dataType, data string)
+	onSaveResponse func()
 
 	isResponseUpdated   bool
 	responseIsAvailable bool
 	jsonViewer          *widgets.JsonViewer
+
+	// streaming is true while the events of a streamed response are arriving
+	streaming bool
+	stream    *Stream
 }
 
 func NewResponse(theme *chapartheme.Theme) *Response {
@@ -57,10 +76,18 @@ func NewResponse(theme *chapartheme.Theme) *Response {
 			{Title: "Body"},
 			{Title: "Headers"},
 			{Title: "Cookies"},
+			{Title: "Timings"},
+			{Title: "Tests"},
+			{Title: "History"},
 		}, nil),
 		jsonViewer:      widgets.NewJsonViewer(),
 		responseHeaders: component.NewValuesTable("Headers", nil),
 		responseCookies: component.NewValuesTable("Cookies", nil),
+		stream:          NewStream(),
+		timings:         NewTimings(),
+		tests:           NewTestResults(),
+		history:         NewHistory(),
+		image:           &ImagePreview{},
 	}
 	return r
 }
@@ -69,12 +96,77 @@ func (r *Response) SetOnCopyResponse(f func(gtx layout.Context, dataType, data s
 	r.onCopyResponse = f
 }
 
+func (r *Response) SetOnClearHistory(f func()) {
+	r.history.SetOnClear(f)
+}
+
+// SetHistory sets the past responses listed in the history tab.
+func (r *Response) SetHistory(responses []domain.HTTPResponse) {
+	r.history.SetResponses(responses)
+}
+
+func (r *Response) SetOnSaveResponse(f func()) {
+	r.onSaveResponse = f
+}
+
+// SetBody sets the raw body of the response, images are decoded to be previewed.
+func (r *Response) SetBody(detail domain.HTTPResponseDetail) {
+	r.body = detail.Body
+	r.bodyFile = detail.BodyFile
+	r.contentType = detail.ContentType
+	r.binary = detail.Binary
+	r.truncated = detail.Truncated
+
+	if r.binary && isImage(r.contentType) {
+		r.image.SetImage(r.body, r.bodyFile)
+	}
+}
+
 func (r *Response) SetResponse(response string) {
 	r.response = response
 	r.err = nil
 	r.message = ""
 	r.isResponseUpdated = false
 	r.responseIsAvailable = true
+	r.streaming = false
+}
+
+// SetStreamStarted shows the response as a list of events, which are added by AddStreamEvent as they arrive.
+func (r *Response) SetStreamStarted(code int, headers []domain.KeyValue) {
+	r.stream.Clear()
+	r.streaming = true
+	r.response = ""
+	r.err = nil
+	r.message = ""
+	r.responseIsAvailable = true
+	r.responseCode = code
+	r.responseHeaders.SetData(headers)
+	r.timings.SetTimings(domain.HTTPTimings{})
+	r.tests.SetResults(nil)
+	r.SetBody(domain.HTTPResponseDetail{})
+}
+
+func (r *Response) AddStreamEvent(event rest.StreamEvent) {
+	r.stream.AddEvent(event)
+}
+
+// ClearStream removes the events of the previous response.
+func (r *Response) ClearStream() {
+	r.stream.Clear()
+	r.streaming = false
+}
+
+func (r *Response) GetResponse() *domain.HTTPResponseDetail {
+	return &domain.HTTPResponseDetail{
+		Response:    r.response,
+		Headers:     r.responseHeaders.GetData(),
+		Cookies:     r.responseCookies.GetData(),
+		ContentType: r.contentType,
+		Body:        r.body,
+		Binary:      r.binary,
+		Truncated:   r.truncated,
+		BodyFile:    r.bodyFile,
+	}
 }
 
 func (r *Response) SetStatusParams(code int, duration time.Duration, size int) {
@@ -83,6 +175,14 @@ func (r *Response) SetStatusParams(code int, duration time.Duration, size int) {
 	r.responseSize = size
 }
 
+func (r *Response) SetTimings(timings domain.HTTPTimings) {
+	r.timings.SetTimings(timings)
+}
+
+func (r *Response) SetTestResults(results []domain.AssertionResult) {
+	r.tests.SetResults(results)
+}
+
 func (r *Response) SetHeaders(headers []domain.KeyValue) {
 	r.responseHeaders.SetData(headers)
 }
@@ -101,6 +201,10 @@ func (r *Response) SetCookies(cookies []domain.KeyValue) {
 
 func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
 	if r.err != nil {
+		var canceledErr *rest.CanceledError
+		if errors.As(r.err, &canceledErr) {
+			return component.Message(gtx, component.MessageTypeWarning, theme, r.err.Error())
+		}
 		return component.Message(gtx, component.MessageTypeError, theme, r.err.Error())
 	}
 
@@ -116,6 +220,10 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 		r.handleCopy(gtx)
 	}
 
+	if r.saveClickable.Clicked(gtx) && r.onSaveResponse != nil {
+		r.onSaveResponse()
+	}
+
 	inset := layout.Inset{Top: unit.Dp(10)}
 	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{
@@ -128,8 +236,13 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 				return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceBetween, Alignment: layout.Middle}.Layout(gtx,
 					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
 						return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							status := formatStatus(r.responseCode, r.duration, uint64(r.responseSize))
+							if r.streaming {
+								status = fmt.Sprintf("%d %s, streaming, %d events", r.responseCode, http.StatusText(r.responseCode), r.stream.Len())
+							}
+
 							l := material.LabelStyle{
-								Text:     formatStatus(r.responseCode, r.duration, uint64(r.responseSize)),
+								Text:     status,
 								Color:    theme.ResponseStatusColor,
 								TextSize: theme.TextSize,
 								Shaper:   theme.Shaper,
@@ -138,6 +251,12 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 							return l.Layout(gtx)
 						})
 					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &r.saveClickable, widgets.SaveIcon, widgets.IconPositionStart, "Save")
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 						btn := widgets.Button(theme.Material(), &r.copyClickable, widgets.CopyIcon, widgets.IconPositionStart, "Copy")
 						btn.Color = theme.ButtonTextColor
@@ -151,14 +270,20 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 					return r.responseHeaders.Layout(gtx, theme)
 				case 2:
 					return r.responseCookies.Layout(gtx, theme)
+				case 3:
+					return r.timings.Layout(gtx, theme)
+				case 4:
+					return r.tests.Layout(gtx, theme)
+				case 5:
+					return r.history.Layout(gtx, theme)
 				default:
 					return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-						if !r.isResponseUpdated {
-							r.jsonViewer.SetData(r.response)
-							r.isResponseUpdated = true
+						// streamed responses keep showing their events once they are complete
+						if r.streaming || r.stream.Len() > 0 {
+							return r.stream.Layout(gtx, theme)
 						}
 
-						return r.jsonViewer.Layout(gtx, theme)
+						return r.bodyLayout(gtx, theme)
 					})
 				}
 			}),
@@ -166,6 +291,42 @@ func (r *Response) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.D
 	})
 }
 
+// bodyLayout shows images as pictures, other binary bodies as a notice and text bodies in the viewer,
+// with a notice above the text when only its beginning is shown.
+func (r *Response) bodyLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if r.binary {
+		if isImage(r.contentType) {
+			return r.image.Layout(gtx, theme)
+		}
+
+		text := fmt.Sprintf("Binary response (%s, %s), use Save to write it to a file", r.contentType, humanize.Bytes(uint64(r.responseSize)))
+		return component.Message(gtx, component.MessageTypeInfo, theme, text)
+	}
+
+	if !r.isResponseUpdated {
+		r.jsonViewer.SetData(r.response)
+		r.isResponseUpdated = true
+	}
+
+	if !r.truncated {
+		return r.jsonViewer.Layout(gtx, theme)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				text := fmt.Sprintf("The response is %s, only the first %s is shown, use Save to get all of it", humanize.Bytes(uint64(r.responseSize)), humanize.Bytes(uint64(len(r.body))))
+				lb := material.Label(theme.Material(), theme.TextSize, text)
+				lb.Color = theme.WarningColor
+				return lb.Layout(gtx)
+			})
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return r.jsonViewer.Layout(gtx, theme)
+		}),
+	)
+}
+
 func formatStatus(statueCode int, duration time.Duration, size uint64) string {
 	return fmt.Sprintf("%d %s, %s, %s", statueCode, http.StatusText(statueCode), duration, humanize.Bytes(size))
 }
@@ -176,7 +337,15 @@ func (r *Response) handleCopy(gtx layout.Context) {
 		r.onCopyResponse(gtx, "Headers", domain.KeyValuesToText(r.responseHeaders.GetData()))
 	case 2:
 		r.onCopyResponse(gtx, "Cookies", domain.KeyValuesToText(r.responseCookies.GetData()))
+	case 3:
+		r.onCopyResponse(gtx, "Timings", r.timings.Text())
+	case 4:
+		r.onCopyResponse(gtx, "Tests", r.tests.Text())
 	default:
+		if r.stream.Len() > 0 {
+			r.onCopyResponse(gtx, "Events", r.stream.Text())
+			return
+		}
 		r.onCopyResponse(gtx, "Response", r.response)
 	}
 }
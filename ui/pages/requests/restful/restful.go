@@ -1,10 +1,15 @@
 package restful
 
 import (
+	"time"
+
 	"gioui.org/layout"
 	"gioui.org/unit"
 	giox "gioui.org/x/component"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/loadtest"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/runner"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/pages/requests/component"
 	"github.com/chapar-rest/chapar/ui/widgets"
@@ -25,6 +30,7 @@ type Restful struct {
 	onSave        func(id string)
 	onDataChanged func(id string, data any)
 	onSubmit      func(id string)
+	onCancel      func(id string)
 }
 
 func New(req *domain.Request, theme *chapartheme.Theme) *Restful {
@@ -47,17 +53,17 @@ func New(req *domain.Request, theme *chapartheme.Theme) *Restful {
 	return r
 }
 
-func (r *Restful) SetPostRequestSetValues(set domain.PostRequestSet) {
-	r.Request.PostRequest.SetPostRequestSetValues(set)
+func (r *Restful) SetPostRequestRules(rules []domain.PostRequestSet) {
+	r.Request.PostRequest.SetPostRequestRules(rules)
 }
 
-func (r *Restful) SetPostRequestSetPreview(preview string) {
-	r.Request.PostRequest.SetPreview(preview)
+func (r *Restful) SetPostRequestRulePreviews(previews []string) {
+	r.Request.PostRequest.SetPreviews(previews)
 }
 
-func (r *Restful) SetOnPostRequestSetChanged(f func(id string, statusCode int, item, from, fromKey string)) {
-	r.Request.PostRequest.SetOnPostRequestSetChanged(func(statusCode int, item, from, fromKey string) {
-		f(r.Req.MetaData.ID, statusCode, item, from, fromKey)
+func (r *Restful) SetOnPostRequestRulesChanged(f func(id string, rules []domain.PostRequestSet)) {
+	r.Request.PostRequest.SetOnPostRequestRulesChanged(func(rules []domain.PostRequestSet) {
+		f(r.Req.MetaData.ID, rules)
 	})
 }
 
@@ -69,6 +75,10 @@ func (r *Restful) SetOnSubmit(f func(id string)) {
 	r.onSubmit = f
 }
 
+func (r *Restful) SetOnCancel(f func(id string)) {
+	r.onCancel = f
+}
+
 func (r *Restful) SetURL(url string) {
 	r.AddressBar.SetURL(url)
 }
@@ -101,6 +111,80 @@ func (r *Restful) SetOnTitleChanged(f func(title string)) {
 	r.Breadcrumb.SetOnTitleChanged(f)
 }
 
+func (r *Restful) SetResponseHistory(responses []domain.HTTPResponse) {
+	r.Response.SetHistory(responses)
+}
+
+func (r *Restful) SetOnClearResponseHistory(f func(id string)) {
+	r.Response.SetOnClearHistory(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *Restful) SetOnSaveResponse(f func(id string)) {
+	r.Response.SetOnSaveResponse(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *Restful) SetOnLoadTest(f func(id string, opts loadtest.Options)) {
+	r.Request.LoadTest.SetOnStart(func(opts loadtest.Options) {
+		f(r.Req.MetaData.ID, opts)
+	})
+}
+
+func (r *Restful) SetOnStopLoadTest(f func(id string)) {
+	r.Request.LoadTest.SetOnStop(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *Restful) SetLoadTestStarted() {
+	r.Request.LoadTest.SetStarted()
+}
+
+func (r *Restful) SetLoadTestStats(stats loadtest.Stats) {
+	r.Request.LoadTest.SetStats(stats)
+}
+
+func (r *Restful) SetLoadTestFinished(stats loadtest.Stats, err error) {
+	r.Request.LoadTest.SetFinished(stats, err)
+}
+
+func (r *Restful) SetOnRun(f func(id string, opts runner.Options)) {
+	r.Request.Runner.SetOnRun(func(opts runner.Options) {
+		f(r.Req.MetaData.ID, opts)
+	})
+}
+
+func (r *Restful) SetOnStopRun(f func(id string)) {
+	r.Request.Runner.SetOnStop(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *Restful) SetOnSelectRunDataFile(f func(id string)) {
+	r.Request.Runner.SetOnSelectDataFile(func() {
+		f(r.Req.MetaData.ID)
+	})
+}
+
+func (r *Restful) SetRunDataFile(path string) {
+	r.Request.Runner.SetDataFile(path)
+}
+
+func (r *Restful) SetRunStarted() {
+	r.Request.Runner.SetRunStarted()
+}
+
+func (r *Restful) AddRunResult(result runner.Result) {
+	r.Request.Runner.AddResult(result)
+}
+
+func (r *Restful) SetRunFinished(report *runner.Report, err error) {
+	r.Request.Runner.SetRunFinished(report, err)
+}
+
 func (r *Restful) SetOnCopyResponse(f func(gtx layout.Context, dataType, data string)) {
 	r.Response.SetOnCopyResponse(f)
 }
@@ -114,23 +198,37 @@ func (r *Restful) SetHTTPResponse(detail domain.HTTPResponseDetail) {
 	r.Response.SetResponse(detail.Response)
 	r.Response.SetHeaders(detail.Headers)
 	r.Response.SetCookies(detail.Cookies)
+	r.Response.SetTimings(detail.Timings)
+	r.Response.SetTestResults(detail.TestResults)
+	r.Response.SetBody(detail)
 	r.Response.SetStatusParams(detail.StatusCode, detail.Duration, detail.Size)
 }
 
 func (r *Restful) GetHTTPResponse() *domain.HTTPResponseDetail {
-	return &domain.HTTPResponseDetail{
-		Response: r.Response.response,
-		Headers:  r.Response.responseHeaders.GetData(),
-		Cookies:  r.Response.responseCookies.GetData(),
-	}
+	return r.Response.GetResponse()
 }
 
 func (r *Restful) ShowSendingRequestLoading() {
+	r.Response.ClearStream()
+	r.Response.SetError(nil)
 	r.Response.SetMessage("Sending request...")
+	r.AddressBar.SetSendingRequest(true)
 }
 
 func (r *Restful) HideSendingRequestLoading() {
 	r.Response.SetMessage("")
+	r.AddressBar.SetSendingRequest(false)
+	r.AddressBar.SetButtonTexts("Send", "Cancel")
+}
+
+// SetStreamStarted shows the streamed response while it arrives, the request can be stopped with the cancel button.
+func (r *Restful) SetStreamStarted(code int, headers []domain.KeyValue) {
+	r.Response.SetStreamStarted(code, headers)
+	r.AddressBar.SetButtonTexts("Send", "Stop")
+}
+
+func (r *Restful) AddStreamEvent(event rest.StreamEvent) {
+	r.Response.AddStreamEvent(event)
 }
 
 func (r *Restful) SetOnSave(f func(id string)) {
@@ -171,6 +269,12 @@ func (r *Restful) setupHooks() {
 		r.onSubmit(r.Req.MetaData.ID)
 	})
 
+	r.AddressBar.SetOnCancel(func() {
+		if r.onCancel != nil {
+			r.onCancel(r.Req.MetaData.ID)
+		}
+	})
+
 	r.Request.Params.SetOnChange(func(queryParams []domain.KeyValue, urlParams []domain.KeyValue) {
 		r.Req.Spec.HTTP.Request.QueryParams = queryParams
 		r.Req.Spec.HTTP.Request.PathParams = urlParams
@@ -187,15 +291,35 @@ func (r *Restful) setupHooks() {
 		r.onDataChanged(r.Req.MetaData.ID, r.Req)
 	})
 
-	//r.Request.PreRequest.SetOnDropDownChanged(func(selected string) {
-	//	r.Req.Spec.HTTP.Request.PreRequest.Type = selected
-	//	r.onDataChanged(r.Req.MetaData.ID, r.Req)
-	//})
+	r.Request.PreRequest.SetOnDropDownChanged(func(selected string) {
+		r.Req.Spec.HTTP.Request.PreRequest.Type = selected
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
 
-	//r.Request.PreRequest.SetOnScriptChanged(func(code string) {
-	//	r.Req.Spec.HTTP.Request.PreRequest.Script = code
-	//	r.onDataChanged(r.Req.MetaData.ID, r.Req)
-	//})
+	r.Request.PreRequest.SetOnScriptChanged(func(code string) {
+		r.Req.Spec.HTTP.Request.PreRequest.Script = code
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.PreRequest.SetOnKubernetesTunnelChanged(func(tunnel *domain.KubernetesTunnel) {
+		r.Req.Spec.HTTP.Request.PreRequest.KubernetesTunnel = tunnel
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.PreRequest.SetOnSSHTunnelChanged(func(tunnel *domain.SShTunnel) {
+		r.Req.Spec.HTTP.Request.PreRequest.SShTunnel = tunnel
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.PreRequest.SetOnTimeoutChanged(func(timeout time.Duration) {
+		r.Req.Spec.HTTP.Request.PreRequest.Timeout = timeout
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.PostRequest.SetOnTimeoutChanged(func(timeout time.Duration) {
+		r.Req.Spec.HTTP.Request.PostRequest.Timeout = timeout
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
 
 	r.Request.PostRequest.SetOnDropDownChanged(func(selected string) {
 		r.Req.Spec.HTTP.Request.PostRequest.Type = selected
@@ -207,10 +331,20 @@ func (r *Restful) setupHooks() {
 		r.onDataChanged(r.Req.MetaData.ID, r.Req)
 	})
 
+	r.Request.Tests.SetOnChange(func(assertions []domain.Assertion) {
+		r.Req.Spec.HTTP.Request.Tests = assertions
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
 	r.Request.Body.SetOnChange(func(body domain.Body) {
 		r.Req.Spec.HTTP.Request.Body = body
 		r.onDataChanged(r.Req.MetaData.ID, r.Req)
 	})
+
+	r.Request.Settings.SetOnChange(func(settings *domain.HTTPClientSettings) {
+		r.Req.Spec.HTTP.Settings = settings
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
 }
 
 func (r *Restful) SetQueryParams(params []domain.KeyValue) {
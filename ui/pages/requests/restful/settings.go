@@ -0,0 +1,196 @@
+package restful
+
+import (
+	"strconv"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	settingsTimeout      = "Timeout"
+	settingsMaxRedirects = "Max Redirects"
+	settingsCACertPath   = "CA Cert Path"
+	settingsMaxResponse  = "Max Response Size"
+
+	optionDefault = ""
+	optionYes     = "true"
+	optionNo      = "false"
+)
+
+// Settings let the user override the workspace http client settings for a single request.
+type Settings struct {
+	Form *component.Form
+
+	FollowRedirects *widgets.DropDown
+	SkipTLSVerify   *widgets.DropDown
+	StreamChunked   *widgets.DropDown
+
+	onChange func(settings *domain.HTTPClientSettings)
+}
+
+func NewSettings(settings *domain.HTTPClientSettings, theme *chapartheme.Theme) *Settings {
+	s := &Settings{
+		Form: component.NewForm([]*component.Field{
+			{Label: settingsTimeout, Value: ""},
+			{Label: settingsMaxRedirects, Value: ""},
+			{Label: settingsCACertPath, Value: ""},
+			{Label: settingsMaxResponse, Value: ""},
+		}),
+		FollowRedirects: newBoolDropDown(theme),
+		SkipTLSVerify:   newBoolDropDown(theme),
+		StreamChunked:   newBoolDropDown(theme),
+	}
+
+	s.SetSettings(settings)
+	return s
+}
+
+func newBoolDropDown(theme *chapartheme.Theme) *widgets.DropDown {
+	d := widgets.NewDropDown(
+		theme,
+		widgets.NewDropDownOption("Workspace default").WithValue(optionDefault),
+		widgets.NewDropDownOption("Yes").WithValue(optionYes),
+		widgets.NewDropDownOption("No").WithValue(optionNo),
+	)
+	d.MinWidth = unit.Dp(162)
+	return d
+}
+
+func (s *Settings) SetSettings(settings *domain.HTTPClientSettings) {
+	if settings == nil {
+		settings = &domain.HTTPClientSettings{}
+	}
+
+	values := map[string]string{
+		settingsCACertPath: settings.CACertPath,
+	}
+
+	if settings.Timeout > 0 {
+		values[settingsTimeout] = settings.Timeout.String()
+	}
+
+	if settings.MaxRedirects > 0 {
+		values[settingsMaxRedirects] = strconv.Itoa(settings.MaxRedirects)
+	}
+
+	if settings.MaxResponseSize > 0 {
+		values[settingsMaxResponse] = humanize.IBytes(uint64(settings.MaxResponseSize))
+	}
+
+	s.Form.SetValues(values)
+	s.FollowRedirects.SetSelectedByValue(boolPtrToOption(settings.FollowRedirects))
+	s.SkipTLSVerify.SetSelectedByValue(boolPtrToOption(settings.InsecureSkipVerify))
+	s.StreamChunked.SetSelectedByValue(boolPtrToOption(settings.StreamChunked))
+}
+
+func (s *Settings) SetOnChange(f func(settings *domain.HTTPClientSettings)) {
+	s.onChange = f
+
+	s.Form.SetOnChange(func(values map[string]string) {
+		s.onChange(s.getSettings())
+	})
+
+	s.FollowRedirects.SetOnChanged(func(value string) {
+		s.onChange(s.getSettings())
+	})
+
+	s.SkipTLSVerify.SetOnChanged(func(value string) {
+		s.onChange(s.getSettings())
+	})
+
+	s.StreamChunked.SetOnChanged(func(value string) {
+		s.onChange(s.getSettings())
+	})
+}
+
+// getSettings returns the settings of the form, or nil when nothing is overridden.
+func (s *Settings) getSettings() *domain.HTTPClientSettings {
+	values := s.Form.GetValues()
+
+	settings := &domain.HTTPClientSettings{
+		CACertPath:         values[settingsCACertPath],
+		FollowRedirects:    optionToBoolPtr(s.FollowRedirects.GetSelected().Value),
+		InsecureSkipVerify: optionToBoolPtr(s.SkipTLSVerify.GetSelected().Value),
+		StreamChunked:      optionToBoolPtr(s.StreamChunked.GetSelected().Value),
+	}
+
+	// invalid values are ignored, so the workspace default is used
+	if timeout, err := time.ParseDuration(values[settingsTimeout]); err == nil && timeout > 0 {
+		settings.Timeout = timeout
+	}
+
+	if maxRedirects, err := strconv.Atoi(values[settingsMaxRedirects]); err == nil && maxRedirects > 0 {
+		settings.MaxRedirects = maxRedirects
+	}
+
+	if maxResponseSize, err := humanize.ParseBytes(values[settingsMaxResponse]); err == nil && maxResponseSize > 0 {
+		settings.MaxResponseSize = int64(maxResponseSize)
+	}
+
+	if domain.CompareHTTPClientSettings(settings, &domain.HTTPClientSettings{}) {
+		return nil
+	}
+
+	return settings
+}
+
+func boolPtrToOption(v *bool) string {
+	if v == nil {
+		return optionDefault
+	}
+	return strconv.FormatBool(*v)
+}
+
+func optionToBoolPtr(option string) *bool {
+	if option == optionDefault {
+		return nil
+	}
+
+	v := option == optionYes
+	return &v
+}
+
+func (s *Settings) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	dropDown := func(label string, d *widgets.DropDown) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(130)
+						return material.Label(theme.Material(), theme.TextSize, label).Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return d.Layout(gtx, theme)
+					}),
+				)
+			})
+		})
+	}
+
+	inset := layout.Inset{Top: unit.Dp(15), Right: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Start}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					l := material.Label(theme.Material(), theme.TextSize, "Empty fields use the workspace settings, e.g. timeout 30s, max response size 10MiB")
+					l.Color = theme.TextColor
+					return l.Layout(gtx)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return s.Form.Layout(gtx, theme)
+			}),
+			dropDown("Follow Redirects", s.FollowRedirects),
+			dropDown("Skip TLS Verify", s.SkipTLSVerify),
+			dropDown("Stream Chunked", s.StreamChunked),
+		)
+	})
+}
@@ -0,0 +1,94 @@
+package restful
+
+import (
+	"strings"
+	"sync"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+)
+
+const streamTimeLayout = "15:04:05.000"
+
+// Stream shows the events of a streamed response as they arrive.
+type Stream struct {
+	mx     *sync.Mutex
+	events []rest.StreamEvent
+
+	list *widget.List
+}
+
+func NewStream() *Stream {
+	return &Stream{
+		mx: &sync.Mutex{},
+		list: &widget.List{
+			List: layout.List{
+				Axis:        layout.Vertical,
+				ScrollToEnd: true,
+			},
+		},
+	}
+}
+
+func (s *Stream) AddEvent(event rest.StreamEvent) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *Stream) Clear() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.events = nil
+}
+
+func (s *Stream) Len() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return len(s.events)
+}
+
+// Text returns the data of the events one per line.
+func (s *Stream) Text() string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	lines := make([]string, 0, len(s.events))
+	for _, e := range s.events {
+		lines = append(lines, e.Data)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *Stream) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	s.mx.Lock()
+	events := s.events
+	s.mx.Unlock()
+
+	return material.List(theme.Material(), s.list).Layout(gtx, len(events), func(gtx layout.Context, i int) layout.Dimensions {
+		e := events[i]
+		return layout.Inset{Top: unit.Dp(3), Bottom: unit.Dp(3)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(90)
+					lb := material.Label(theme.Material(), theme.TextSize, e.Time.Local().Format(streamTimeLayout))
+					lb.Color = theme.TextColor
+					return lb.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if e.Event == "" {
+						return layout.Dimensions{}
+					}
+					lb := material.Label(theme.Material(), theme.TextSize, e.Event)
+					lb.Color = theme.ResponseStatusColor
+					return layout.Inset{Right: unit.Dp(10)}.Layout(gtx, lb.Layout)
+				}),
+				layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, e.Data).Layout),
+			)
+		})
+	})
+}
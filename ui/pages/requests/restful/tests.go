@@ -0,0 +1,109 @@
+package restful
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+)
+
+var testPassedColor = color.NRGBA{R: 0x43, G: 0xa0, B: 0x47, A: 0xff}
+
+// TestResults shows the outcome of the assertions of the request against the last response.
+type TestResults struct {
+	results []domain.AssertionResult
+	list    *widget.List
+}
+
+func NewTestResults() *TestResults {
+	return &TestResults{
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+}
+
+func (t *TestResults) SetResults(results []domain.AssertionResult) {
+	t.results = results
+}
+
+func (t *TestResults) passed() int {
+	passed := 0
+	for _, r := range t.results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+func (t *TestResults) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if len(t.results) == 0 {
+		return component.Message(gtx, component.MessageTypeInfo, theme, "No tests, add them in the Tests tab of the request")
+	}
+
+	return layout.Inset{Top: unit.Dp(10), Left: unit.Dp(5), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					passed := t.passed()
+					lb := material.Label(theme.Material(), theme.TextSize, fmt.Sprintf("%d of %d tests passed", passed, len(t.results)))
+					lb.Color = testPassedColor
+					if passed != len(t.results) {
+						lb.Color = theme.ErrorColor
+					}
+					return lb.Layout(gtx)
+				})
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return material.List(theme.Material(), t.list).Layout(gtx, len(t.results), func(gtx layout.Context, i int) layout.Dimensions {
+					return t.resultLayout(gtx, theme, t.results[i])
+				})
+			}),
+		)
+	})
+}
+
+func (t *TestResults) resultLayout(gtx layout.Context, theme *chapartheme.Theme, r domain.AssertionResult) layout.Dimensions {
+	status, statusColor := "PASS", testPassedColor
+	if !r.Passed {
+		status, statusColor = "FAIL", theme.ErrorColor
+	}
+
+	return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Dp(50)
+				lb := material.Label(theme.Material(), theme.TextSize, status)
+				lb.Color = statusColor
+				return lb.Layout(gtx)
+			}),
+			layout.Flexed(1, material.Label(theme.Material(), theme.TextSize, r.Assertion.String()).Layout),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				lb := material.Label(theme.Material(), theme.TextSize, r.Message)
+				lb.Color = theme.TextColor
+				return lb.Layout(gtx)
+			}),
+		)
+	})
+}
+
+// Text returns the results as lines of status, assertion and message.
+func (t *TestResults) Text() string {
+	var b strings.Builder
+	for _, r := range t.results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s %s: %s\n", status, r.Assertion, r.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package restful
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+)
+
+// Timings shows the phases of a request as a waterfall, each phase starts where the previous one ended.
+type Timings struct {
+	timings domain.HTTPTimings
+}
+
+type timingPhase struct {
+	title    string
+	duration time.Duration
+	color    color.NRGBA
+}
+
+func NewTimings() *Timings {
+	return &Timings{}
+}
+
+func (t *Timings) SetTimings(timings domain.HTTPTimings) {
+	t.timings = timings
+}
+
+func (t *Timings) phases() []timingPhase {
+	return []timingPhase{
+		{title: "DNS Lookup", duration: t.timings.DNSLookup, color: color.NRGBA{R: 0x26, G: 0xa6, B: 0x9a, A: 0xff}},
+		{title: "TCP Connect", duration: t.timings.TCPConnect, color: color.NRGBA{R: 0xff, G: 0xa7, B: 0x26, A: 0xff}},
+		{title: "TLS Handshake", duration: t.timings.TLSHandshake, color: color.NRGBA{R: 0xab, G: 0x47, B: 0xbc, A: 0xff}},
+		{title: "Waiting (TTFB)", duration: t.timings.TimeToFirstByte, color: color.NRGBA{R: 0x8b, G: 0xc3, B: 0x4a, A: 0xff}},
+		{title: "Download", duration: t.timings.Download, color: color.NRGBA{R: 0x45, G: 0x89, B: 0xf5, A: 0xff}},
+	}
+}
+
+func (t *Timings) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if t.timings.Total == 0 {
+		return component.Message(gtx, component.MessageTypeInfo, theme, "No timings available")
+	}
+
+	phases := t.phases()
+	children := make([]layout.FlexChild, 0, len(phases)+2)
+
+	var offset time.Duration
+	for _, p := range phases {
+		start := offset
+		offset += p.duration
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return t.phaseLayout(gtx, theme, p, start)
+		}))
+	}
+
+	children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+		return t.phaseLayout(gtx, theme, timingPhase{title: "Total", duration: t.timings.Total, color: theme.BorderColor}, 0)
+	}))
+
+	if t.timings.ConnectionReused {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, material.Label(theme.Material(), theme.TextSize, "The connection was reused, so there was no DNS lookup, connect or TLS handshake").Layout)
+		}))
+	}
+
+	return layout.Inset{Top: unit.Dp(10), Left: unit.Dp(5), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// phaseLayout draws the title and the duration of the phase, followed by its bar placed at start on the scale of the total time.
+func (t *Timings) phaseLayout(gtx layout.Context, theme *chapartheme.Theme, p timingPhase, start time.Duration) layout.Dimensions {
+	column := func(width unit.Dp, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Dp(width)
+			return material.Label(theme.Material(), theme.TextSize, text).Layout(gtx)
+		})
+	}
+
+	return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			column(130, p.title),
+			column(110, p.duration.Round(time.Microsecond).String()),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				width := gtx.Constraints.Max.X
+				height := gtx.Dp(unit.Dp(12))
+
+				total := float64(t.timings.Total)
+				x0 := int(float64(width) * float64(start) / total)
+				x1 := int(float64(width) * float64(start+p.duration) / total)
+				if p.duration > 0 && x1 <= x0 {
+					// very short phases stay visible
+					x1 = x0 + 1
+				}
+
+				rect := image.Rect(min(x0, width), 0, min(x1, width), height)
+				paint.FillShape(gtx.Ops, p.color, clip.Rect(rect).Op())
+				return layout.Dimensions{Size: image.Pt(width, height)}
+			}),
+		)
+	})
+}
+
+// Text returns the timings as lines of phase and duration.
+func (t *Timings) Text() string {
+	var b strings.Builder
+	for _, p := range t.phases() {
+		fmt.Fprintf(&b, "%s: %s\n", p.title, p.duration)
+	}
+	fmt.Fprintf(&b, "Total: %s\n", t.timings.Total)
+	return b.String()
+}
@@ -12,11 +12,19 @@ import (
 	"gioui.org/x/component"
 	giox "gioui.org/x/component"
 	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/graphql"
+	"github.com/chapar-rest/chapar/internal/grpc"
+	"github.com/chapar-rest/chapar/internal/loadtest"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/internal/runner"
 	"github.com/chapar-rest/chapar/internal/safemap"
 	"github.com/chapar-rest/chapar/ui/chapartheme"
 	"github.com/chapar-rest/chapar/ui/keys"
 	"github.com/chapar-rest/chapar/ui/pages/requests/collections"
+	gqlui "github.com/chapar-rest/chapar/ui/pages/requests/graphql"
+	grpcui "github.com/chapar-rest/chapar/ui/pages/requests/grpc"
 	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
+	"github.com/chapar-rest/chapar/ui/pages/requests/websocket"
 	"github.com/chapar-rest/chapar/ui/pages/tips"
 	"github.com/chapar-rest/chapar/ui/widgets"
 	"github.com/google/uuid"
@@ -34,14 +42,16 @@ type View struct {
 	window *app.Window
 
 	// add menu
-	newRequestButton     widget.Clickable
-	importButton         widget.Clickable
-	newMenuContextArea   giox.ContextArea
-	newMenu              giox.MenuState
-	menuInit             bool
-	newHttpRequestButton widget.Clickable
-	newGrpcRequestButton widget.Clickable
-	newCollectionButton  widget.Clickable
+	newRequestButton          widget.Clickable
+	importButton              widget.Clickable
+	newMenuContextArea        giox.ContextArea
+	newMenu                   giox.MenuState
+	menuInit                  bool
+	newHttpRequestButton      widget.Clickable
+	newGraphQLRequestButton   widget.Clickable
+	newWebSocketRequestButton widget.Clickable
+	newGrpcRequestButton      widget.Clickable
+	newCollectionButton       widget.Clickable
 
 	treeViewSearchBox *widgets.TextField
 	treeView          *widgets.TreeView
@@ -50,22 +60,37 @@ type View struct {
 	tabHeader *widgets.Tabs
 
 	// callbacks
-	onTitleChanged              func(id, title, containerType string)
-	onNewRequest                func()
-	onImport                    func()
-	onNewCollection             func()
-	onTabClose                  func(id string)
-	onTreeViewNodeDoubleClicked func(id string)
-	onTreeViewNodeClicked       func(id string)
-	onTreeViewMenuClicked       func(id string, action string)
-	onTabSelected               func(id string)
-	onSave                      func(id string)
-	onSubmit                    func(id, containerType string)
-	onDataChanged               func(id string, data any, containerType string)
-	onCopyResponse              func(gtx layout.Context, dataType, data string)
-	onOnPostRequestSetChanged   func(id string, statusCode int, item, from, fromKey string)
-	onBinaryFileSelect          func(id string)
-	onFromDataFileSelect        func(requestID, fieldID string)
+	onTitleChanged               func(id, title, containerType string)
+	onNewRequest                 func(requestType string)
+	onImport                     func()
+	onNewCollection              func()
+	onTabClose                   func(id string)
+	onTreeViewNodeDoubleClicked  func(id string)
+	onTreeViewNodeClicked        func(id string)
+	onTreeViewMenuClicked        func(id string, action string)
+	onTabSelected                func(id string)
+	onSave                       func(id string)
+	onSubmit                     func(id, containerType string)
+	onCancel                     func(id string)
+	onDataChanged                func(id string, data any, containerType string)
+	onCopyResponse               func(gtx layout.Context, dataType, data string)
+	onSaveResponse               func(id string)
+	onClearResponseHistory       func(id string)
+	onPostRequestRulesChanged    func(id string, rules []domain.PostRequestSet)
+	onBinaryFileSelect           func(id string)
+	onFromDataFileSelect         func(requestID, fieldID string)
+	onFetchGraphQLSchema         func(id string)
+	onWebSocketSend              func(id, messageType, data string)
+	onGRPCReloadServices         func(id string)
+	onGRPCRequestTemplate        func(id, method string)
+	onGRPCSend                   func(id, body string)
+	onGRPCCloseSend              func(id string)
+	onRun                        func(id string, opts runner.Options)
+	onStopRun                    func(id string)
+	onSelectRunDataFile          func(id string)
+	onCollectionVariablesChanged func(id string, values []domain.KeyValue)
+	onLoadTest                   func(id string, opts loadtest.Options)
+	onStopLoadTest               func(id string)
 
 	// state
 	containers    *safemap.Map[Container]
@@ -116,22 +141,23 @@ func NewView(w *app.Window, theme *chapartheme.Theme) *View {
 	return v
 }
 
-func (v *View) SetOnPostRequestSetChanged(f func(id string, statusCode int, item, from, fromKey string)) {
-	v.onOnPostRequestSetChanged = f
+func (v *View) SetOnPostRequestRulesChanged(f func(id string, rules []domain.PostRequestSet)) {
+	v.onPostRequestRulesChanged = f
 }
 
-func (v *View) SetPostRequestSetValues(id string, set domain.PostRequestSet) {
+func (v *View) SetPostRequestRules(id string, rules []domain.PostRequestSet) {
 	if ct, ok := v.containers.Get(id); ok {
 		if ct, ok := ct.(RestContainer); ok {
-			ct.SetPostRequestSetValues(set)
+			ct.SetPostRequestRules(rules)
 		}
 	}
 }
 
-func (v *View) SetPostRequestSetPreview(id, preview string) {
+func (v *View) SetPostRequestRulePreviews(id string, previews []string) {
 	if ct, ok := v.containers.Get(id); ok {
 		if ct, ok := ct.(RestContainer); ok {
-			ct.SetPostRequestSetPreview(preview)
+			ct.SetPostRequestRulePreviews(previews)
+			v.window.Invalidate()
 		}
 	}
 }
@@ -142,6 +168,8 @@ func (v *View) AddRequestTreeViewNode(req *domain.Request) {
 		Identifier:  req.MetaData.ID,
 		MenuOptions: []string{MenuView, MenuDuplicate, MenuDelete},
 		Meta:        safemap.New[string](),
+		Prefix:      RequestPrefix(req),
+		PrefixColor: chapartheme.GetRequestPrefixColor(RequestPrefix(req)),
 	}
 
 	node.Meta.Set(TypeMeta, TypeRequest)
@@ -176,6 +204,120 @@ func (v *View) SetOnCopyResponse(onCopyResponse func(gtx layout.Context, dataTyp
 	v.onCopyResponse = onCopyResponse
 }
 
+func (v *View) SetOnSaveResponse(f func(id string)) {
+	v.onSaveResponse = f
+}
+
+func (v *View) SetOnClearResponseHistory(f func(id string)) {
+	v.onClearResponseHistory = f
+}
+
+func (v *View) SetResponseHistory(id string, responses []domain.HTTPResponse) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(ResponseContainer); ok {
+			ct.SetResponseHistory(responses)
+			v.window.Invalidate()
+		}
+	}
+}
+
+// SetOnRun sets the callback to run a collection or a request, the id is the one of either.
+func (v *View) SetOnRun(f func(id string, opts runner.Options)) {
+	v.onRun = f
+}
+
+func (v *View) SetOnStopRun(f func(id string)) {
+	v.onStopRun = f
+}
+
+func (v *View) SetOnSelectRunDataFile(f func(id string)) {
+	v.onSelectRunDataFile = f
+}
+
+func (v *View) SetOnCollectionVariablesChanged(f func(id string, values []domain.KeyValue)) {
+	v.onCollectionVariablesChanged = f
+}
+
+func (v *View) SetCollectionVariables(id string, values []domain.KeyValue) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(CollectionContainer); ok {
+			ct.SetVariables(values)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetRunDataFile(id, path string) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RunnerContainer); ok {
+			ct.SetRunDataFile(path)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetRunStarted(id string) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RunnerContainer); ok {
+			ct.SetRunStarted()
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) AddRunResult(id string, result runner.Result) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RunnerContainer); ok {
+			ct.AddRunResult(result)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetRunFinished(id string, report *runner.Report, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(RunnerContainer); ok {
+			ct.SetRunFinished(report, err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetOnLoadTest(f func(id string, opts loadtest.Options)) {
+	v.onLoadTest = f
+}
+
+func (v *View) SetOnStopLoadTest(f func(id string)) {
+	v.onStopLoadTest = f
+}
+
+func (v *View) SetLoadTestStarted(id string) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(LoadTestContainer); ok {
+			ct.SetLoadTestStarted()
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetLoadTestStats(id string, stats loadtest.Stats) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(LoadTestContainer); ok {
+			ct.SetLoadTestStats(stats)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetLoadTestFinished(id string, stats loadtest.Stats, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(LoadTestContainer); ok {
+			ct.SetLoadTestFinished(stats, err)
+			v.window.Invalidate()
+		}
+	}
+}
+
 func (v *View) SetOnBinaryFileSelect(f func(id string)) {
 	v.onBinaryFileSelect = f
 }
@@ -200,10 +342,142 @@ func (v *View) AddFileToFormData(requestId, fieldId, filePath string) {
 	}
 }
 
-func (v *View) SetOnNewRequest(onNewRequest func()) {
+func (v *View) SetOnNewRequest(onNewRequest func(requestType string)) {
 	v.onNewRequest = onNewRequest
 }
 
+func (v *View) SetOnFetchGraphQLSchema(f func(id string)) {
+	v.onFetchGraphQLSchema = f
+}
+
+func (v *View) SetGraphQLSchema(id string, schema *graphql.Schema, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GraphQLContainer); ok {
+			ct.SetSchema(schema, err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetOnGRPCReloadServices(f func(id string)) {
+	v.onGRPCReloadServices = f
+}
+
+func (v *View) SetOnGRPCRequestTemplate(f func(id, method string)) {
+	v.onGRPCRequestTemplate = f
+}
+
+func (v *View) SetGRPCServices(id string, services []domain.GRPCService, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GRPCContainer); ok {
+			ct.SetServices(services, err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetGRPCRequestTemplate(id, template string, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GRPCContainer); ok {
+			ct.SetRequestTemplate(template, err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetGRPCResponse(id string, response *grpc.Response, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GRPCContainer); ok {
+			ct.SetGRPCResponse(response, err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetOnGRPCSend(f func(id, body string)) {
+	v.onGRPCSend = f
+}
+
+func (v *View) SetOnGRPCCloseSend(f func(id string)) {
+	v.onGRPCCloseSend = f
+}
+
+func (v *View) SetGRPCStreamOpened(id string, clientStreaming bool) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GRPCContainer); ok {
+			ct.SetStreamOpened(clientStreaming)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) AddGRPCStreamMessage(id string, msg grpc.StreamMessage) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GRPCContainer); ok {
+			ct.AddStreamMessage(msg)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetGRPCStreamError(id string, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(GRPCContainer); ok {
+			ct.SetStreamError(err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetOnWebSocketSend(f func(id, messageType, data string)) {
+	v.onWebSocketSend = f
+}
+
+func (v *View) SetWebSocketConnecting(id string) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(WebSocketContainer); ok {
+			ct.SetConnecting()
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetWebSocketConnected(id string) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(WebSocketContainer); ok {
+			ct.SetConnected()
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) SetWebSocketDisconnected(id string, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(WebSocketContainer); ok {
+			ct.SetDisconnected(err)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) AddWebSocketMessage(id string, msg rest.WebSocketMessage) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(WebSocketContainer); ok {
+			ct.AddMessage(msg)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) AddWebSocketError(id string, err error) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(WebSocketContainer); ok {
+			ct.AddError(err)
+			v.window.Invalidate()
+		}
+	}
+}
+
 func (v *View) SetOnDataChanged(onDataChanged func(id string, data any, containerType string)) {
 	v.onDataChanged = onDataChanged
 }
@@ -216,6 +490,10 @@ func (v *View) SetOnSubmit(f func(id, containerType string)) {
 	v.onSubmit = f
 }
 
+func (v *View) SetOnCancel(f func(id string)) {
+	v.onCancel = f
+}
+
 func (v *View) SetOnImport(f func()) {
 	v.onImport = f
 }
@@ -354,6 +632,21 @@ func (v *View) OpenRequestContainer(req *domain.Request) {
 		return
 	}
 
+	if req.Spec.GraphQL != nil {
+		v.openGraphQLContainer(req)
+		return
+	}
+
+	if req.Spec.WebSocket != nil {
+		v.openWebSocketContainer(req)
+		return
+	}
+
+	if req.Spec.GRPC != nil {
+		v.openGRPCContainer(req)
+		return
+	}
+
 	ct := restful.New(req, v.theme)
 	ct.SetOnTitleChanged(func(text string) {
 		if v.onTitleChanged != nil {
@@ -379,15 +672,63 @@ func (v *View) OpenRequestContainer(req *domain.Request) {
 		}
 	})
 
+	ct.SetOnCancel(func(id string) {
+		if v.onCancel != nil {
+			v.onCancel(id)
+		}
+	})
+
 	ct.SetOnCopyResponse(func(gtx layout.Context, dataType, data string) {
 		if v.onCopyResponse != nil {
 			v.onCopyResponse(gtx, dataType, data)
 		}
 	})
 
-	ct.SetOnPostRequestSetChanged(func(id string, statusCode int, item, from, fromKey string) {
-		if v.onOnPostRequestSetChanged != nil {
-			v.onOnPostRequestSetChanged(id, statusCode, item, from, fromKey)
+	ct.SetOnSaveResponse(func(id string) {
+		if v.onSaveResponse != nil {
+			v.onSaveResponse(id)
+		}
+	})
+
+	ct.SetOnClearResponseHistory(func(id string) {
+		if v.onClearResponseHistory != nil {
+			v.onClearResponseHistory(id)
+		}
+	})
+
+	ct.SetOnLoadTest(func(id string, opts loadtest.Options) {
+		if v.onLoadTest != nil {
+			v.onLoadTest(id, opts)
+		}
+	})
+
+	ct.SetOnStopLoadTest(func(id string) {
+		if v.onStopLoadTest != nil {
+			v.onStopLoadTest(id)
+		}
+	})
+
+	ct.SetOnRun(func(id string, opts runner.Options) {
+		if v.onRun != nil {
+			v.onRun(id, opts)
+		}
+	})
+
+	ct.SetOnStopRun(func(id string) {
+		if v.onStopRun != nil {
+			v.onStopRun(id)
+		}
+	})
+
+	ct.SetOnSelectRunDataFile(func(id string) {
+		if v.onSelectRunDataFile != nil {
+			v.onSelectRunDataFile(id)
+		}
+	})
+
+	ct.SetOnPostRequestRulesChanged(func(id string, rules []domain.PostRequestSet) {
+		if v.onPostRequestRulesChanged != nil {
+			v.onPostRequestRulesChanged(id, rules)
 		}
 	})
 
@@ -406,9 +747,186 @@ func (v *View) OpenRequestContainer(req *domain.Request) {
 	v.containers.Set(req.MetaData.ID, ct)
 }
 
+func (v *View) openGraphQLContainer(req *domain.Request) {
+	ct := gqlui.New(req, v.theme)
+	ct.SetOnTitleChanged(func(text string) {
+		if v.onTitleChanged != nil {
+			v.onTitleChanged(req.MetaData.ID, text, TypeRequest)
+		}
+	})
+
+	ct.SetOnSave(func(id string) {
+		if v.onSave != nil {
+			v.onSave(id)
+		}
+	})
+
+	ct.SetOnDataChanged(func(id string, data any) {
+		if v.onDataChanged != nil {
+			v.onDataChanged(id, req, TypeRequest)
+		}
+	})
+
+	ct.SetOnSubmit(func(id string) {
+		if v.onSubmit != nil {
+			v.onSubmit(id, TypeRequest)
+		}
+	})
+
+	ct.SetOnCancel(func(id string) {
+		if v.onCancel != nil {
+			v.onCancel(id)
+		}
+	})
+
+	ct.SetOnCopyResponse(func(gtx layout.Context, dataType, data string) {
+		if v.onCopyResponse != nil {
+			v.onCopyResponse(gtx, dataType, data)
+		}
+	})
+
+	ct.SetOnSaveResponse(func(id string) {
+		if v.onSaveResponse != nil {
+			v.onSaveResponse(id)
+		}
+	})
+
+	ct.SetOnClearResponseHistory(func(id string) {
+		if v.onClearResponseHistory != nil {
+			v.onClearResponseHistory(id)
+		}
+	})
+
+	ct.SetOnLoadTest(func(id string, opts loadtest.Options) {
+		if v.onLoadTest != nil {
+			v.onLoadTest(id, opts)
+		}
+	})
+
+	ct.SetOnStopLoadTest(func(id string) {
+		if v.onStopLoadTest != nil {
+			v.onStopLoadTest(id)
+		}
+	})
+
+	ct.SetOnFetchSchema(func(id string) {
+		if v.onFetchGraphQLSchema != nil {
+			v.onFetchGraphQLSchema(id)
+		}
+	})
+
+	v.containers.Set(req.MetaData.ID, ct)
+}
+
+func (v *View) openWebSocketContainer(req *domain.Request) {
+	ct := websocket.New(req, v.theme)
+	ct.SetOnTitleChanged(func(text string) {
+		if v.onTitleChanged != nil {
+			v.onTitleChanged(req.MetaData.ID, text, TypeRequest)
+		}
+	})
+
+	ct.SetOnSave(func(id string) {
+		if v.onSave != nil {
+			v.onSave(id)
+		}
+	})
+
+	ct.SetOnDataChanged(func(id string, data any) {
+		if v.onDataChanged != nil {
+			v.onDataChanged(id, req, TypeRequest)
+		}
+	})
+
+	ct.SetOnSubmit(func(id string) {
+		if v.onSubmit != nil {
+			v.onSubmit(id, TypeRequest)
+		}
+	})
+
+	ct.SetOnCancel(func(id string) {
+		if v.onCancel != nil {
+			v.onCancel(id)
+		}
+	})
+
+	ct.SetOnSendMessage(func(id, messageType, data string) {
+		if v.onWebSocketSend != nil {
+			v.onWebSocketSend(id, messageType, data)
+		}
+	})
+
+	v.containers.Set(req.MetaData.ID, ct)
+}
+
+func (v *View) openGRPCContainer(req *domain.Request) {
+	ct := grpcui.New(req, v.theme)
+	ct.SetOnTitleChanged(func(text string) {
+		if v.onTitleChanged != nil {
+			v.onTitleChanged(req.MetaData.ID, text, TypeRequest)
+		}
+	})
+
+	ct.SetOnSave(func(id string) {
+		if v.onSave != nil {
+			v.onSave(id)
+		}
+	})
+
+	ct.SetOnDataChanged(func(id string, data any) {
+		if v.onDataChanged != nil {
+			v.onDataChanged(id, req, TypeRequest)
+		}
+	})
+
+	ct.SetOnSubmit(func(id string) {
+		if v.onSubmit != nil {
+			v.onSubmit(id, TypeRequest)
+		}
+	})
+
+	ct.SetOnCancel(func(id string) {
+		if v.onCancel != nil {
+			v.onCancel(id)
+		}
+	})
+
+	ct.SetOnCopyResponse(func(gtx layout.Context, dataType, data string) {
+		if v.onCopyResponse != nil {
+			v.onCopyResponse(gtx, dataType, data)
+		}
+	})
+
+	ct.SetOnReloadServices(func(id string) {
+		if v.onGRPCReloadServices != nil {
+			v.onGRPCReloadServices(id)
+		}
+	})
+
+	ct.SetOnRequestTemplate(func(id, method string) {
+		if v.onGRPCRequestTemplate != nil {
+			v.onGRPCRequestTemplate(id, method)
+		}
+	})
+
+	ct.SetOnSendMessage(func(id, body string) {
+		if v.onGRPCSend != nil {
+			v.onGRPCSend(id, body)
+		}
+	})
+
+	ct.SetOnCloseSend(func(id string) {
+		if v.onGRPCCloseSend != nil {
+			v.onGRPCCloseSend(id)
+		}
+	})
+
+	v.containers.Set(req.MetaData.ID, ct)
+}
+
 func (v *View) SetSendingRequestLoading(id string) {
 	if ct, ok := v.containers.Get(id); ok {
-		if ct, ok := ct.(RestContainer); ok {
+		if ct, ok := ct.(SendingContainer); ok {
 			ct.ShowSendingRequestLoading()
 		}
 	}
@@ -416,7 +934,7 @@ func (v *View) SetSendingRequestLoading(id string) {
 
 func (v *View) SetSendingRequestLoaded(id string) {
 	if ct, ok := v.containers.Get(id); ok {
-		if ct, ok := ct.(RestContainer); ok {
+		if ct, ok := ct.(SendingContainer); ok {
 			ct.HideSendingRequestLoading()
 		}
 	}
@@ -458,21 +976,64 @@ func (v *View) OpenCollectionContainer(collection *domain.Collection) {
 		}
 	})
 
+	ct.SetOnRun(func(id string, opts runner.Options) {
+		if v.onRun != nil {
+			v.onRun(id, opts)
+		}
+	})
+
+	ct.SetOnStopRun(func(id string) {
+		if v.onStopRun != nil {
+			v.onStopRun(id)
+		}
+	})
+
+	ct.SetOnSelectRunDataFile(func(id string) {
+		if v.onSelectRunDataFile != nil {
+			v.onSelectRunDataFile(id)
+		}
+	})
+
+	ct.SetOnVariablesChanged(func(id string, values []domain.KeyValue) {
+		if v.onCollectionVariablesChanged != nil {
+			v.onCollectionVariablesChanged(id, values)
+		}
+	})
+
 	v.containers.Set(collection.MetaData.ID, ct)
 }
 
 func (v *View) SetHTTPResponse(id string, response domain.HTTPResponseDetail) {
 	if ct, ok := v.containers.Get(id); ok {
-		if ct, ok := ct.(RestContainer); ok {
+		if ct, ok := ct.(ResponseContainer); ok {
 			ct.SetHTTPResponse(response)
 			v.window.Invalidate()
 		}
 	}
 }
 
+// SetStreamStarted switches the response of the request to the list of the streamed events.
+func (v *View) SetStreamStarted(id string, code int, headers []domain.KeyValue) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(ResponseContainer); ok {
+			ct.SetStreamStarted(code, headers)
+			v.window.Invalidate()
+		}
+	}
+}
+
+func (v *View) AddStreamEvent(id string, event rest.StreamEvent) {
+	if ct, ok := v.containers.Get(id); ok {
+		if ct, ok := ct.(ResponseContainer); ok {
+			ct.AddStreamEvent(event)
+			v.window.Invalidate()
+		}
+	}
+}
+
 func (v *View) GetHTTPResponse(id string) *domain.HTTPResponseDetail {
 	if ct, ok := v.containers.Get(id); ok {
-		if ct, ok := ct.(RestContainer); ok {
+		if ct, ok := ct.(ResponseContainer); ok {
 			return ct.GetHTTPResponse()
 		}
 	}
@@ -516,8 +1077,8 @@ func (v *View) PopulateTreeView(requests []*domain.Request, collections []*domai
 				Identifier:  req.MetaData.ID,
 				MenuOptions: []string{MenuView, MenuDuplicate, MenuDelete},
 				Meta:        safemap.New[string](),
-				Prefix:      req.Spec.HTTP.Method,
-				PrefixColor: chapartheme.GetRequestPrefixColor(req.Spec.HTTP.Method),
+				Prefix:      RequestPrefix(req),
+				PrefixColor: chapartheme.GetRequestPrefixColor(RequestPrefix(req)),
 			}
 			node.Meta.Set(TypeMeta, TypeRequest)
 			parentNode.AddChildNode(node)
@@ -534,8 +1095,8 @@ func (v *View) PopulateTreeView(requests []*domain.Request, collections []*domai
 			Identifier:  req.MetaData.ID,
 			MenuOptions: []string{MenuView, MenuDuplicate, MenuDelete},
 			Meta:        safemap.New[string](),
-			Prefix:      req.Spec.HTTP.Method,
-			PrefixColor: chapartheme.GetRequestPrefixColor(req.Spec.HTTP.Method),
+			Prefix:      RequestPrefix(req),
+			PrefixColor: chapartheme.GetRequestPrefixColor(RequestPrefix(req)),
 		}
 		node.Meta.Set(TypeMeta, TypeRequest)
 		treeViewNodes = append(treeViewNodes, node)
@@ -545,6 +1106,23 @@ func (v *View) PopulateTreeView(requests []*domain.Request, collections []*domai
 	v.treeView.SetNodes(treeViewNodes)
 }
 
+// RequestPrefix returns the prefix shown next to the request in the tree view.
+func RequestPrefix(req *domain.Request) string {
+	if req.Spec.GraphQL != nil {
+		return gqlui.ContainerType
+	}
+	if req.Spec.WebSocket != nil {
+		return websocket.ContainerType
+	}
+	if req.Spec.GRPC != nil {
+		return grpcui.ContainerType
+	}
+	if req.Spec.HTTP != nil {
+		return req.Spec.HTTP.Method
+	}
+	return ""
+}
+
 func (v *View) AddTreeViewNode(req *domain.Request) {
 	v.addTreeViewNode("", req)
 }
@@ -571,6 +1149,8 @@ func (v *View) addTreeViewNode(parentID string, req *domain.Request) {
 		Identifier:  req.MetaData.ID,
 		MenuOptions: []string{MenuDuplicate, MenuDelete},
 		Meta:        safemap.New[string](),
+		Prefix:      RequestPrefix(req),
+		PrefixColor: chapartheme.GetRequestPrefixColor(RequestPrefix(req)),
 	}
 	node.Meta.Set(TypeMeta, TypeRequest)
 	if parentID == "" {
@@ -598,6 +1178,8 @@ func (v *View) requestList(gtx layout.Context, theme *chapartheme.Theme) layout.
 		v.newMenu = component.MenuState{
 			Options: []func(gtx layout.Context) layout.Dimensions{
 				component.MenuItem(theme.Material(), &v.newHttpRequestButton, "Restful Request").Layout,
+				component.MenuItem(theme.Material(), &v.newGraphQLRequestButton, "GraphQL Request").Layout,
+				component.MenuItem(theme.Material(), &v.newWebSocketRequestButton, "WebSocket Request").Layout,
 				component.MenuItem(theme.Material(), &v.newGrpcRequestButton, "GRPC Request").Layout,
 				component.Divider(theme.Material()).Layout,
 				component.MenuItem(theme.Material(), &v.newCollectionButton, "Collection").Layout,
@@ -607,7 +1189,25 @@ func (v *View) requestList(gtx layout.Context, theme *chapartheme.Theme) layout.
 
 	if v.newHttpRequestButton.Clicked(gtx) {
 		if v.onNewRequest != nil {
-			v.onNewRequest()
+			v.onNewRequest(domain.RequestTypeHTTP)
+		}
+	}
+
+	if v.newGraphQLRequestButton.Clicked(gtx) {
+		if v.onNewRequest != nil {
+			v.onNewRequest(domain.RequestTypeGraphQL)
+		}
+	}
+
+	if v.newWebSocketRequestButton.Clicked(gtx) {
+		if v.onNewRequest != nil {
+			v.onNewRequest(domain.RequestTypeWebSocket)
+		}
+	}
+
+	if v.newGrpcRequestButton.Clicked(gtx) {
+		if v.onNewRequest != nil {
+			v.onNewRequest(domain.RequestTypeGRPC)
 		}
 	}
 
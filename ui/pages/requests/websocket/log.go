@@ -0,0 +1,160 @@
+package websocket
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image/color"
+	"sync"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const logTimeLayout = "15:04:05.000"
+
+// Log is the list of the messages exchanged on the connection along with the connection events.
+type Log struct {
+	clearButton widget.Clickable
+
+	mx      *sync.Mutex
+	entries []logEntry
+	status  string
+
+	list *widget.List
+}
+
+type logEntry struct {
+	time time.Time
+	// direction is empty for the connection events
+	direction string
+	text      string
+	isError   bool
+}
+
+func NewLog() *Log {
+	return &Log{
+		mx:     &sync.Mutex{},
+		status: "Disconnected",
+		list: &widget.List{
+			List: layout.List{
+				Axis:        layout.Vertical,
+				ScrollToEnd: true,
+			},
+		},
+	}
+}
+
+func (l *Log) SetStatus(status string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.status = status
+}
+
+func (l *Log) AddEvent(text string, isError bool) {
+	l.add(logEntry{time: time.Now(), text: text, isError: isError})
+}
+
+func (l *Log) AddMessage(msg rest.WebSocketMessage) {
+	text := string(msg.Data)
+	if msg.Type == domain.WebSocketMessageTypeBinary {
+		text = fmt.Sprintf("binary, %d bytes: %s", len(msg.Data), base64.StdEncoding.EncodeToString(msg.Data))
+	}
+
+	l.add(logEntry{time: msg.Time, direction: msg.Direction, text: text})
+}
+
+func (l *Log) add(entry logEntry) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *Log) Clear() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.entries = nil
+}
+
+func (l *Log) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if l.clearButton.Clicked(gtx) {
+		l.Clear()
+	}
+
+	l.mx.Lock()
+	entries := l.entries
+	status := l.status
+	l.mx.Unlock()
+
+	return layout.Inset{Left: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Top: unit.Dp(10), Bottom: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle, Spacing: layout.SpaceBetween}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							lb := material.Label(theme.Material(), theme.TextSize, fmt.Sprintf("Messages (%s)", status))
+							lb.Font.Weight = font.Bold
+							return lb.Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							btn := widgets.Button(theme.Material(), &l.clearButton, widgets.DeleteIcon, widgets.IconPositionStart, "Clear")
+							btn.Color = theme.ButtonTextColor
+							return btn.Layout(gtx, theme)
+						}),
+					)
+				})
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return material.List(theme.Material(), l.list).Layout(gtx, len(entries), func(gtx layout.Context, i int) layout.Dimensions {
+					return l.entryLayout(gtx, theme, entries[i])
+				})
+			}),
+		)
+	})
+}
+
+func (l *Log) entryLayout(gtx layout.Context, theme *chapartheme.Theme, e logEntry) layout.Dimensions {
+	prefix, prefixColor := "•", theme.TextColor
+	switch {
+	case e.isError:
+		prefixColor = theme.ErrorColor
+	case e.direction == rest.WebSocketDirectionOut:
+		prefix, prefixColor = "↑", chapartheme.LightBlue
+	case e.direction == rest.WebSocketDirectionIn:
+		prefix, prefixColor = "↓", chapartheme.LightGreen
+	}
+
+	return layout.Inset{Top: unit.Dp(3), Bottom: unit.Dp(3)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Dp(90)
+				lb := material.Label(theme.Material(), theme.TextSize, e.time.Local().Format(logTimeLayout))
+				lb.Color = theme.TextColor
+				return lb.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Dp(20)
+				return label(theme, prefix, prefixColor).Layout(gtx)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				if e.isError {
+					return label(theme, e.text, theme.ErrorColor).Layout(gtx)
+				}
+				return material.Label(theme.Material(), theme.TextSize, e.text).Layout(gtx)
+			}),
+		)
+	})
+}
+
+func label(theme *chapartheme.Theme, text string, c color.NRGBA) material.LabelStyle {
+	lb := material.Label(theme.Material(), theme.TextSize, text)
+	lb.Color = c
+	return lb
+}
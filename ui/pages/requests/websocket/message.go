@@ -0,0 +1,205 @@
+package websocket
+
+import (
+	"fmt"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+	"github.com/google/uuid"
+)
+
+// Message is the editor of the message to send along with the saved message templates.
+type Message struct {
+	TypeDropDown *widgets.DropDown
+	Editor       *widgets.CodeEditor
+
+	sendButton widget.Clickable
+	saveButton widget.Clickable
+
+	templates []*template
+	list      *widget.List
+
+	onSend             func(messageType, data string)
+	onTemplatesChanged func(templates []domain.WebSocketMessage)
+}
+
+type template struct {
+	message domain.WebSocketMessage
+
+	Name       *widgets.EditableLabel
+	loadButton widget.Clickable
+	delete     widget.Clickable
+}
+
+func NewMessage(templates []domain.WebSocketMessage, theme *chapartheme.Theme) *Message {
+	m := &Message{
+		TypeDropDown: widgets.NewDropDown(theme,
+			widgets.NewDropDownOption("Text").WithValue(domain.WebSocketMessageTypeText),
+			widgets.NewDropDownOption("JSON").WithValue(domain.WebSocketMessageTypeJSON),
+			widgets.NewDropDownOption("Binary (base64)").WithValue(domain.WebSocketMessageTypeBinary),
+		),
+		Editor: widgets.NewCodeEditor("", "JSON", theme),
+		list: &widget.List{
+			List: layout.List{Axis: layout.Vertical},
+		},
+	}
+
+	m.TypeDropDown.MinWidth = unit.Dp(150)
+	m.setTemplates(templates)
+	return m
+}
+
+func (m *Message) SetOnSend(f func(messageType, data string)) {
+	m.onSend = f
+}
+
+func (m *Message) SetOnTemplatesChanged(f func(templates []domain.WebSocketMessage)) {
+	m.onTemplatesChanged = f
+}
+
+func (m *Message) setTemplates(messages []domain.WebSocketMessage) {
+	m.templates = make([]*template, 0, len(messages))
+	for _, msg := range messages {
+		m.addTemplate(msg)
+	}
+}
+
+func (m *Message) addTemplate(msg domain.WebSocketMessage) {
+	t := &template{
+		message: msg,
+		Name:    widgets.NewEditableLabel(msg.Name),
+	}
+
+	t.Name.SetOnChanged(func(text string) {
+		t.message.Name = text
+		m.templatesChanged()
+	})
+
+	m.templates = append(m.templates, t)
+}
+
+func (m *Message) templatesChanged() {
+	if m.onTemplatesChanged == nil {
+		return
+	}
+
+	messages := make([]domain.WebSocketMessage, 0, len(m.templates))
+	for _, t := range m.templates {
+		messages = append(messages, t.message)
+	}
+	m.onTemplatesChanged(messages)
+}
+
+func (m *Message) selectedType() string {
+	if selected := m.TypeDropDown.GetSelected(); selected != nil {
+		return selected.Value
+	}
+	return domain.WebSocketMessageTypeText
+}
+
+func (m *Message) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if m.sendButton.Clicked(gtx) && m.onSend != nil {
+		go m.onSend(m.selectedType(), m.Editor.Code())
+	}
+
+	if m.saveButton.Clicked(gtx) {
+		m.addTemplate(domain.WebSocketMessage{
+			ID:   uuid.NewString(),
+			Name: fmt.Sprintf("Message %d", len(m.templates)+1),
+			Type: m.selectedType(),
+			Data: m.Editor.Code(),
+		})
+		m.templatesChanged()
+	}
+
+	return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return m.TypeDropDown.Layout(gtx, theme)
+					}),
+					layout.Flexed(1, layout.Spacer{}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &m.saveButton, widgets.SaveIcon, widgets.IconPositionStart, "Save as template")
+						btn.Color = theme.ButtonTextColor
+						return btn.Layout(gtx, theme)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(5)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						btn := widgets.Button(theme.Material(), &m.sendButton, widgets.SendIcon, widgets.IconPositionStart, "Send")
+						btn.Color = theme.ButtonTextColor
+						btn.Background = theme.SendButtonBgColor
+						return btn.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(10)}.Layout),
+			layout.Flexed(0.6, func(gtx layout.Context) layout.Dimensions {
+				return m.Editor.Layout(gtx, theme, "Message, environment variables like {{token}} are replaced when sending")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(5)}.Layout(gtx, material.Label(theme.Material(), theme.TextSize, "Templates").Layout)
+			}),
+			layout.Flexed(0.4, func(gtx layout.Context) layout.Dimensions {
+				return m.templatesLayout(gtx, theme)
+			}),
+		)
+	})
+}
+
+func (m *Message) templatesLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if len(m.templates) == 0 {
+		return material.Label(theme.Material(), theme.TextSize, "No saved messages").Layout(gtx)
+	}
+
+	// handle the clicks before the layout, the list is changed when a template is deleted
+	for i, t := range m.templates {
+		if t.loadButton.Clicked(gtx) {
+			m.TypeDropDown.SetSelectedByValue(t.message.Type)
+			m.Editor.SetCode(t.message.Data)
+		}
+
+		if t.delete.Clicked(gtx) {
+			m.templates = append(m.templates[:i], m.templates[i+1:]...)
+			m.templatesChanged()
+			break
+		}
+	}
+
+	return material.List(theme.Material(), m.list).Layout(gtx, len(m.templates), func(gtx layout.Context, i int) layout.Dimensions {
+		t := m.templates[i]
+		return layout.Inset{Top: unit.Dp(3), Bottom: unit.Dp(3)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return t.Name.Layout(gtx, theme)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(60)
+					return material.Label(theme.Material(), theme.TextSize, t.message.Type).Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					btn := widgets.Button(theme.Material(), &t.loadButton, widgets.EditIcon, widgets.IconPositionStart, "Use")
+					btn.Color = theme.ButtonTextColor
+					return btn.Layout(gtx, theme)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					ib := widgets.IconButton{
+						Icon:      widgets.DeleteIcon,
+						Size:      unit.Dp(20),
+						Color:     theme.TextColor,
+						Clickable: &t.delete,
+					}
+					return layout.Inset{Left: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return ib.Layout(gtx, theme)
+					})
+				}),
+			)
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/restful"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+type Request struct {
+	Tabs *widgets.Tabs
+
+	Message  *Message
+	Headers  *restful.Headers
+	Auth     *restful.Auth
+	Settings *restful.Settings
+}
+
+func NewRequest(req *domain.Request, theme *chapartheme.Theme) *Request {
+	spec := req.Spec.WebSocket
+	if spec.Request == nil {
+		spec.Request = &domain.HTTPRequest{}
+	}
+
+	return &Request{
+		Tabs: widgets.NewTabs([]*widgets.Tab{
+			{Title: "Message"},
+			{Title: "Headers"},
+			{Title: "Auth"},
+			{Title: "Settings"},
+		}, nil),
+		Message:  NewMessage(spec.Messages, theme),
+		Headers:  restful.NewHeaders(spec.Request.Headers),
+		Auth:     restful.NewAuth(spec.Request.Auth, theme),
+		Settings: restful.NewSettings(spec.Settings, theme),
+	}
+}
+
+func (r *Request) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	inset := layout.Inset{Top: unit.Dp(10)}
+	return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{
+			Axis:      layout.Vertical,
+			Alignment: layout.Start,
+		}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Tabs.Layout(gtx, theme)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch r.Tabs.SelectedTab().Title {
+				case "Message":
+					return r.Message.Layout(gtx, theme)
+				case "Headers":
+					return r.Headers.Layout(gtx, theme)
+				case "Auth":
+					return r.Auth.Layout(gtx, theme)
+				case "Settings":
+					return r.Settings.Layout(gtx, theme)
+				default:
+					return layout.Dimensions{}
+				}
+			}),
+		)
+	})
+}
@@ -0,0 +1,200 @@
+package websocket
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	giox "gioui.org/x/component"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/rest"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+// ContainerType is the type shown in the breadcrumb and the tree view for websocket requests.
+const ContainerType = "WS"
+
+type WebSocket struct {
+	Prompt *widgets.Prompt
+
+	Req *domain.Request
+
+	Breadcrumb *component.Breadcrumb
+	AddressBar *component.AddressBar
+	Request    *Request
+	Log        *Log
+
+	split widgets.SplitView
+
+	onSave        func(id string)
+	onDataChanged func(id string, data any)
+	onSubmit      func(id string)
+	onCancel      func(id string)
+}
+
+func New(req *domain.Request, theme *chapartheme.Theme) *WebSocket {
+	r := &WebSocket{
+		Req:        req,
+		Prompt:     widgets.NewPrompt("", "", ""),
+		Breadcrumb: component.NewBreadcrumb(req.MetaData.ID, req.CollectionName, ContainerType, req.MetaData.Name),
+		AddressBar: component.NewAddressBar(theme, req.Spec.WebSocket.URL, ""),
+		split: widgets.SplitView{
+			Resize: giox.Resize{
+				Ratio: 0.5,
+			},
+			BarWidth: unit.Dp(2),
+		},
+		Request: NewRequest(req, theme),
+		Log:     NewLog(),
+	}
+	r.AddressBar.SetButtonTexts("Connect", "Disconnect")
+	r.setupHooks()
+
+	return r
+}
+
+func (r *WebSocket) SetOnDataChanged(f func(id string, data any)) {
+	r.onDataChanged = f
+}
+
+// SetOnSubmit sets the callback to open the connection.
+func (r *WebSocket) SetOnSubmit(f func(id string)) {
+	r.onSubmit = f
+}
+
+// SetOnCancel sets the callback to close the connection, or to abort it while connecting.
+func (r *WebSocket) SetOnCancel(f func(id string)) {
+	r.onCancel = f
+}
+
+func (r *WebSocket) SetOnSendMessage(f func(id, messageType, data string)) {
+	r.Request.Message.SetOnSend(func(messageType, data string) {
+		f(r.Req.MetaData.ID, messageType, data)
+	})
+}
+
+func (r *WebSocket) SetConnecting() {
+	r.AddressBar.SetSendingRequest(true)
+	r.Log.SetStatus("Connecting")
+	r.Log.AddEvent("Connecting to "+r.Req.Spec.WebSocket.URL, false)
+}
+
+func (r *WebSocket) SetConnected() {
+	r.AddressBar.SetSendingRequest(true)
+	r.Log.SetStatus("Connected")
+	r.Log.AddEvent("Connected", false)
+}
+
+// SetDisconnected marks the connection as closed, err is the reason when it was not closed normally.
+func (r *WebSocket) SetDisconnected(err error) {
+	r.AddressBar.SetSendingRequest(false)
+	r.Log.SetStatus("Disconnected")
+	if err != nil {
+		r.Log.AddEvent("Disconnected: "+err.Error(), true)
+		return
+	}
+	r.Log.AddEvent("Disconnected", false)
+}
+
+func (r *WebSocket) AddMessage(msg rest.WebSocketMessage) {
+	r.Log.AddMessage(msg)
+}
+
+func (r *WebSocket) AddError(err error) {
+	r.Log.AddEvent(err.Error(), true)
+}
+
+func (r *WebSocket) SetDataChanged(changed bool) {
+	r.Breadcrumb.SetDataChanged(changed)
+}
+
+func (r *WebSocket) SetOnTitleChanged(f func(title string)) {
+	r.Breadcrumb.SetOnTitleChanged(f)
+}
+
+func (r *WebSocket) SetOnSave(f func(id string)) {
+	r.onSave = f
+}
+
+func (r *WebSocket) ShowPrompt(title, content, modalType string, onSubmit func(selectedOption string, remember bool), options ...widgets.Option) {
+	r.Prompt.Type = modalType
+	r.Prompt.Title = title
+	r.Prompt.Content = content
+	r.Prompt.SetOptions(options...)
+	r.Prompt.WithoutRememberBool()
+	r.Prompt.SetOnSubmit(onSubmit)
+	r.Prompt.Show()
+}
+
+func (r *WebSocket) HidePrompt() {
+	r.Prompt.Hide()
+}
+
+func (r *WebSocket) setupHooks() {
+	r.Breadcrumb.SetOnSave(func(id string) {
+		r.onSave(id)
+	})
+
+	r.AddressBar.SetOnURLChanged(func(url string) {
+		r.Req.Spec.WebSocket.URL = url
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.AddressBar.SetOnSubmit(func() {
+		r.onSubmit(r.Req.MetaData.ID)
+	})
+
+	r.AddressBar.SetOnCancel(func() {
+		if r.onCancel != nil {
+			r.onCancel(r.Req.MetaData.ID)
+		}
+	})
+
+	r.Request.Message.SetOnTemplatesChanged(func(templates []domain.WebSocketMessage) {
+		r.Req.Spec.WebSocket.Messages = templates
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Headers.SetOnChange(func(headers []domain.KeyValue) {
+		r.Req.Spec.WebSocket.Request.Headers = headers
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Auth.SetOnChange(func(auth domain.Auth) {
+		r.Req.Spec.WebSocket.Request.Auth = auth
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+
+	r.Request.Settings.SetOnChange(func(settings *domain.HTTPClientSettings) {
+		r.Req.Spec.WebSocket.Settings = settings
+		r.onDataChanged(r.Req.MetaData.ID, r.Req)
+	})
+}
+
+func (r *WebSocket) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	return layout.UniformInset(unit.Dp(10)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.Prompt.Layout(gtx, theme)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(15), Top: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return r.Breadcrumb.Layout(gtx, theme)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return r.AddressBar.Layout(gtx, theme)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return r.split.Layout(gtx, theme,
+					func(gtx layout.Context) layout.Dimensions {
+						return r.Request.Layout(gtx, theme)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return r.Log.Layout(gtx, theme)
+					},
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,68 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/internal/repository"
+	"github.com/chapar-rest/chapar/internal/rest"
+)
+
+type Controller struct {
+	view *View
+
+	repo        repository.Repository
+	restService *rest.Service
+}
+
+func NewController(view *View, repo repository.Repository, restService *rest.Service) *Controller {
+	c := &Controller{
+		view:        view,
+		repo:        repo,
+		restService: restService,
+	}
+
+	view.SetOnChange(c.onChange)
+	view.SetOnSave(c.onSave)
+	return c
+}
+
+// LoadData shows the http client settings of the active workspace.
+func (c *Controller) LoadData() error {
+	preferences, err := c.repo.ReadPreferencesData()
+	if err != nil {
+		return err
+	}
+
+	c.view.SetHTTPClientSettings(preferences.Spec.HTTPClient)
+	c.view.SetDirty(false)
+	return nil
+}
+
+func (c *Controller) onChange(settings domain.HTTPClientSettings) {
+	preferences, err := c.repo.ReadPreferencesData()
+	if err != nil {
+		fmt.Println("failed to read preferences", err)
+		return
+	}
+
+	// set dirty if the settings are different from the saved ones
+	c.view.SetDirty(!domain.CompareHTTPClientSettings(&preferences.Spec.HTTPClient, &settings))
+}
+
+func (c *Controller) onSave(settings domain.HTTPClientSettings) {
+	preferences, err := c.repo.ReadPreferencesData()
+	if err != nil {
+		fmt.Println("failed to read preferences", err)
+		return
+	}
+
+	preferences.Spec.HTTPClient = settings
+	if err := c.repo.UpdatePreferences(preferences); err != nil {
+		fmt.Println("failed to update preferences", err)
+		return
+	}
+
+	c.restService.SetHTTPClientSettings(settings)
+	c.view.SetDirty(false)
+}
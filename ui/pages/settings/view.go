@@ -0,0 +1,254 @@
+package settings
+
+import (
+	"strconv"
+	"time"
+
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/domain"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/pages/requests/component"
+	"github.com/chapar-rest/chapar/ui/widgets"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	settingsTimeout      = "Timeout"
+	settingsMaxRedirects = "Max Redirects"
+	settingsCACertPath   = "CA Cert Path"
+	settingsMaxResponse  = "Max Response Size"
+
+	optionYes = "true"
+	optionNo  = "false"
+
+	description = "HTTP client settings of the workspace, requests and environments can override them.\nEmpty fields use the defaults: no timeout, 10 redirects and 10MiB max response size."
+
+	proxyDescription = "The system proxy is read from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, environments can use another proxy."
+)
+
+type View struct {
+	saveButton widget.Clickable
+
+	form            *component.Form
+	followRedirects *widgets.DropDown
+	skipTLSVerify   *widgets.DropDown
+	streamChunked   *widgets.DropDown
+
+	proxy *component.ProxySettings
+	dirty bool
+
+	list *widget.List
+
+	onChange func(settings domain.HTTPClientSettings)
+	onSave   func(settings domain.HTTPClientSettings)
+}
+
+func NewView(theme *chapartheme.Theme) *View {
+	v := &View{
+		form: component.NewForm([]*component.Field{
+			{Label: settingsTimeout, Value: ""},
+			{Label: settingsMaxRedirects, Value: ""},
+			{Label: settingsCACertPath, Value: ""},
+			{Label: settingsMaxResponse, Value: ""},
+		}),
+		followRedirects: newBoolDropDown(theme),
+		skipTLSVerify:   newBoolDropDown(theme),
+		streamChunked:   newBoolDropDown(theme),
+		proxy:           component.NewProxySettings(nil, "System proxy", theme),
+		list: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+
+	v.form.SetOnChange(func(values map[string]string) {
+		v.changed()
+	})
+
+	v.followRedirects.SetOnChanged(func(value string) {
+		v.changed()
+	})
+
+	v.skipTLSVerify.SetOnChanged(func(value string) {
+		v.changed()
+	})
+
+	v.streamChunked.SetOnChanged(func(value string) {
+		v.changed()
+	})
+
+	v.proxy.SetOnChange(func(proxy *domain.ProxySettings) {
+		v.changed()
+	})
+
+	return v
+}
+
+func newBoolDropDown(theme *chapartheme.Theme) *widgets.DropDown {
+	d := widgets.NewDropDown(
+		theme,
+		widgets.NewDropDownOption("Yes").WithValue(optionYes),
+		widgets.NewDropDownOption("No").WithValue(optionNo),
+	)
+	d.MinWidth = unit.Dp(162)
+	return d
+}
+
+func (v *View) SetOnChange(f func(settings domain.HTTPClientSettings)) {
+	v.onChange = f
+}
+
+func (v *View) SetOnSave(f func(settings domain.HTTPClientSettings)) {
+	v.onSave = f
+}
+
+// SetDirty sets whether the settings have changes which are not saved yet.
+func (v *View) SetDirty(dirty bool) {
+	v.dirty = dirty
+}
+
+func (v *View) SetHTTPClientSettings(settings domain.HTTPClientSettings) {
+	values := map[string]string{
+		settingsCACertPath: settings.CACertPath,
+	}
+
+	if settings.Timeout > 0 {
+		values[settingsTimeout] = settings.Timeout.String()
+	}
+
+	if settings.MaxRedirects > 0 {
+		values[settingsMaxRedirects] = strconv.Itoa(settings.MaxRedirects)
+	}
+
+	if settings.MaxResponseSize > 0 {
+		values[settingsMaxResponse] = humanize.IBytes(uint64(settings.MaxResponseSize))
+	}
+
+	v.form.SetValues(values)
+	v.followRedirects.SetSelectedByValue(strconv.FormatBool(settings.ShouldFollowRedirects()))
+	v.skipTLSVerify.SetSelectedByValue(strconv.FormatBool(settings.ShouldSkipTLSVerify()))
+	v.streamChunked.SetSelectedByValue(strconv.FormatBool(settings.ShouldStreamChunked()))
+	v.proxy.SetProxy(settings.Proxy)
+}
+
+func (v *View) changed() {
+	if v.onChange != nil {
+		v.onChange(v.getHTTPClientSettings())
+	}
+}
+
+// getHTTPClientSettings returns the settings of the form, the defaults are left unset so they stay the defaults.
+func (v *View) getHTTPClientSettings() domain.HTTPClientSettings {
+	values := v.form.GetValues()
+
+	settings := domain.HTTPClientSettings{
+		CACertPath: values[settingsCACertPath],
+		Proxy:      v.proxy.GetProxy(),
+	}
+
+	if v.followRedirects.GetSelected().Value == optionNo {
+		followRedirects := false
+		settings.FollowRedirects = &followRedirects
+	}
+
+	if v.skipTLSVerify.GetSelected().Value == optionYes {
+		skipTLSVerify := true
+		settings.InsecureSkipVerify = &skipTLSVerify
+	}
+
+	if v.streamChunked.GetSelected().Value == optionYes {
+		streamChunked := true
+		settings.StreamChunked = &streamChunked
+	}
+
+	// invalid values are ignored, so the default is used
+	if timeout, err := time.ParseDuration(values[settingsTimeout]); err == nil && timeout > 0 {
+		settings.Timeout = timeout
+	}
+
+	if maxRedirects, err := strconv.Atoi(values[settingsMaxRedirects]); err == nil && maxRedirects > 0 {
+		settings.MaxRedirects = maxRedirects
+	}
+
+	if maxResponseSize, err := humanize.ParseBytes(values[settingsMaxResponse]); err == nil && maxResponseSize > 0 {
+		settings.MaxResponseSize = int64(maxResponseSize)
+	}
+
+	return settings
+}
+
+func (v *View) httpClientLayout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	dropDown := func(label string, d *widgets.DropDown) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(130)
+						return material.Label(theme.Material(), theme.TextSize, label).Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return d.Layout(gtx, theme)
+					}),
+				)
+			})
+		})
+	}
+
+	return layout.Flex{Axis: layout.Vertical, Alignment: layout.Start}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return v.form.Layout(gtx, theme)
+		}),
+		dropDown("Follow Redirects", v.followRedirects),
+		dropDown("Skip TLS Verify", v.skipTLSVerify),
+		dropDown("Stream Chunked", v.streamChunked),
+	)
+}
+
+func (v *View) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	if v.saveButton.Clicked(gtx) && v.onSave != nil {
+		v.onSave(v.getHTTPClientSettings())
+	}
+
+	sections := []layout.Widget{
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					lb := material.Label(theme.Material(), unit.Sp(18), "Settings")
+					lb.Font.Weight = font.Bold
+					return lb.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if !v.dirty {
+						return layout.Dimensions{}
+					}
+					return widgets.SaveButtonLayout(gtx, theme, &v.saveButton)
+				}),
+			)
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(15), Bottom: unit.Dp(15)}.Layout(gtx, material.Label(theme.Material(), theme.TextSize, description).Layout)
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return v.httpClientLayout(gtx, theme)
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return v.proxy.Layout(gtx, theme)
+			})
+		},
+		func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(10)}.Layout(gtx, material.Label(theme.Material(), theme.TextSize, proxyDescription).Layout)
+		},
+	}
+
+	return layout.Inset{Top: unit.Dp(30), Left: unit.Dp(50), Right: unit.Dp(50)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return material.List(theme.Material(), v.list).Layout(gtx, len(sections), func(gtx layout.Context, i int) layout.Dimensions {
+			return sections[i](gtx)
+		})
+	})
+}
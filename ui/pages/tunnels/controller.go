@@ -0,0 +1,36 @@
+package tunnels
+
+import (
+	"fmt"
+
+	"github.com/chapar-rest/chapar/internal/tunnel"
+)
+
+type Controller struct {
+	view    *View
+	manager *tunnel.Manager
+}
+
+func NewController(view *View, manager *tunnel.Manager) *Controller {
+	c := &Controller{
+		view:    view,
+		manager: manager,
+	}
+
+	view.SetOnClose(c.onClose)
+	manager.AddChangeListener(c.LoadData)
+	return c
+}
+
+func (c *Controller) LoadData() {
+	c.view.SetTunnels(c.manager.List())
+}
+
+func (c *Controller) onClose(id int) {
+	// closing waits for the tunnel to stop, like for kubectl to exit
+	go func() {
+		if err := c.manager.Close(id); err != nil {
+			fmt.Println("failed to close tunnel", err)
+		}
+	}()
+}
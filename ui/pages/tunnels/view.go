@@ -0,0 +1,182 @@
+package tunnels
+
+import (
+	"strconv"
+	"sync"
+
+	"gioui.org/app"
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"github.com/chapar-rest/chapar/internal/tunnel"
+	"github.com/chapar-rest/chapar/ui/chapartheme"
+	"github.com/chapar-rest/chapar/ui/widgets"
+)
+
+const description = "Tunnels of the pre-request actions are shared between requests and closed once they are not used for a while.\nThey are checked periodically and reconnected when they fail."
+
+type View struct {
+	window *app.Window
+
+	mx   *sync.Mutex
+	rows []*row
+	list *widget.List
+
+	onClose func(id int)
+}
+
+type row struct {
+	info        tunnel.Info
+	closeButton widget.Clickable
+}
+
+func NewView(w *app.Window) *View {
+	return &View{
+		window: w,
+		mx:     &sync.Mutex{},
+		list: &widget.List{
+			List: layout.List{
+				Axis: layout.Vertical,
+			},
+		},
+	}
+}
+
+func (v *View) SetOnClose(f func(id int)) {
+	v.onClose = f
+}
+
+// SetTunnels sets the tunnels to show, it is called from the goroutines of the tunnels so the window is invalidated.
+func (v *View) SetTunnels(tunnels []tunnel.Info) {
+	v.mx.Lock()
+	old := make(map[int]*row, len(v.rows))
+	for _, r := range v.rows {
+		old[r.info.ID] = r
+	}
+
+	rows := make([]*row, 0, len(tunnels))
+	for _, t := range tunnels {
+		// rows are kept so the state of their buttons is not lost
+		r, ok := old[t.ID]
+		if !ok {
+			r = &row{}
+		}
+		r.info = t
+		rows = append(rows, r)
+	}
+	v.rows = rows
+	v.mx.Unlock()
+
+	v.window.Invalidate()
+}
+
+func (v *View) tunnelLayout(gtx layout.Context, theme *chapartheme.Theme, r *row) layout.Dimensions {
+	info := r.info
+
+	localAddr := info.LocalAddr
+	if localAddr == "" {
+		localAddr = "-"
+	}
+
+	column := func(width unit.Dp, text string) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Min.X = gtx.Dp(width)
+			gtx.Constraints.Max.X = gtx.Dp(width)
+			lb := material.Label(theme.Material(), theme.TextSize, text)
+			lb.MaxLines = 1
+			return lb.Layout(gtx)
+		})
+	}
+
+	return layout.Inset{Top: unit.Dp(5), Bottom: unit.Dp(5)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						lb := material.Label(theme.Material(), theme.TextSize, info.Name)
+						lb.MaxLines = 1
+						return lb.Layout(gtx)
+					}),
+					column(160, localAddr),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(100)
+						gtx.Constraints.Max.X = gtx.Dp(100)
+						lb := material.Label(theme.Material(), theme.TextSize, info.Status)
+						switch info.Status {
+						case tunnel.StatusOpen:
+							lb.Color = chapartheme.LightGreen
+						case tunnel.StatusReconnecting:
+							lb.Color = chapartheme.LightYellow
+						}
+						return lb.Layout(gtx)
+					}),
+					column(80, plural(info.Users, "request")),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						ib := widgets.IconButton{
+							Icon:      widgets.CloseIcon,
+							Size:      unit.Dp(20),
+							Color:     theme.TextColor,
+							Clickable: &r.closeButton,
+						}
+
+						ib.OnClick = func() {
+							if v.onClose != nil {
+								v.onClose(info.ID)
+							}
+						}
+
+						return ib.Layout(gtx, theme)
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if info.Error == "" {
+					return layout.Dimensions{}
+				}
+
+				lb := material.Label(theme.Material(), unit.Sp(12), info.Error)
+				lb.Color = chapartheme.LightRed
+				return lb.Layout(gtx)
+			}),
+		)
+	})
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return strconv.Itoa(n) + " " + word + "s"
+}
+
+func (v *View) Layout(gtx layout.Context, theme *chapartheme.Theme) layout.Dimensions {
+	v.mx.Lock()
+	rows := v.rows
+	v.mx.Unlock()
+
+	return layout.Inset{Top: unit.Dp(30), Left: unit.Dp(50), Right: unit.Dp(50)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle, Spacing: layout.SpaceEnd}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				lb := material.Label(theme.Material(), unit.Sp(18), "Tunnels")
+				lb.Font.Weight = font.Bold
+				return lb.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return material.Label(theme.Material(), theme.TextSize, description).Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(15)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(rows) == 0 {
+					return material.Label(theme.Material(), theme.TextSize, "No open tunnels").Layout(gtx)
+				}
+
+				return material.List(theme.Material(), v.list).Layout(gtx, len(rows), func(gtx layout.Context, i int) layout.Dimensions {
+					return v.tunnelLayout(gtx, theme, rows[i])
+				})
+			}),
+		)
+	})
+}
@@ -161,6 +161,10 @@ func (c *DropDown) SetOptions(options ...*DropDownOption) {
 	c.menuInit = true
 }
 
+func (c *DropDown) Options() []*DropDownOption {
+	return c.options
+}
+
 func (c *DropDown) GetSelected() *DropDownOption {
 	return c.options[c.selectedOptionIndex]
 }
@@ -100,6 +100,11 @@ var ForwardIcon *widget.Icon = func() *widget.Icon {
 	return icon
 }()
 
+var BackIcon *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.NavigationArrowBack)
+	return icon
+}()
+
 var ExpandIcon *widget.Icon = func() *widget.Icon {
 	icon, _ := widget.NewIcon(icons.NavigationExpandMore)
 	return icon
@@ -149,3 +154,28 @@ var WorkspacesIcon *widget.Icon = func() *widget.Icon {
 	icon, _ := widget.NewIcon(icons.NavigationApps)
 	return icon
 }()
+
+var CookieIcon *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.SocialCake)
+	return icon
+}()
+
+var GlobalsIcon *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.SocialPublic)
+	return icon
+}()
+
+var RefreshIcon *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.NavigationRefresh)
+	return icon
+}()
+
+var PlayIcon *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.AVPlayArrow)
+	return icon
+}()
+
+var StopIcon *widget.Icon = func() *widget.Icon {
+	icon, _ := widget.NewIcon(icons.AVStop)
+	return icon
+}()